@@ -1,15 +1,11 @@
 package account_def
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/common"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/account"
 	"github.com/fletaio/core/amount"
 	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/errs"
 )
 
 func init() {
@@ -23,14 +19,14 @@ func init() {
 	}, func(loader data.Loader, a account.Account, signers []common.PublicHash) error {
 		acc := a.(*LockedAccount)
 		if acc.UnlockHeight > loader.TargetHeight() {
-			return ErrLockedAccount
+			return errs.WrapField(ErrLockedAccount, "unlock_height", loader.TargetHeight(), acc.UnlockHeight)
 		}
 		if len(signers) != 1 {
-			return ErrInvalidSignerCount
+			return errs.WrapField(ErrInvalidSignerCount, "signers", 1, len(signers))
 		}
 		signer := signers[0]
 		if !acc.KeyHash.Equal(signer) {
-			return ErrInvalidAccountSigner
+			return errs.WrapField(ErrInvalidAccountSigner, "signers", acc.KeyHash, signer)
 		}
 		return nil
 	})
@@ -40,8 +36,8 @@ func init() {
 // It is used to prevent transactions from the locked account until the unlock height
 type LockedAccount struct {
 	account.Base
-	UnlockHeight uint32
-	KeyHash      common.PublicHash
+	UnlockHeight uint32            `codec:"unlock_height"`
+	KeyHash      common.PublicHash `codec:"key_hash"`
 }
 
 // Clone returns the clonend value of it
@@ -50,86 +46,10 @@ func (acc *LockedAccount) Clone() account.Account {
 		Base: account.Base{
 			Type_:    acc.Type_,
 			Address_: acc.Address_,
+			Name_:    acc.Name_,
 			Balance_: acc.Balance(),
 		},
-		KeyHash: acc.KeyHash.Clone(),
-	}
-}
-
-// WriteTo is a serialization function
-func (acc *LockedAccount) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := acc.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteUint32(w, acc.UnlockHeight); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := acc.KeyHash.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	return wrote, nil
-}
-
-// ReadFrom is a deserialization function
-func (acc *LockedAccount) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := acc.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if v, n, err := util.ReadUint32(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		acc.UnlockHeight = v
-	}
-	if n, err := acc.KeyHash.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	return read, nil
-}
-
-// MarshalJSON is a marshaler function
-func (acc *LockedAccount) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"address":`)
-	if bs, err := acc.Address_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(acc.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"unlock_height":`)
-	if bs, err := json.Marshal(acc.UnlockHeight); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"key_hash":`)
-	if bs, err := acc.KeyHash.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+		UnlockHeight: acc.UnlockHeight,
+		KeyHash:      acc.KeyHash.Clone(),
 	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
 }
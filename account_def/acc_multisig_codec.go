@@ -0,0 +1,171 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package account_def
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/json_util"
+)
+
+// WriteTo is a serialization function
+func (acc *MultiSigAccount) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := acc.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint8(w, acc.Required); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckItemCount("MultiSigAccount.KeyHashes", uint64(len(acc.KeyHashes)), 255); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteUint8(w, uint8(len(acc.KeyHashes))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+		for _, v := range acc.KeyHashes {
+			if n, err := v.WriteTo(w); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (acc *MultiSigAccount) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := acc.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		acc.Required = v
+	}
+	if Len, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		acc.KeyHashes = make([]common.PublicHash, 0, Len)
+		for i := 0; i < int(Len); i++ {
+			var v common.PublicHash
+			if n, err := v.ReadFrom(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				acc.KeyHashes = append(acc.KeyHashes, v)
+			}
+		}
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (acc *MultiSigAccount) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"address":`)
+	if bs, err := acc.Address_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(acc.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(acc.Name_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"balance":`)
+	if bs, err := acc.Balance_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"required":`)
+	if bs, err := json.Marshal(acc.Required); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"key_hashes":`)
+	buffer.WriteString(`[`)
+	for i, v := range acc.KeyHashes {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := v.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (acc *MultiSigAccount) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Address   string          `json:"address"`
+		Type      account.Type    `json:"type"`
+		Name      string          `json:"name"`
+		Balance   json.RawMessage `json:"balance"`
+		Required  uint8           `json:"required"`
+		KeyHashes []string        `json:"key_hashes"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	if addr, err := common.ParseAddress(v.Address); err != nil {
+		return err
+	} else {
+		acc.Address_ = addr
+	}
+	acc.Type_ = v.Type
+	acc.Name_ = v.Name
+	if am, err := json_util.ParseAmount(v.Balance); err != nil {
+		return err
+	} else {
+		acc.Balance_ = am
+	}
+	acc.Required = v.Required
+	if err := codec.CheckItemCount("MultiSigAccount.KeyHashes", uint64(len(v.KeyHashes)), 255); err != nil {
+		return err
+	}
+	if list, err := json_util.ParsePublicHashes(v.KeyHashes); err != nil {
+		return err
+	} else {
+		acc.KeyHashes = list
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package account_def
+
+import (
+	"bytes"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/errs"
+)
+
+var (
+	tagPolicy        = []byte("fleta.Policy")
+	tagPendingPolicy = []byte("fleta.PendingPolicy")
+	tagPolicySpent   = []byte("fleta.PolicySpent")
+)
+
+// UTXOAddress is the destination of the outputs of UTXOs in the policy check
+// The public hashes of the outputs cannot be matched with the allowed addresses, so a policy that has allowed addresses
+// rejects the transactions that make UTXOs unless UTXOAddress is one of them
+var UTXOAddress = common.Address{}
+
+// Policy is a spending policy that is attached to the account as account data
+// It limits the amount that is spent in a window of blocks, destinations and transaction types
+// It is checked for every transaction that is sent from the account; the UTXO transactions are not sent from an account
+// The fee of the transaction is not counted against the spend limit, only the amount that leaves the account is
+// Changing the attached policy requires the signature of the admin key and takes effect after ChangeDelay blocks
+type Policy struct {
+	AdminKeyHash     common.PublicHash  `codec:"admin_key_hash"`
+	ChangeDelay      uint32             `codec:"change_delay"`
+	WindowSize       uint32             `codec:"window_size"`
+	SpendLimit       *amount.Amount     `codec:"spend_limit"` // nil or zero means no limit
+	AllowedAddresses []common.Address   `codec:"allowed_addresses"`
+	AllowedTypes     []transaction.Type `codec:"allowed_types"`
+}
+
+// NewPolicy returns a Policy
+func NewPolicy() *Policy {
+	return &Policy{
+		SpendLimit:       amount.NewCoinAmount(0, 0),
+		AllowedAddresses: []common.Address{},
+		AllowedTypes:     []transaction.Type{},
+	}
+}
+
+// Clone returns the clonend value of it
+func (p *Policy) Clone() *Policy {
+	return &Policy{
+		AdminKeyHash:     p.AdminKeyHash.Clone(),
+		ChangeDelay:      p.ChangeDelay,
+		WindowSize:       p.WindowSize,
+		SpendLimit:       p.SpendLimit.Clone(),
+		AllowedAddresses: append([]common.Address{}, p.AllowedAddresses...),
+		AllowedTypes:     append([]transaction.Type{}, p.AllowedTypes...),
+	}
+}
+
+// IsValid returns true when the policy can be attached
+func (p *Policy) IsValid() bool {
+	if p.AdminKeyHash == (common.PublicHash{}) {
+		return false
+	}
+	if p.hasSpendLimit() && p.WindowSize == 0 {
+		return false
+	}
+	if len(p.AllowedAddresses) > 255 || len(p.AllowedTypes) > 255 {
+		return false
+	}
+	return true
+}
+
+func (p *Policy) hasSpendLimit() bool {
+	return p.SpendLimit != nil && !p.SpendLimit.Equal(amount.NewCoinAmount(0, 0))
+}
+
+// IsAllowedType returns true when the transaction type is allowed by the policy
+func (p *Policy) IsAllowedType(t transaction.Type) bool {
+	if len(p.AllowedTypes) == 0 {
+		return true
+	}
+	for _, v := range p.AllowedTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAllowedAddress returns true when the destination address is allowed by the policy
+func (p *Policy) IsAllowedAddress(addr common.Address) bool {
+	if len(p.AllowedAddresses) == 0 {
+		return true
+	}
+	for _, v := range p.AllowedAddresses {
+		if v == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func readPolicy(bs []byte) (*Policy, error) {
+	p := NewPolicy()
+	if _, err := p.ReadFrom(bytes.NewReader(bs)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func policyBytes(p *Policy) ([]byte, error) {
+	var buffer bytes.Buffer
+	if _, err := p.WriteTo(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// PendingPolicy returns the policy that is waiting for its effective height or nil if there is no pending policy
+func PendingPolicy(loader data.Loader, addr common.Address) (*Policy, uint32, error) {
+	bs := loader.AccountData(addr, tagPendingPolicy)
+	if len(bs) < 4 {
+		return nil, 0, nil
+	}
+	r := bytes.NewReader(bs)
+	EffectiveHeight, _, err := util.ReadUint32(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	p := NewPolicy()
+	if _, err := p.ReadFrom(r); err != nil {
+		return nil, 0, err
+	}
+	return p, EffectiveHeight, nil
+}
+
+// PolicyOf returns the policy that is effective at the target height or nil if the account has no policy
+func PolicyOf(loader data.Loader, addr common.Address) (*Policy, error) {
+	if p, EffectiveHeight, err := PendingPolicy(loader, addr); err != nil {
+		return nil, err
+	} else if p != nil && EffectiveHeight <= loader.TargetHeight() {
+		return p, nil
+	}
+	bs := loader.AccountData(addr, tagPolicy)
+	if len(bs) == 0 {
+		return nil, nil
+	}
+	return readPolicy(bs)
+}
+
+// SetPolicy attaches the policy to the account from the effective height
+// The policy is attached immediately when the effective height is not over the target height
+func SetPolicy(ctx *data.Context, addr common.Address, p *Policy, EffectiveHeight uint32) error {
+	if err := promotePolicy(ctx, addr); err != nil {
+		return err
+	}
+	bs, err := policyBytes(p)
+	if err != nil {
+		return err
+	}
+	if EffectiveHeight <= ctx.TargetHeight() {
+		ctx.SetAccountData(addr, tagPolicy, bs)
+		ctx.SetAccountData(addr, tagPendingPolicy, nil)
+		return nil
+	}
+	var buffer bytes.Buffer
+	if _, err := util.WriteUint32(&buffer, EffectiveHeight); err != nil {
+		return err
+	}
+	buffer.Write(bs)
+	ctx.SetAccountData(addr, tagPendingPolicy, buffer.Bytes())
+	return nil
+}
+
+func promotePolicy(ctx *data.Context, addr common.Address) error {
+	p, EffectiveHeight, err := PendingPolicy(ctx, addr)
+	if err != nil {
+		return err
+	}
+	if p == nil || EffectiveHeight > ctx.TargetHeight() {
+		return nil
+	}
+	bs, err := policyBytes(p)
+	if err != nil {
+		return err
+	}
+	ctx.SetAccountData(addr, tagPolicy, bs)
+	ctx.SetAccountData(addr, tagPendingPolicy, nil)
+	ctx.SetAccountData(addr, tagPolicySpent, nil)
+	return nil
+}
+
+// spentInWindow returns the amount that is spent in the window of the target height
+func spentInWindow(loader data.Loader, addr common.Address, p *Policy) (uint32, *amount.Amount, error) {
+	WindowStart := loader.TargetHeight() - loader.TargetHeight()%p.WindowSize
+	bs := loader.AccountData(addr, tagPolicySpent)
+	if len(bs) == 0 {
+		return WindowStart, amount.NewCoinAmount(0, 0), nil
+	}
+	r := bytes.NewReader(bs)
+	Start, _, err := util.ReadUint32(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if Start != WindowStart {
+		return WindowStart, amount.NewCoinAmount(0, 0), nil
+	}
+	Spent := amount.NewCoinAmount(0, 0)
+	if _, err := Spent.ReadFrom(r); err != nil {
+		return 0, nil, err
+	}
+	return WindowStart, Spent, nil
+}
+
+// CheckPolicy returns an error when the transaction of the account is not allowed by the effective policy
+func CheckPolicy(loader data.Loader, addr common.Address, t transaction.Type, tos []common.Address, spend *amount.Amount) error {
+	p, err := PolicyOf(loader, addr)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return nil
+	}
+	if !p.IsAllowedType(t) {
+		return errs.Wrap(ErrNotAllowedTransactionType, t, "type")
+	}
+	for _, to := range tos {
+		if !p.IsAllowedAddress(to) {
+			return errs.WrapValue(ErrNotAllowedDestination, t, "to", nil, to)
+		}
+	}
+	if p.hasSpendLimit() && spend != nil {
+		_, Spent, err := spentInWindow(loader, addr, p)
+		if err != nil {
+			return err
+		}
+		if p.SpendLimit.Less(Spent.Add(spend)) {
+			return errs.WrapValue(ErrExceedSpendLimit, t, "amount", p.SpendLimit, Spent.Add(spend))
+		}
+	}
+	return nil
+}
+
+// senderLoader carries the transaction of the sender account to the account validator
+type senderLoader struct {
+	data.Loader
+	t     transaction.Type
+	tos   []common.Address
+	spend *amount.Amount
+}
+
+// ValidateSender validates the signers of the account that sends the transaction and checks the transaction by the policy of the account
+// The validators of the transactions that are sent from an account should use it instead of Accounter().Validate
+// because the account validator rejects the account that has a policy when it does not know the transaction
+func ValidateSender(loader data.Loader, acc account.Account, signers []common.PublicHash, t transaction.Type, tos []common.Address, spend *amount.Amount) error {
+	return loader.Accounter().Validate(&senderLoader{
+		Loader: loader,
+		t:      t,
+		tos:    tos,
+		spend:  spend,
+	}, acc, signers)
+}
+
+// checkSenderPolicy checks the transaction that is given by ValidateSender with the policy of the account
+func checkSenderPolicy(loader data.Loader, addr common.Address) error {
+	if sl, is := loader.(*senderLoader); is {
+		return CheckPolicy(sl.Loader, addr, sl.t, sl.tos, sl.spend)
+	}
+	if p, err := PolicyOf(loader, addr); err != nil {
+		return err
+	} else if p != nil {
+		return errs.WrapField(ErrNotAllowedTransactionType, "type", "validated by ValidateSender", nil)
+	}
+	return nil
+}
+
+// ApplyPolicy checks the transaction of the account by the effective policy and records the spent amount of the window
+func ApplyPolicy(ctx *data.Context, addr common.Address, t transaction.Type, tos []common.Address, spend *amount.Amount) error {
+	if err := promotePolicy(ctx, addr); err != nil {
+		return err
+	}
+	if err := CheckPolicy(ctx, addr, t, tos, spend); err != nil {
+		return err
+	}
+	p, err := PolicyOf(ctx, addr)
+	if err != nil {
+		return err
+	}
+	if p == nil || !p.hasSpendLimit() || spend == nil {
+		return nil
+	}
+	WindowStart, Spent, err := spentInWindow(ctx, addr, p)
+	if err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	if _, err := util.WriteUint32(&buffer, WindowStart); err != nil {
+		return err
+	}
+	if _, err := Spent.Add(spend).WriteTo(&buffer); err != nil {
+		return err
+	}
+	ctx.SetAccountData(addr, tagPolicySpent, buffer.Bytes())
+	return nil
+}
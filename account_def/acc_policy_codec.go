@@ -0,0 +1,233 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package account_def
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/json_util"
+)
+
+// WriteTo is a serialization function
+func (p *Policy) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := p.AdminKeyHash.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, p.ChangeDelay); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, p.WindowSize); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := p.SpendLimit.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckItemCount("Policy.AllowedAddresses", uint64(len(p.AllowedAddresses)), 255); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteUint8(w, uint8(len(p.AllowedAddresses))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+		for _, v := range p.AllowedAddresses {
+			if n, err := v.WriteTo(w); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	if err := codec.CheckItemCount("Policy.AllowedTypes", uint64(len(p.AllowedTypes)), 255); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteUint8(w, uint8(len(p.AllowedTypes))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+		for _, v := range p.AllowedTypes {
+			if n, err := util.WriteUint8(w, uint8(v)); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (p *Policy) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := p.AdminKeyHash.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		p.ChangeDelay = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		p.WindowSize = v
+	}
+	p.SpendLimit = amount.NewCoinAmount(0, 0)
+	if n, err := p.SpendLimit.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if Len, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		p.AllowedAddresses = make([]common.Address, 0, Len)
+		for i := 0; i < int(Len); i++ {
+			var v common.Address
+			if n, err := v.ReadFrom(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				p.AllowedAddresses = append(p.AllowedAddresses, v)
+			}
+		}
+	}
+	if Len, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		p.AllowedTypes = make([]transaction.Type, 0, Len)
+		for i := 0; i < int(Len); i++ {
+			if v, n, err := util.ReadUint8(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				p.AllowedTypes = append(p.AllowedTypes, transaction.Type(v))
+			}
+		}
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (p *Policy) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"admin_key_hash":`)
+	if bs, err := p.AdminKeyHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"change_delay":`)
+	if bs, err := json.Marshal(p.ChangeDelay); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"window_size":`)
+	if bs, err := json.Marshal(p.WindowSize); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"spend_limit":`)
+	if bs, err := p.SpendLimit.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"allowed_addresses":`)
+	buffer.WriteString(`[`)
+	for i, v := range p.AllowedAddresses {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := v.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"allowed_types":`)
+	buffer.WriteString(`[`)
+	for i, v := range p.AllowedTypes {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := json.Marshal(v); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (p *Policy) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		AdminKeyHash     string             `json:"admin_key_hash"`
+		ChangeDelay      uint32             `json:"change_delay"`
+		WindowSize       uint32             `json:"window_size"`
+		SpendLimit       json.RawMessage    `json:"spend_limit"`
+		AllowedAddresses []string           `json:"allowed_addresses"`
+		AllowedTypes     []transaction.Type `json:"allowed_types"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	if pubhash, err := common.ParsePublicHash(v.AdminKeyHash); err != nil {
+		return err
+	} else {
+		p.AdminKeyHash = pubhash
+	}
+	p.ChangeDelay = v.ChangeDelay
+	p.WindowSize = v.WindowSize
+	if am, err := json_util.ParseAmount(v.SpendLimit); err != nil {
+		return err
+	} else {
+		p.SpendLimit = am
+	}
+	if err := codec.CheckItemCount("Policy.AllowedAddresses", uint64(len(v.AllowedAddresses)), 255); err != nil {
+		return err
+	}
+	if list, err := json_util.ParseAddresses(v.AllowedAddresses); err != nil {
+		return err
+	} else {
+		p.AllowedAddresses = list
+	}
+	if err := codec.CheckItemCount("Policy.AllowedTypes", uint64(len(v.AllowedTypes)), 255); err != nil {
+		return err
+	}
+	p.AllowedTypes = v.AllowedTypes
+	return nil
+}
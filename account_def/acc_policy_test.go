@@ -0,0 +1,199 @@
+package account_def
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func newTestContext(height uint32) (*test_util.Loader, *data.Context) {
+	loader := test_util.NewLoader(common.NewCoordinate(0, 0))
+	loader.Height = height
+	return loader, data.NewContext(loader)
+}
+
+func TestCheckPolicy(t *testing.T) {
+	loader, ctx := newTestContext(10)
+	addr := test_util.Address(1)
+	allowed := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	other := test_util.Address(2)
+
+	// an account without a policy is not limited
+	if err := CheckPolicy(ctx, addr, 11, []common.Address{other}, amount.NewCoinAmount(1000, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetPolicy(ctx, addr, testPolicy(), loader.Height); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		t     transaction.Type
+		tos   []common.Address
+		spend *amount.Amount
+		err   error
+	}{
+		{"allowed", 10, []common.Address{allowed}, amount.NewCoinAmount(10, 0), nil},
+		{"no destination", 10, nil, amount.NewCoinAmount(1, 0), nil},
+		{"no spend", 10, []common.Address{allowed}, nil, nil},
+		{"not allowed type", 11, []common.Address{allowed}, amount.NewCoinAmount(1, 0), ErrNotAllowedTransactionType},
+		{"not allowed destination", 10, []common.Address{allowed, other}, amount.NewCoinAmount(1, 0), ErrNotAllowedDestination},
+		{"utxo outputs", 10, []common.Address{UTXOAddress}, amount.NewCoinAmount(1, 0), ErrNotAllowedDestination},
+		{"exceed spend limit", 10, []common.Address{allowed}, amount.NewCoinAmount(10, 1), ErrExceedSpendLimit},
+	}
+	for _, tt := range tests {
+		err := CheckPolicy(ctx, addr, tt.t, tt.tos, tt.spend)
+		if tt.err == nil && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		} else if tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected %v but %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestApplyPolicy(t *testing.T) {
+	loader, ctx := newTestContext(10)
+	addr := test_util.Address(1)
+	allowed := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	if err := SetPolicy(ctx, addr, testPolicy(), loader.Height); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ApplyPolicy(ctx, addr, 10, []common.Address{allowed}, amount.NewCoinAmount(6, 0)); err != nil {
+		t.Fatal(err)
+	}
+	// the spent amount of the window is recorded
+	if err := CheckPolicy(ctx, addr, 10, []common.Address{allowed}, amount.NewCoinAmount(5, 0)); !errors.Is(err, ErrExceedSpendLimit) {
+		t.Fatalf("expected %v but %v", ErrExceedSpendLimit, err)
+	}
+	// a rejected spend is not recorded
+	if err := ApplyPolicy(ctx, addr, 10, []common.Address{allowed}, amount.NewCoinAmount(5, 0)); !errors.Is(err, ErrExceedSpendLimit) {
+		t.Fatalf("expected %v but %v", ErrExceedSpendLimit, err)
+	}
+	if err := ApplyPolicy(ctx, addr, 10, []common.Address{allowed}, amount.NewCoinAmount(4, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyPolicy(ctx, addr, 10, []common.Address{allowed}, amount.NewCoinAmount(0, 1)); !errors.Is(err, ErrExceedSpendLimit) {
+		t.Fatalf("expected %v but %v", ErrExceedSpendLimit, err)
+	}
+
+	// the limit is renewed in the next window
+	loader.Height = 20
+	if err := ApplyPolicy(ctx, addr, 10, []common.Address{allowed}, amount.NewCoinAmount(10, 0)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	loader, ctx := newTestContext(10)
+	addr := test_util.Address(1)
+	allowed := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	if err := SetPolicy(ctx, addr, testPolicy(), loader.Height); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyPolicy(ctx, addr, 10, []common.Address{allowed}, amount.NewCoinAmount(10, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	next := testPolicy()
+	next.SpendLimit = amount.NewCoinAmount(0, 0)
+	next.AllowedTypes = []transaction.Type{}
+	if err := SetPolicy(ctx, addr, next, 15); err != nil {
+		t.Fatal(err)
+	}
+
+	// the current policy is effective before the effective height
+	if p, EffectiveHeight, err := PendingPolicy(ctx, addr); err != nil {
+		t.Fatal(err)
+	} else if p == nil || EffectiveHeight != 15 {
+		t.Fatalf("expected the pending policy at 15 but %v at %d", p, EffectiveHeight)
+	}
+	if err := CheckPolicy(ctx, addr, 11, []common.Address{allowed}, nil); !errors.Is(err, ErrNotAllowedTransactionType) {
+		t.Fatalf("expected %v but %v", ErrNotAllowedTransactionType, err)
+	}
+	loader.Height = 14
+	if err := CheckPolicy(ctx, addr, 10, []common.Address{allowed}, amount.NewCoinAmount(1, 0)); !errors.Is(err, ErrExceedSpendLimit) {
+		t.Fatalf("expected %v but %v", ErrExceedSpendLimit, err)
+	}
+
+	// the pending policy takes effect at the effective height and replaces the current one when it is applied
+	loader.Height = 15
+	if err := ApplyPolicy(ctx, addr, 11, []common.Address{allowed}, amount.NewCoinAmount(100, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if p, _, err := PendingPolicy(ctx, addr); err != nil || p != nil {
+		t.Fatalf("expected no pending policy but %v, %v", p, err)
+	}
+	if p, err := PolicyOf(ctx, addr); err != nil {
+		t.Fatal(err)
+	} else if len(p.AllowedTypes) != 0 || p.hasSpendLimit() {
+		t.Fatalf("unexpected policy %+v", p)
+	}
+}
+
+func TestValidateSender(t *testing.T) {
+	loader, ctx := newTestContext(10)
+	loader.Act = test_util.Accounter(t, testAccountTypes)
+	allowed := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	other := test_util.Address(2)
+
+	single := &SingleAccount{Base: testAccountBase(10), KeyHash: test_util.PublicHash(1)}
+	single.Address_ = test_util.Address(11)
+	multi := &MultiSigAccount{Base: testAccountBase(11), Required: 2, KeyHashes: []common.PublicHash{test_util.PublicHash(2), test_util.PublicHash(3), test_util.PublicHash(4)}}
+	multi.Address_ = test_util.Address(12)
+	for _, v := range []struct {
+		acc     account.Account
+		signers []common.PublicHash
+	}{
+		{single, []common.PublicHash{test_util.PublicHash(1)}},
+		{multi, []common.PublicHash{test_util.PublicHash(2), test_util.PublicHash(3)}},
+	} {
+		acc, signers := v.acc, v.signers
+		wrong := make([]common.PublicHash, len(signers))
+		for i := range wrong {
+			wrong[i] = test_util.PublicHash(byte(20 + i))
+		}
+		// an account without a policy is valid with or without the transaction
+		if err := loader.Act.Validate(ctx, acc, signers); err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidateSender(ctx, acc, signers, 11, []common.Address{other}, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := SetPolicy(ctx, acc.Address(), testPolicy(), 0); err != nil {
+			t.Fatal(err)
+		}
+		// the validator of a transaction that does not give the transaction to the policy check is rejected
+		if err := loader.Act.Validate(ctx, acc, signers); !errors.Is(err, ErrNotAllowedTransactionType) {
+			t.Fatalf("expected %v but %v", ErrNotAllowedTransactionType, err)
+		}
+		if err := ValidateSender(ctx, acc, signers, 10, []common.Address{allowed}, amount.NewCoinAmount(1, 0)); err != nil {
+			t.Fatal(err)
+		}
+		tests := []struct {
+			name     string
+			signers  []common.PublicHash
+			t        transaction.Type
+			tos      []common.Address
+			spend    *amount.Amount
+			expected error
+		}{
+			{"type", signers, 11, []common.Address{allowed}, nil, ErrNotAllowedTransactionType},
+			{"destination", signers, 10, []common.Address{other}, nil, ErrNotAllowedDestination},
+			{"spend limit", signers, 10, []common.Address{allowed}, amount.NewCoinAmount(11, 0), ErrExceedSpendLimit},
+			{"signer", wrong, 10, []common.Address{allowed}, nil, ErrInvalidAccountSigner},
+		}
+		for _, tt := range tests {
+			if err := ValidateSender(ctx, acc, tt.signers, tt.t, tt.tos, tt.spend); !errors.Is(err, tt.expected) {
+				t.Errorf("%s: expected %v but %v", tt.name, tt.expected, err)
+			}
+		}
+	}
+}
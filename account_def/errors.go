@@ -1,12 +1,15 @@
 package account_def
 
 import (
-	"errors"
+	"github.com/fletaio/extension/errs"
 )
 
 // account_def errors
 var (
-	ErrInvalidSignerCount   = errors.New("invalid signer count")
-	ErrInvalidAccountSigner = errors.New("invalid account signer")
-	ErrLockedAccount        = errors.New("locked account")
+	ErrInvalidSignerCount        = errs.ErrInvalidSignerCount
+	ErrInvalidAccountSigner      = errs.ErrInvalidAccountSigner
+	ErrLockedAccount             = errs.ErrLockedAccount
+	ErrNotAllowedTransactionType = errs.ErrNotAllowedTransactionType
+	ErrNotAllowedDestination     = errs.ErrNotAllowedDestination
+	ErrExceedSpendLimit          = errs.ErrExceedSpendLimit
 )
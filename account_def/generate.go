@@ -0,0 +1,3 @@
+package account_def
+
+//go:generate go run ../codec/codecgen -type=SingleAccount,MultiSigAccount,LockedAccount,Policy
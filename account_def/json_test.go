@@ -0,0 +1,23 @@
+package account_def
+
+import (
+	"testing"
+
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func TestAccountJSON(t *testing.T) {
+	act := test_util.Accounter(t, testAccountTypes)
+	for _, src := range testAccounts() {
+		dst, err := act.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.JSONRoundTrip(t, src.(test_util.JSONValue), dst.(test_util.JSONValue))
+	}
+}
+
+func TestPolicyJSON(t *testing.T) {
+	test_util.JSONRoundTrip(t, testPolicy(), NewPolicy())
+	test_util.JSONRoundTrip(t, NewPolicy(), NewPolicy())
+}
@@ -0,0 +1,119 @@
+package account_def
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+var testAccountTypes = map[string]account.Type{
+	"fleta.SingleAccount":   10,
+	"fleta.MultiSigAccount": 11,
+	"fleta.LockedAccount":   19,
+}
+
+func testAccountBase(t account.Type) account.Base {
+	return account.Base{
+		Type_:    t,
+		Address_: common.NewAddress(common.NewCoordinate(1, 2), 0),
+		Name_:    "testaccount",
+		Balance_: amount.NewCoinAmount(100, 250000000000000000),
+	}
+}
+
+func testAccounts() []account.Account {
+	return []account.Account{
+		&SingleAccount{Base: testAccountBase(10), KeyHash: test_util.PublicHash(1)},
+		&MultiSigAccount{Base: testAccountBase(11), Required: 2, KeyHashes: []common.PublicHash{test_util.PublicHash(2), test_util.PublicHash(3), test_util.PublicHash(4)}},
+		&LockedAccount{Base: testAccountBase(19), UnlockHeight: 1000, KeyHash: test_util.PublicHash(5)},
+	}
+}
+
+func testPolicy() *Policy {
+	policy := NewPolicy()
+	policy.AdminKeyHash = test_util.PublicHash(9)
+	policy.ChangeDelay = 100
+	policy.WindowSize = 10
+	policy.SpendLimit = amount.NewCoinAmount(10, 0)
+	policy.AllowedAddresses = []common.Address{common.NewAddress(common.NewCoordinate(3, 4), 0)}
+	policy.AllowedTypes = []transaction.Type{10}
+	return policy
+}
+
+func TestAccountBinary(t *testing.T) {
+	act := test_util.Accounter(t, testAccountTypes)
+	for _, src := range testAccounts() {
+		dst, err := act.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		bs := test_util.BinaryRoundTrip(t, src, dst)
+
+		var cloned bytes.Buffer
+		if _, err := src.Clone().WriteTo(&cloned); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bs, cloned.Bytes()) {
+			t.Errorf("%T: clone mismatch\n got %x\nwant %x", src, cloned.Bytes(), bs)
+		}
+	}
+}
+
+func TestPolicyBinary(t *testing.T) {
+	policy := testPolicy()
+	bs := test_util.BinaryRoundTrip(t, policy, NewPolicy())
+	test_util.BinaryRoundTrip(t, NewPolicy(), NewPolicy())
+
+	var cloned bytes.Buffer
+	if _, err := policy.Clone().WriteTo(&cloned); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bs, cloned.Bytes()) {
+		t.Errorf("clone mismatch\n got %x\nwant %x", cloned.Bytes(), bs)
+	}
+}
+
+func FuzzAccountReadFrom(f *testing.F) {
+	act := test_util.Accounter(f, testAccountTypes)
+	for _, src := range testAccounts() {
+		var buffer bytes.Buffer
+		if _, err := src.WriteTo(&buffer); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(uint8(src.Type()), buffer.Bytes())
+	}
+	f.Fuzz(func(t *testing.T, Type uint8, bs []byte) {
+		acc, err := act.NewByType(account.Type(Type))
+		if err != nil {
+			return
+		}
+		if _, err := acc.ReadFrom(bytes.NewReader(bs)); err != nil {
+			return
+		}
+		dst, err := act.NewByType(account.Type(Type))
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.BinaryRoundTrip(t, acc, dst)
+	})
+}
+
+func FuzzPolicyReadFrom(f *testing.F) {
+	var buffer bytes.Buffer
+	if _, err := testPolicy().WriteTo(&buffer); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buffer.Bytes())
+	f.Fuzz(func(t *testing.T, bs []byte) {
+		policy := NewPolicy()
+		if _, err := policy.ReadFrom(bytes.NewReader(bs)); err != nil {
+			return
+		}
+		test_util.BinaryRoundTrip(t, policy, NewPolicy())
+	})
+}
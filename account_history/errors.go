@@ -0,0 +1,18 @@
+package account_history
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// account history error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeInvalidHistoryHeight  errs.Code = 2301
+	CodeCorruptedHistoryStore errs.Code = 2302
+)
+
+// account history errors
+var (
+	ErrInvalidHistoryHeight  = errs.New(CodeInvalidHistoryHeight, "invalid history height")
+	ErrCorruptedHistoryStore = errs.New(CodeCorruptedHistoryStore, "corrupted history store")
+)
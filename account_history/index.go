@@ -0,0 +1,252 @@
+// Package account_history indexes the transactions of the chain by the addresses and the public hashes that they touch.
+//
+// The index is kept in an embedded key-value store and is updated block by block, so the transactions of an address are listed
+// in the order of their coordinates without scanning the blocks. The Indexer is added to the kernel as an event handler to follow the chain
+// and the index can be rebuilt from the blocks of the kernel at any time.
+package account_history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/store"
+)
+
+// key tags of the store
+var (
+	tagHeight      = []byte{0x01}
+	tagDestination = []byte{0x02} // the addresses of the account names of the destinations that are resolved by the execution
+	tagPayment     = []byte{0x03} // the payments of the standing orders that are made when the blocks are processed
+	tagAddress     = []byte{0x10}
+	tagPublicHash  = []byte{0x11}
+)
+
+// Entry is a transaction in the history
+// A payment of a standing order is listed after the transactions of the block that makes it with the type of the transaction that registered the order
+type Entry struct {
+	Coord *common.Coordinate // the height of the block and the index of the transaction in the block
+	Type  transaction.Type
+	Order *common.Coordinate // the coordinate of the transaction that registered the standing order of a payment, nil for a transaction
+}
+
+// Index is the history of the transactions by the addresses and the public hashes
+type Index struct {
+	sync.Mutex
+	st store.Store
+}
+
+// NewIndex returns the index that is kept in the store
+func NewIndex(st store.Store) *Index {
+	return &Index{
+		st: st,
+	}
+}
+
+// Height returns the height of the last indexed block
+func (idx *Index) Height() (uint32, error) {
+	bs, err := idx.st.Get(tagHeight)
+	if err != nil {
+		return 0, err
+	}
+	if bs == nil {
+		return 0, nil
+	}
+	if len(bs) != 4 {
+		return 0, errs.WrapField(ErrCorruptedHistoryStore, "height", 4, len(bs))
+	}
+	return binary.BigEndian.Uint32(bs), nil
+}
+
+// IndexBlock adds the transactions of the block that should be the next of the last indexed block
+// The events are emitted by the execution of the block and give the addresses of the account names of the destinations
+// and the payments of the standing orders, which are recorded so the block is indexed in the same way without the events later
+// A block that is already indexed is ignored
+func (idx *Index) IndexBlock(b *block.Block, events []event.Event) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	height, err := idx.Height()
+	if err != nil {
+		return err
+	}
+	if b.Header.Height() <= height {
+		return nil
+	}
+	if b.Header.Height() != height+1 {
+		return errs.WrapField(ErrInvalidHistoryHeight, "height", height+1, b.Header.Height())
+	}
+
+	batch := store.NewBatch()
+	for i, tx := range b.Body.Transactions {
+		coord := common.NewCoordinate(b.Header.Height(), uint16(i))
+		var to *common.Address
+		if hasNameDestination(tx) {
+			if addr, has := transferredTo(events, coord); has {
+				batch.Set(entryKey(tagDestination, nil, coord), addr[:])
+				to = &addr
+			} else if to, err = idx.recordedDestination(coord); err != nil {
+				return err
+			}
+		}
+		addrs, pubhashes := Involved(coord, tx, to)
+		for _, addr := range addrs {
+			batch.Set(entryKey(tagAddress, addr[:], coord), []byte{byte(tx.Type())})
+		}
+		for _, pubhash := range pubhashes {
+			batch.Set(entryKey(tagPublicHash, pubhash[:], coord), []byte{byte(tx.Type())})
+		}
+	}
+
+	payments, err := idx.paymentsOf(b, events)
+	if err != nil {
+		return err
+	}
+	if len(payments) > 0 {
+		for _, p := range payments {
+			batch.Set(entryKey(tagPayment, nil, p.coord), p.bytes())
+		}
+	} else if payments, err = idx.recordedPayments(b.Header.Height()); err != nil {
+		return err
+	}
+	for _, p := range payments {
+		batch.Set(entryKey(tagAddress, p.from[:], p.coord), p.entryValue())
+		batch.Set(entryKey(tagAddress, p.to[:], p.coord), p.entryValue())
+	}
+
+	bs := make([]byte, 4)
+	binary.BigEndian.PutUint32(bs, b.Header.Height())
+	batch.Set(tagHeight, bs)
+	return idx.st.Apply(batch)
+}
+
+// transferredTo returns the receiver of the TransferEvent of the transaction at the coordinate
+func transferredTo(events []event.Event, coord *common.Coordinate) (common.Address, bool) {
+	for _, e := range events {
+		if ev, is := e.(*event_def.TransferEvent); is && ev.Coord().Equal(coord) && ev.To != (common.Address{}) {
+			return ev.To, true
+		}
+	}
+	return common.Address{}, false
+}
+
+// recordedDestination returns nil when the destination of the transaction at the coordinate is not recorded
+func (idx *Index) recordedDestination(coord *common.Coordinate) (*common.Address, error) {
+	bs, err := idx.st.Get(entryKey(tagDestination, nil, coord))
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		return nil, nil
+	}
+	var addr common.Address
+	if len(bs) != len(addr) {
+		return nil, errs.WrapField(ErrCorruptedHistoryStore, "destination", len(addr), len(bs))
+	}
+	copy(addr[:], bs)
+	return &addr, nil
+}
+
+// Sync indexes the blocks of the provider from the next of the last indexed block
+// The account names of the destinations are taken from the addresses that are recorded when the blocks are indexed with their events
+func (idx *Index) Sync(provider kernel.Provider) error {
+	height, err := idx.Height()
+	if err != nil {
+		return err
+	}
+	for h := height + 1; h <= provider.Height(); h++ {
+		b, err := provider.Block(h)
+		if err != nil {
+			return err
+		}
+		if err := idx.IndexBlock(b, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rebuild removes the index and indexes all blocks of the provider
+// The recorded addresses of the account names and the recorded payments are kept, and a destination of an account name that is not recorded is skipped
+// because the name can be owned by another account in the current state
+func (idx *Index) Rebuild(provider kernel.Provider) error {
+	if err := idx.clear(); err != nil {
+		return err
+	}
+	return idx.Sync(provider)
+}
+
+func (idx *Index) clear() error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	batch := store.NewBatch()
+	if err := idx.st.Iterate(nil, false, func(key []byte, value []byte) bool {
+		if !bytes.HasPrefix(key, tagDestination) && !bytes.HasPrefix(key, tagPayment) {
+			batch.Delete(key)
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+	return idx.st.Apply(batch)
+}
+
+// Transactions returns the transactions of the address from the latest one
+// It skips the offset number of the transactions and returns at most the limit number of them
+func (idx *Index) Transactions(addr common.Address, offset int, limit int) ([]*Entry, error) {
+	return idx.entries(append(append([]byte{}, tagAddress...), addr[:]...), offset, limit)
+}
+
+// TransactionsByPublicHash returns the transactions that create the outputs of the public hash from the latest one
+// It skips the offset number of the transactions and returns at most the limit number of them
+func (idx *Index) TransactionsByPublicHash(pubhash common.PublicHash, offset int, limit int) ([]*Entry, error) {
+	return idx.entries(append(append([]byte{}, tagPublicHash...), pubhash[:]...), offset, limit)
+}
+
+func (idx *Index) entries(prefix []byte, offset int, limit int) ([]*Entry, error) {
+	list := []*Entry{}
+	if limit <= 0 {
+		return list, nil
+	}
+	var inErr error
+	if err := idx.st.Iterate(prefix, true, func(key []byte, value []byte) bool {
+		if offset > 0 {
+			offset--
+			return true
+		}
+		if len(key) != len(prefix)+6 || (len(value) != 1 && len(value) != 7) {
+			inErr = errs.WrapField(ErrCorruptedHistoryStore, "entry", len(prefix)+6, len(key))
+			return false
+		}
+		e := &Entry{
+			Coord: common.NewCoordinate(binary.BigEndian.Uint32(key[len(prefix):]), binary.BigEndian.Uint16(key[len(prefix)+4:])),
+			Type:  transaction.Type(value[0]),
+		}
+		if len(value) == 7 {
+			e.Order = common.NewCoordinate(binary.BigEndian.Uint32(value[1:]), binary.BigEndian.Uint16(value[5:]))
+		}
+		list = append(list, e)
+		return len(list) < limit
+	}); err != nil {
+		return nil, err
+	}
+	if inErr != nil {
+		return nil, inErr
+	}
+	return list, nil
+}
+
+func entryKey(tag []byte, id []byte, coord *common.Coordinate) []byte {
+	key := make([]byte, 0, len(tag)+len(id)+6)
+	key = append(key, tag...)
+	key = append(key, id...)
+	return append(key, coordBytes(coord)...)
+}
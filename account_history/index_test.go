@@ -0,0 +1,259 @@
+package account_history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/internal/test_util"
+	"github.com/fletaio/extension/standing_order"
+	"github.com/fletaio/extension/store"
+	"github.com/fletaio/extension/type_registry"
+	"github.com/fletaio/extension/utxo_tx"
+)
+
+func tempStorePath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "account_history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "history.db"), func() { os.RemoveAll(dir) }
+}
+
+func testBlocks(t *testing.T) []*block.Block {
+	tran := data.NewTransactor(common.NewCoordinate(0, 0))
+	if err := type_registry.Register(data.NewAccounter(tran.ChainCoord()), tran, data.NewEventer(tran.ChainCoord())); err != nil {
+		t.Fatal(err)
+	}
+	newTx := func(name string) transaction.Transaction {
+		tx, err := tran.NewByTypeName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	transfer := newTx("fleta.Transfer").(*account_tx.Transfer)
+	transfer.From_ = test_util.Address(1)
+	transfer.To = account_name.NewAddressDestination(test_util.Address(2))
+	create := newTx("fleta.CreateAccount").(*account_tx.CreateAccount)
+	create.From_ = test_util.Address(1)
+	assign := newTx("fleta.Assign").(*utxo_tx.Assign)
+	assign.Vout = []*transaction.TxOut{transaction.NewTxOut(), transaction.NewTxOut()}
+	assign.Vout[0].PublicHash[0] = 7
+	assign.Vout[1].PublicHash[0] = 7
+	back := newTx("fleta.Transfer").(*account_tx.Transfer)
+	back.From_ = test_util.Address(2)
+	back.To = account_name.NewAddressDestination(test_util.Address(1))
+
+	return []*block.Block{
+		{Header: block.Header{Height_: 1}, Body: block.Body{Transactions: []transaction.Transaction{transfer, create}}},
+		{Header: block.Header{Height_: 2}, Body: block.Body{Transactions: []transaction.Transaction{assign}}},
+		{Header: block.Header{Height_: 3}, Body: block.Body{Transactions: []transaction.Transaction{back}}},
+	}
+}
+
+func TestIndexBlock(t *testing.T) {
+	path, remove := tempStorePath(t)
+	defer remove()
+	st, err := store.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	idx := NewIndex(st)
+	blocks := testBlocks(t)
+
+	if err := idx.IndexBlock(blocks[1], nil); errs.CodeOf(err) != CodeInvalidHistoryHeight {
+		t.Errorf("expected the invalid height but %v", err)
+	}
+	for _, b := range blocks {
+		if err := idx.IndexBlock(b, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := idx.IndexBlock(blocks[0], nil); err != nil {
+		t.Errorf("the indexed block should be ignored but %v", err)
+	}
+	if height, err := idx.Height(); err != nil || height != 3 {
+		t.Errorf("expected the height 3 but %d %v", height, err)
+	}
+
+	list, err := idx.Transactions(test_util.Address(1), 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*common.Coordinate{common.NewCoordinate(3, 0), common.NewCoordinate(1, 1), common.NewCoordinate(1, 0)}
+	if len(list) != len(expected) {
+		t.Fatalf("expected %d transactions but %d", len(expected), len(list))
+	}
+	for i, e := range list {
+		if !e.Coord.Equal(expected[i]) {
+			t.Errorf("expected %v but %v at %d", expected[i], e.Coord, i)
+		}
+	}
+	if list[1].Type != 20 {
+		t.Errorf("expected the type of the fleta.CreateAccount but %d", list[1].Type)
+	}
+
+	if list, err := idx.Transactions(test_util.Address(1), 1, 1); err != nil || len(list) != 1 || !list[0].Coord.Equal(expected[1]) {
+		t.Errorf("unexpected page %v %v", list, err)
+	}
+	if list, err := idx.Transactions(common.NewAddress(common.NewCoordinate(1, 1), 0), 0, 10); err != nil || len(list) != 1 {
+		t.Errorf("the created account is not indexed %v %v", list, err)
+	}
+	var pubhash common.PublicHash
+	pubhash[0] = 7
+	if list, err := idx.TransactionsByPublicHash(pubhash, 0, 10); err != nil || len(list) != 1 || !list[0].Coord.Equal(common.NewCoordinate(2, 0)) {
+		t.Errorf("the outputs are not indexed %v %v", list, err)
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	path, remove := tempStorePath(t)
+	defer remove()
+	st, err := store.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	idx := NewIndex(st)
+	provider := &test_util.Provider{Blocks: testBlocks(t)}
+
+	if err := idx.IndexBlock(provider.Blocks[0], nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Sync(provider); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := idx.Transactions(test_util.Address(2), 0, 10); err != nil || len(list) != 2 {
+		t.Fatalf("unexpected transactions %v %v", list, err)
+	}
+
+	provider.Blocks = provider.Blocks[:1]
+	if err := idx.Rebuild(provider); err != nil {
+		t.Fatal(err)
+	}
+	if height, _ := idx.Height(); height != 1 {
+		t.Errorf("expected the height 1 but %d", height)
+	}
+	if list, err := idx.Transactions(test_util.Address(2), 0, 10); err != nil || len(list) != 1 {
+		t.Errorf("unexpected transactions %v %v", list, err)
+	}
+}
+
+func TestNameDestination(t *testing.T) {
+	path, remove := tempStorePath(t)
+	defer remove()
+	st, err := store.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	idx := NewIndex(st)
+	blocks := testBlocks(t)
+	transfer := blocks[0].Body.Transactions[0].(*account_tx.Transfer)
+	transfer.To = account_name.NewNameDestination("testaccount")
+	provider := &test_util.Provider{Blocks: blocks}
+
+	// the name is not resolved without the events of the execution
+	if err := idx.Sync(provider); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := idx.Transactions(test_util.Address(2), 0, 10); err != nil || len(list) != 1 {
+		t.Fatalf("unexpected transactions %v %v", list, err)
+	}
+
+	// the address of the execution is recorded and kept by the rebuild although the name is owned by another account later
+	if err := idx.clear(); err != nil {
+		t.Fatal(err)
+	}
+	ev := &event_def.TransferEvent{
+		Base: event.Base{Coord_: common.NewCoordinate(1, 0)},
+		From: test_util.Address(1),
+		To:   test_util.Address(2),
+	}
+	if err := idx.IndexBlock(blocks[0], []event.Event{ev}); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Rebuild(provider); err != nil {
+		t.Fatal(err)
+	}
+	if list, err := idx.Transactions(test_util.Address(2), 0, 10); err != nil || len(list) != 2 || !list[1].Coord.Equal(common.NewCoordinate(1, 0)) {
+		t.Errorf("unexpected transactions %v %v", list, err)
+	}
+}
+
+func TestStandingOrderPayment(t *testing.T) {
+	path, remove := tempStorePath(t)
+	defer remove()
+	st, err := store.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	idx := NewIndex(st)
+	blocks := testBlocks(t)
+	tran := data.NewTransactor(common.NewCoordinate(0, 0))
+	if err := type_registry.Register(data.NewAccounter(tran.ChainCoord()), tran, data.NewEventer(tran.ChainCoord())); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := tran.NewByTypeName("fleta.RegisterStandingOrder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	register := tx.(*standing_order.RegisterStandingOrder)
+	register.From_ = test_util.Address(3)
+	register.To = test_util.Address(2)
+	blocks[0].Body.Transactions = append(blocks[0].Body.Transactions, register)
+	provider := &test_util.Provider{Blocks: blocks}
+
+	// the payment is made by the processing of the third block with the coordinate of the order
+	ev := &event_def.TransferEvent{
+		Base: event.Base{Coord_: common.NewCoordinate(1, 2)},
+		From: test_util.Address(3),
+		To:   test_util.Address(2),
+	}
+	for i, b := range blocks {
+		var events []event.Event
+		if i == 2 {
+			events = []event.Event{ev}
+		}
+		if err := idx.IndexBlock(b, events); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func() {
+		t.Helper()
+		list, err := idx.Transactions(test_util.Address(3), 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 2 || !list[0].Coord.Equal(common.NewCoordinate(3, 1)) || list[0].Order == nil || !list[0].Order.Equal(common.NewCoordinate(1, 2)) || list[0].Type != register.Type() {
+			t.Fatalf("unexpected payment of the payer %v", list)
+		}
+		if list[1].Order != nil || !list[1].Coord.Equal(common.NewCoordinate(1, 2)) {
+			t.Fatalf("unexpected registration %v", list[1])
+		}
+		if list, err := idx.Transactions(test_util.Address(2), 0, 10); err != nil || len(list) != 4 || !list[0].Coord.Equal(common.NewCoordinate(3, 1)) || !list[1].Coord.Equal(common.NewCoordinate(3, 0)) {
+			t.Fatalf("unexpected payment of the recipient %v %v", list, err)
+		}
+	}
+	check()
+
+	// the recorded payment is indexed again by the rebuild without the events
+	if err := idx.Rebuild(provider); err != nil {
+		t.Fatal(err)
+	}
+	check()
+}
@@ -0,0 +1,66 @@
+package account_history
+
+import (
+	"sync"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/message_def"
+	"github.com/fletaio/core/transaction"
+)
+
+// Indexer updates the index by the processed blocks of the kernel
+// It is added to the kernel as an event handler and the index stops following the chain at the first failure until it is synced again
+type Indexer struct {
+	*Index
+	sync.Mutex
+	err error
+}
+
+// NewIndexer returns a Indexer of the index
+func NewIndexer(idx *Index) *Indexer {
+	return &Indexer{
+		Index: idx,
+	}
+}
+
+// Err returns the failure of the last processed block
+func (ih *Indexer) Err() error {
+	ih.Mutex.Lock()
+	defer ih.Mutex.Unlock()
+
+	return ih.err
+}
+
+// AfterProcessBlock called when processed block to the chain
+func (ih *Indexer) AfterProcessBlock(kn *kernel.Kernel, b *block.Block, s *block.ObserverSigned, ctx *data.Context) {
+	err := ih.IndexBlock(b, ctx.Top().Events)
+
+	ih.Mutex.Lock()
+	defer ih.Mutex.Unlock()
+	ih.err = err
+}
+
+// OnProcessBlock called when processing a block to the chain (error prevent processing block)
+func (ih *Indexer) OnProcessBlock(kn *kernel.Kernel, b *block.Block, s *block.ObserverSigned, ctx *data.Context) error {
+	return nil
+}
+
+// OnPushTransaction called when pushing a transaction to the transaction pool (error prevent push transaction)
+func (ih *Indexer) OnPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) error {
+	return nil
+}
+
+// AfterPushTransaction called when pushed a transaction to the transaction pool
+func (ih *Indexer) AfterPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) {
+}
+
+// DoTransactionBroadcast called when a transaction need to be broadcast
+func (ih *Indexer) DoTransactionBroadcast(kn *kernel.Kernel, msg *message_def.TransactionMessage) {
+}
+
+// DebugLog TEMP
+func (ih *Indexer) DebugLog(kn *kernel.Kernel, args ...interface{}) {
+}
@@ -0,0 +1,107 @@
+package account_history
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/standing_order"
+	"github.com/fletaio/extension/token_tx"
+	"github.com/fletaio/extension/utxo_tx"
+)
+
+type fromTransaction interface {
+	From() common.Address
+}
+
+// Involved returns the addresses and the public hashes of the outputs that are touched by the transaction at the coordinate
+// to is the address of the account name of the destination that is resolved by the execution, and the destination is skipped when it is nil
+// The owners of the spent UTXOs are not returned because the UTXOs are removed by the transaction
+func Involved(coord *common.Coordinate, tx transaction.Transaction, to *common.Address) ([]common.Address, []common.PublicHash) {
+	addrs := []common.Address{}
+	pubhashes := []common.PublicHash{}
+	if t, is := tx.(fromTransaction); is {
+		addrs = append(addrs, t.From())
+	}
+
+	switch tx := tx.(type) {
+	case *account_tx.Transfer:
+		addrs = appendDestination(addrs, &tx.To, to)
+	case *account_tx.Withdraw:
+		pubhashes = appendVout(pubhashes, tx.Vout)
+	case *account_tx.CreateAccount:
+		addrs = append(addrs, common.NewAddress(coord, 0))
+	case *account_tx.CreateMultiSigAccount:
+		addrs = append(addrs, common.NewAddress(coord, 0))
+	case *account_tx.TransferName:
+		addrs = append(addrs, tx.To)
+	case *account_tx.CloseAccount:
+		addrs = append(addrs, tx.To)
+	case *standing_order.RegisterStandingOrder:
+		addrs = append(addrs, tx.To)
+	case *utxo_tx.Assign:
+		pubhashes = appendVout(pubhashes, tx.Vout)
+	case *utxo_tx.Deposit:
+		pubhashes = appendVout(pubhashes, tx.Vout)
+		addrs = appendDestination(addrs, &tx.To, to)
+	case *utxo_tx.OpenAccount:
+		pubhashes = appendVout(pubhashes, tx.Vout)
+		addrs = append(addrs, common.NewAddress(coord, 0))
+	case *token_tx.TokenCreation:
+		addrs = append(addrs, common.NewAddress(coord, 0))
+	case *token_tx.TokenIssue:
+		addrs = append(addrs, tx.TokenAddress)
+	}
+	return uniqueAddresses(addrs), uniquePublicHashes(pubhashes)
+}
+
+func appendDestination(addrs []common.Address, dest *account_name.Destination, to *common.Address) []common.Address {
+	if !dest.IsName() {
+		return append(addrs, dest.Address)
+	}
+	if to == nil {
+		return addrs
+	}
+	return append(addrs, *to)
+}
+
+func hasNameDestination(tx transaction.Transaction) bool {
+	switch tx := tx.(type) {
+	case *account_tx.Transfer:
+		return tx.To.IsName()
+	case *utxo_tx.Deposit:
+		return tx.To.IsName()
+	}
+	return false
+}
+
+func appendVout(pubhashes []common.PublicHash, vout []*transaction.TxOut) []common.PublicHash {
+	for _, out := range vout {
+		pubhashes = append(pubhashes, out.PublicHash)
+	}
+	return pubhashes
+}
+
+func uniqueAddresses(addrs []common.Address) []common.Address {
+	list := make([]common.Address, 0, len(addrs))
+	addrMap := map[common.Address]bool{}
+	for _, addr := range addrs {
+		if !addrMap[addr] {
+			addrMap[addr] = true
+			list = append(list, addr)
+		}
+	}
+	return list
+}
+
+func uniquePublicHashes(pubhashes []common.PublicHash) []common.PublicHash {
+	list := make([]common.PublicHash, 0, len(pubhashes))
+	pubhashMap := map[common.PublicHash]bool{}
+	for _, pubhash := range pubhashes {
+		if !pubhashMap[pubhash] {
+			pubhashMap[pubhash] = true
+			list = append(list, pubhash)
+		}
+	}
+	return list
+}
@@ -0,0 +1,109 @@
+package account_history
+
+import (
+	"encoding/binary"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+)
+
+// payment is a payment of a standing order that is made when the block is processed
+// It is not a transaction of the block, so it is indexed at the index after the transactions of the block
+type payment struct {
+	coord *common.Coordinate // the height of the block and the index after the transactions of the block
+	order *common.Coordinate // the coordinate of the transaction that registered the order
+	t     transaction.Type   // the type of the transaction that registered the order
+	from  common.Address
+	to    common.Address
+}
+
+// paymentsOf returns the payments of the standing orders by the TransferEvents of the block
+// The TransferEvent of a payment has the coordinate of the transaction that registered the order in a previous block
+// and the type of the payment is taken from the entry of the payer at the coordinate
+func (idx *Index) paymentsOf(b *block.Block, events []event.Event) ([]*payment, error) {
+	height := b.Header.Height()
+	list := []*payment{}
+	for _, e := range events {
+		ev, is := e.(*event_def.TransferEvent)
+		if !is || ev.From == (common.Address{}) || ev.To == (common.Address{}) {
+			continue
+		}
+		p := &payment{
+			coord: common.NewCoordinate(height, uint16(len(b.Body.Transactions)+len(list))),
+			order: ev.Coord().Clone(),
+			from:  ev.From,
+			to:    ev.To,
+		}
+		if p.order.Height >= height {
+			continue
+		}
+		bs, err := idx.st.Get(entryKey(tagAddress, p.from[:], p.order))
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) == 0 {
+			return nil, errs.WrapField(ErrCorruptedHistoryStore, "order", p.order, nil)
+		}
+		p.t = transaction.Type(bs[0])
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+// recordedPayments returns the payments that are recorded when the block of the height is indexed with its events
+func (idx *Index) recordedPayments(height uint32) ([]*payment, error) {
+	prefix := make([]byte, len(tagPayment)+4)
+	copy(prefix, tagPayment)
+	binary.BigEndian.PutUint32(prefix[len(tagPayment):], height)
+
+	list := []*payment{}
+	var inErr error
+	if err := idx.st.Iterate(prefix, false, func(key []byte, value []byte) bool {
+		p := &payment{}
+		if len(key) != len(prefix)+2 || len(value) != 1+len(p.from)+len(p.to)+6 {
+			inErr = errs.WrapField(ErrCorruptedHistoryStore, "payment", 1+len(p.from)+len(p.to)+6, len(value))
+			return false
+		}
+		p.coord = common.NewCoordinate(height, binary.BigEndian.Uint16(key[len(prefix):]))
+		p.t = transaction.Type(value[0])
+		value = value[1:]
+		copy(p.from[:], value)
+		value = value[len(p.from):]
+		copy(p.to[:], value)
+		value = value[len(p.to):]
+		p.order = common.NewCoordinate(binary.BigEndian.Uint32(value), binary.BigEndian.Uint16(value[4:]))
+		list = append(list, p)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	if inErr != nil {
+		return nil, inErr
+	}
+	return list, nil
+}
+
+// bytes returns the recorded form of the payment
+func (p *payment) bytes() []byte {
+	bs := make([]byte, 0, 1+len(p.from)+len(p.to)+6)
+	bs = append(bs, byte(p.t))
+	bs = append(bs, p.from[:]...)
+	bs = append(bs, p.to[:]...)
+	return append(bs, coordBytes(p.order)...)
+}
+
+// entryValue returns the value of the entries of the payer and the recipient
+func (p *payment) entryValue() []byte {
+	return append([]byte{byte(p.t)}, coordBytes(p.order)...)
+}
+
+func coordBytes(coord *common.Coordinate) []byte {
+	bs := make([]byte, 6)
+	binary.BigEndian.PutUint32(bs, coord.Height)
+	binary.BigEndian.PutUint16(bs[4:], coord.Index)
+	return bs
+}
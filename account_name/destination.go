@@ -0,0 +1,178 @@
+package account_name
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/errs"
+)
+
+// MaxNameLength is the maximum byte length of account names in the binary form
+const MaxNameLength = 64
+
+// destination kinds of the binary form
+const (
+	addressDestination = uint8(0)
+	nameDestination    = uint8(1)
+)
+
+// Destination is the recipient of a transaction that is specified by an address or an account name
+// The name is resolved to the address of its owner when the transaction is executed
+type Destination struct {
+	Address common.Address
+	Name    string
+}
+
+// NewAddressDestination returns the destination that is specified by the address
+func NewAddressDestination(addr common.Address) Destination {
+	return Destination{
+		Address: addr,
+	}
+}
+
+// NewNameDestination returns the destination that is specified by the account name
+func NewNameDestination(name string) Destination {
+	return Destination{
+		Name: name,
+	}
+}
+
+// IsName returns true when the destination is specified by the account name
+func (d Destination) IsName() bool {
+	return len(d.Name) > 0
+}
+
+// Resolve returns the address of the destination
+// The account name is resolved to the address of its owner by the name index of the loader
+func (d *Destination) Resolve(loader data.Loader) (common.Address, error) {
+	if !d.IsName() {
+		return d.Address, nil
+	}
+	return OwnerOf(loader, d.Name)
+}
+
+// String returns the address or the account name of the destination
+func (d Destination) String() string {
+	if d.IsName() {
+		return "@" + d.Name
+	}
+	return d.Address.String()
+}
+
+// WriteTo is a serialization function
+func (d *Destination) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if d.IsName() {
+		if err := codec.CheckByteLength("Destination.Name", uint64(len(d.Name)), MaxNameLength); err != nil {
+			return wrote, err
+		}
+		if n, err := util.WriteUint8(w, nameDestination); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := util.WriteString(w, d.Name); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	} else {
+		if n, err := util.WriteUint8(w, addressDestination); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := d.Address.WriteTo(w); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (d *Destination) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	Kind, n, err := util.ReadUint8(r)
+	if err != nil {
+		return read, err
+	}
+	read += n
+	switch Kind {
+	case addressDestination:
+		d.Name = ""
+		if n, err := d.Address.ReadFrom(r); err != nil {
+			return read, err
+		} else {
+			read += n
+		}
+	case nameDestination:
+		if v, n, err := codec.ReadString(r, "Destination.Name", MaxNameLength); err != nil {
+			return read, err
+		} else {
+			read += n
+			if len(v) == 0 {
+				return read, ErrInvalidAccountName
+			}
+			d.Address = common.Address{}
+			d.Name = v
+		}
+	default:
+		return read, errs.WrapField(ErrInvalidDestination, "kind", nil, Kind)
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+// The address is written as a string and the account name is written as an object that has the name
+func (d Destination) MarshalJSON() ([]byte, error) {
+	if !d.IsName() {
+		return d.Address.MarshalJSON()
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(d.Name); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (d *Destination) UnmarshalJSON(bs []byte) error {
+	bs = bytes.TrimSpace(bs)
+	if len(bs) > 0 && bs[0] == '"' {
+		var str string
+		if err := json.Unmarshal(bs, &str); err != nil {
+			return err
+		}
+		addr, err := common.ParseAddress(str)
+		if err != nil {
+			return err
+		}
+		d.Address = addr
+		d.Name = ""
+		return nil
+	}
+	var v struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	if len(v.Name) == 0 || len(v.Name) > MaxNameLength {
+		return ErrInvalidAccountName
+	}
+	d.Address = common.Address{}
+	d.Name = v.Name
+	return nil
+}
@@ -0,0 +1,83 @@
+package account_name
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+)
+
+func TestDestinationBinary(t *testing.T) {
+	addr := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	tests := []struct {
+		dst  Destination
+		kind byte
+	}{
+		{NewAddressDestination(addr), addressDestination},
+		{NewNameDestination("testaccount"), nameDestination},
+	}
+	for _, tt := range tests {
+		var buffer bytes.Buffer
+		wrote, err := tt.dst.WriteTo(&buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bs := buffer.Bytes()
+		if bs[0] != tt.kind {
+			t.Fatalf("%v: expected the kind %d but %d", tt.dst, tt.kind, bs[0])
+		}
+		var dst Destination
+		if read, err := dst.ReadFrom(bytes.NewReader(bs)); err != nil {
+			t.Fatal(err)
+		} else if read != wrote {
+			t.Fatalf("%v: read %d bytes but wrote %d", tt.dst, read, wrote)
+		}
+		if dst != tt.dst {
+			t.Fatalf("expected %v but %v", tt.dst, dst)
+		}
+	}
+}
+
+func TestDestinationReadInvalid(t *testing.T) {
+	var dst Destination
+	if _, err := dst.ReadFrom(bytes.NewReader([]byte{2})); !errors.Is(err, ErrInvalidDestination) {
+		t.Fatalf("expected %v but %v", ErrInvalidDestination, err)
+	}
+	if _, err := dst.ReadFrom(bytes.NewReader([]byte{nameDestination, 0})); err != ErrInvalidAccountName {
+		t.Fatalf("expected %v but %v", ErrInvalidAccountName, err)
+	}
+}
+
+func TestDestinationJSON(t *testing.T) {
+	addr := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	tests := []struct {
+		dst  Destination
+		json string
+	}{
+		{NewAddressDestination(addr), `"` + addr.String() + `"`},
+		{NewNameDestination("testaccount"), `{"name":"testaccount"}`},
+	}
+	for _, tt := range tests {
+		bs, err := json.Marshal(tt.dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(bs) != tt.json {
+			t.Fatalf("expected %s but %s", tt.json, bs)
+		}
+		var dst Destination
+		if err := json.Unmarshal(bs, &dst); err != nil {
+			t.Fatal(err)
+		}
+		if dst != tt.dst {
+			t.Fatalf("expected %v but %v", tt.dst, dst)
+		}
+	}
+
+	var dst Destination
+	if err := json.Unmarshal([]byte(`{"name":""}`), &dst); err != ErrInvalidAccountName {
+		t.Fatalf("expected %v but %v", ErrInvalidAccountName, err)
+	}
+}
@@ -0,0 +1,13 @@
+package account_name
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// account_name errors
+var (
+	ErrInvalidAccountName  = errs.ErrInvalidAccountName
+	ErrNotExistAccountName = errs.ErrNotExistAccountName
+	ErrNotNameOwner        = errs.ErrNotNameOwner
+	ErrInvalidDestination  = errs.ErrInvalidDestination
+)
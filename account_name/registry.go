@@ -0,0 +1,233 @@
+package account_name
+
+import (
+	"bytes"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/errs"
+)
+
+// registryAddress is the address that keeps the owners of the transferred names as account data
+// The name that is not in the registry is owned by the account that is created with it
+var registryAddress = common.Address{}
+
+// releasedOwner is the owner of the name that is released while the account that is created with it still exists
+// The account keeps the name in the chain, so the name is given to the next account through the registry
+var releasedOwner = []byte{0}
+
+var (
+	tagOwner = []byte("account_name.owner.")
+	tagNames = []byte("account_name.names.")
+)
+
+func toOwnerKey(name string) []byte {
+	bs := make([]byte, len(tagOwner)+len(name))
+	copy(bs, tagOwner)
+	copy(bs[len(tagOwner):], name)
+	return bs
+}
+
+func toNamesKey(addr common.Address) []byte {
+	bs := make([]byte, len(tagNames)+len(addr))
+	copy(bs, tagNames)
+	copy(bs[len(tagNames):], addr[:])
+	return bs
+}
+
+// IsExist returns true when the name is owned by an account
+func IsExist(loader data.Loader, name string) (bool, error) {
+	if bs := loader.AccountData(registryAddress, toOwnerKey(name)); len(bs) > 0 {
+		return !bytes.Equal(bs, releasedOwner), nil
+	}
+	return loader.IsExistAccountName(name)
+}
+
+// IsReleased returns true when the name is released but the account that is created with it still exists
+func IsReleased(loader data.Loader, name string) bool {
+	return bytes.Equal(loader.AccountData(registryAddress, toOwnerKey(name)), releasedOwner)
+}
+
+// OwnerOf returns the address of the account that owns the name
+func OwnerOf(loader data.Loader, name string) (common.Address, error) {
+	if bs := loader.AccountData(registryAddress, toOwnerKey(name)); len(bs) > 0 {
+		if bytes.Equal(bs, releasedOwner) {
+			return common.Address{}, errs.WrapField(ErrNotExistAccountName, "name", nil, name)
+		}
+		var addr common.Address
+		if len(bs) != len(addr) {
+			return common.Address{}, errs.WrapField(ErrInvalidAccountName, "owner", len(addr), len(bs))
+		}
+		copy(addr[:], bs)
+		return addr, nil
+	}
+	if is, err := loader.IsExistAccountName(name); err != nil {
+		return common.Address{}, err
+	} else if !is {
+		return common.Address{}, errs.WrapField(ErrNotExistAccountName, "name", nil, name)
+	}
+	return loader.AddressByName(name)
+}
+
+// NamesOf returns the names that are owned by the account
+func NamesOf(loader data.Loader, acc account.Account) ([]string, error) {
+	names, err := transferredNames(loader, acc.Address())
+	if err != nil {
+		return nil, err
+	}
+	if len(acc.Name()) > 0 && !IsReleased(loader, acc.Name()) {
+		if owner, err := OwnerOf(loader, acc.Name()); err != nil {
+			return nil, err
+		} else if owner == acc.Address() && !contains(names, acc.Name()) {
+			names = append([]string{acc.Name()}, names...)
+		}
+	}
+	return names, nil
+}
+
+// Transfer moves the name from the owner to the other account
+func Transfer(ctx *data.Context, name string, from common.Address, to common.Address) error {
+	if owner, err := OwnerOf(ctx, name); err != nil {
+		return err
+	} else if owner != from {
+		return errs.WrapField(ErrNotNameOwner, "from", owner, from)
+	}
+
+	if err := removeTransferredName(ctx, from, name); err != nil {
+		return err
+	}
+	names, err := transferredNames(ctx, to)
+	if err != nil {
+		return err
+	}
+	if err := setTransferredNames(ctx, to, append(names, name)); err != nil {
+		return err
+	}
+	ctx.SetAccountData(registryAddress, toOwnerKey(name), to[:])
+	return nil
+}
+
+// Claim gives the released name to the new account through the registry
+// It returns false when the name is not released, and then the name should be given as the name of the new account
+func Claim(ctx *data.Context, name string, addr common.Address) (bool, error) {
+	if !IsReleased(ctx, name) {
+		return false, nil
+	}
+	names, err := transferredNames(ctx, addr)
+	if err != nil {
+		return false, err
+	}
+	if err := setTransferredNames(ctx, addr, append(names, name)); err != nil {
+		return false, err
+	}
+	ctx.SetAccountData(registryAddress, toOwnerKey(name), addr[:])
+	return true, nil
+}
+
+// Release frees the names that are owned by the account so they can be used by new accounts
+// It should be called when the account is deleted and the name of the account itself is freed by deleting it
+// The name that is transferred from an account that still exists is marked as released because that account keeps it in the chain,
+// and the name of the account itself stays with its owner when it has been transferred to the other account
+func Release(ctx *data.Context, acc account.Account) error {
+	names, err := transferredNames(ctx, acc.Address())
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		value := []byte(nil)
+		if is, err := ctx.IsExistAccountName(name); err != nil {
+			return err
+		} else if is {
+			holder, err := ctx.AddressByName(name)
+			if err != nil {
+				return err
+			}
+			if holder != acc.Address() {
+				value = releasedOwner
+			}
+		}
+		ctx.SetAccountData(registryAddress, toOwnerKey(name), value)
+	}
+	ctx.SetAccountData(registryAddress, toNamesKey(acc.Address()), nil)
+	if len(acc.Name()) > 0 && IsReleased(ctx, acc.Name()) {
+		ctx.SetAccountData(registryAddress, toOwnerKey(acc.Name()), nil)
+	}
+	return nil
+}
+
+// TransferredNames returns the names that are transferred to the account
+func TransferredNames(loader data.Loader, addr common.Address) ([]string, error) {
+	return transferredNames(loader, addr)
+}
+
+// RegistryData returns the account data of the registry that records the names transferred to the account
+// The keys of the map are the names of the account data of the returned address and it is used to restore the registry in a new genesis
+func RegistryData(addr common.Address, names []string) (common.Address, map[string][]byte, error) {
+	m := map[string][]byte{}
+	if len(names) == 0 {
+		return registryAddress, m, nil
+	}
+	var buffer bytes.Buffer
+	for _, name := range names {
+		if _, err := util.WriteString(&buffer, name); err != nil {
+			return common.Address{}, nil, err
+		}
+		m[string(toOwnerKey(name))] = addr[:]
+	}
+	m[string(toNamesKey(addr))] = buffer.Bytes()
+	return registryAddress, m, nil
+}
+
+func transferredNames(loader data.Loader, addr common.Address) ([]string, error) {
+	bs := loader.AccountData(registryAddress, toNamesKey(addr))
+	names := []string{}
+	r := bytes.NewReader(bs)
+	for r.Len() > 0 {
+		name, _, err := util.ReadString(r)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func setTransferredNames(ctx *data.Context, addr common.Address, names []string) error {
+	if len(names) == 0 {
+		ctx.SetAccountData(registryAddress, toNamesKey(addr), nil)
+		return nil
+	}
+	var buffer bytes.Buffer
+	for _, name := range names {
+		if _, err := util.WriteString(&buffer, name); err != nil {
+			return err
+		}
+	}
+	ctx.SetAccountData(registryAddress, toNamesKey(addr), buffer.Bytes())
+	return nil
+}
+
+func removeTransferredName(ctx *data.Context, addr common.Address, name string) error {
+	names, err := transferredNames(ctx, addr)
+	if err != nil {
+		return err
+	}
+	list := make([]string, 0, len(names))
+	for _, v := range names {
+		if v != name {
+			list = append(list, v)
+		}
+	}
+	return setTransferredNames(ctx, addr, list)
+}
+
+func contains(list []string, name string) bool {
+	for _, v := range list {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
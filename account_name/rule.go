@@ -0,0 +1,43 @@
+// Package account_name holds the canonical form of account names and the registry of the names that are transferred between accounts.
+package account_name
+
+// IsCanonical returns true when the name is in the canonical form
+// The canonical form has lower case ASCII letters and digits, and '.', '-' or '_' between them
+// It does not allow unicode, whitespace and control characters so names cannot be confused by their look
+func IsCanonical(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case isSeparator(c):
+			if i == 0 || i == len(name)-1 || isSeparator(name[i-1]) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Normalize returns the canonical form of the name
+// Upper case ASCII letters are folded to lower case and the other characters that are out of the charset are not allowed
+func Normalize(name string) (string, error) {
+	bs := []byte(name)
+	for i, c := range bs {
+		if c >= 'A' && c <= 'Z' {
+			bs[i] = c - 'A' + 'a'
+		}
+	}
+	if !IsCanonical(string(bs)) {
+		return "", ErrInvalidAccountName
+	}
+	return string(bs), nil
+}
+
+func isSeparator(c byte) bool {
+	return c == '.' || c == '-' || c == '_'
+}
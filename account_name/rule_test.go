@@ -0,0 +1,42 @@
+package account_name
+
+import (
+	"testing"
+)
+
+func TestIsCanonical(t *testing.T) {
+	tests := map[string]bool{
+		"testaccount":       true,
+		"test.account":      true,
+		"test-acc_01":       true,
+		"0123456789":        true,
+		"":                  false,
+		"TestAccount":       false,
+		"test account":      false,
+		"test\taccount":     false,
+		".testaccount":      false,
+		"testaccount-":      false,
+		"test..account":     false,
+		"test.-account":     false,
+		"t\u0435staccount":  false, // cyrillic e
+		"testaccount\u200b": false, // zero width space
+	}
+	for name, expected := range tests {
+		if IsCanonical(name) != expected {
+			t.Errorf("%q: expected %v", name, expected)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if name, err := Normalize("Test.Account"); err != nil {
+		t.Fatal(err)
+	} else if name != "test.account" {
+		t.Fatalf("expected test.account but %q", name)
+	}
+	for _, name := range []string{"Test Account", "\uff34estaccount", "test__account"} {
+		if _, err := Normalize(name); err != ErrInvalidAccountName {
+			t.Errorf("%q: expected %v but %v", name, ErrInvalidAccountName, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package account_tx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/internal/test_util"
+	"github.com/fletaio/extension/token_tx"
+)
+
+func init() {
+	// the formulation account of the consensus is not a dependency of the extension
+	data.RegisterAccount("consensus.FormulationAccount", func(t account.Type) account.Account {
+		return &account_def.SingleAccount{
+			Base: account.Base{
+				Type_:    t,
+				Balance_: amount.NewCoinAmount(0, 0),
+			},
+		}
+	}, func(loader data.Loader, a account.Account, signers []common.PublicHash) error {
+		return nil
+	})
+}
+
+func TestCloseAccountType(t *testing.T) {
+	loader := test_util.NewLoader(common.NewCoordinate(0, 0))
+	loader.Act = test_util.Accounter(t, map[string]account.Type{
+		"fleta.SingleAccount":          1,
+		"fleta.TokenAccount":           12,
+		"consensus.FormulationAccount": 60,
+	})
+	loader.Tran = test_util.Transactor(t, map[string]transaction.Type{"fleta.CloseAccount": 26})
+	loader.Height = 10
+
+	to := test_util.Address(9)
+	target, err := loader.Act.NewByTypeName("fleta.SingleAccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	target.(*account_def.SingleAccount).Address_ = to
+	loader.AddAccount(target, 0)
+
+	for n, name := range []string{"fleta.TokenAccount", "consensus.FormulationAccount"} {
+		a, err := loader.Act.NewByTypeName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := test_util.Address(uint16(n + 1))
+		switch acc := a.(type) {
+		case *token_tx.TokenAccount:
+			acc.Address_ = addr
+			acc.KeyHash = test_util.PublicHash(1)
+		case *account_def.SingleAccount:
+			acc.Address_ = addr
+			acc.KeyHash = test_util.PublicHash(1)
+		}
+		a.AddBalance(amount.NewCoinAmount(100, 0))
+		loader.AddAccount(a, 0)
+
+		tx, err := loader.Tran.NewByTypeName("fleta.CloseAccount")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ca := tx.(*account_tx.CloseAccount)
+		ca.From_ = addr
+		ca.Seq_ = 1
+		ca.To = to
+
+		ctx := data.NewContext(loader)
+		if err := loader.Tran.Validate(ctx, ca, []common.PublicHash{test_util.PublicHash(1)}); !errors.Is(err, account_tx.ErrNotClosableAccount) {
+			t.Errorf("%s: expected %v but %v", name, account_tx.ErrNotClosableAccount, err)
+		}
+		if _, err := loader.Tran.Execute(ctx, ca, common.NewCoordinate(10, 0)); !errors.Is(err, account_tx.ErrNotClosableAccount) {
+			t.Errorf("%s: expected %v but %v", name, account_tx.ErrNotClosableAccount, err)
+		}
+		if is, err := ctx.IsExistAccount(addr); err != nil || !is {
+			t.Errorf("%s: expected the account is not deleted but %v, %v", name, is, err)
+		}
+	}
+}
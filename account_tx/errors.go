@@ -1,18 +1,26 @@
 package account_tx
 
 import (
-	"errors"
+	"github.com/fletaio/extension/errs"
 )
 
 // account_tx errors
 var (
-	ErrInvalidSequence             = errors.New("invalid sequence")
-	ErrInsuffcientBalance          = errors.New("insufficient balance")
-	ErrExistAddress                = errors.New("exist address")
-	ErrExistAccountName            = errors.New("exist account name")
-	ErrInvalidAccountName          = errors.New("invalid account name")
-	ErrInvalidTransactionSignature = errors.New("invalid transaction signature")
-	ErrInvalidMultiSigKeyHashCount = errors.New("invalid multisig key hash count")
-	ErrNotMainChain                = errors.New("not main chain")
-	ErrDustAmount                  = errors.New("dust amount")
+	ErrInvalidSequence             = errs.ErrInvalidSequence
+	ErrInsuffcientBalance          = errs.ErrInsufficientBalance
+	ErrExistAddress                = errs.ErrExistAddress
+	ErrExistAccountName            = errs.ErrExistAccountName
+	ErrInvalidAccountName          = errs.ErrInvalidAccountName
+	ErrInvalidTransactionSignature = errs.ErrInvalidTransactionSignature
+	ErrInvalidMultiSigKeyHashCount = errs.ErrInvalidMultiSigKeyHashCount
+	ErrNotMainChain                = errs.ErrNotMainChain
+	ErrDustAmount                  = errs.ErrDustAmount
+	ErrTooLongTag                  = errs.ErrTooLongTag
+	ErrInvalidPolicy               = errs.ErrInvalidPolicy
+	ErrNotPolicyAccount            = errs.ErrNotPolicyAccount
+	ErrInvalidAdminSigner          = errs.ErrInvalidAdminSigner
+	ErrNotNameOwner                = errs.ErrNotNameOwner
+	ErrSelfNameTransfer            = errs.ErrSelfNameTransfer
+	ErrSelfCloseAccount            = errs.ErrSelfCloseAccount
+	ErrNotClosableAccount          = errs.ErrNotClosableAccount
 )
@@ -0,0 +1,3 @@
+package account_tx
+
+//go:generate go run ../codec/codecgen -type=Base:binary,Transfer,Withdraw,Burn,CreateAccount,CreateMultiSigAccount,SetAccountPolicy,TransferName,CloseAccount
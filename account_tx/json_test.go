@@ -0,0 +1,131 @@
+package account_tx
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func TestTransactionJSON(t *testing.T) {
+	tran := test_util.Transactor(t, testTransactionTypes)
+	for _, src := range testTransactions() {
+		dst, err := tran.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.JSONRoundTrip(t, src.(test_util.JSONValue), dst.(test_util.JSONValue))
+		if src.Hash() != dst.Hash() {
+			t.Errorf("%T: hash mismatch after json round trip", src)
+		}
+	}
+}
+
+func testJSONKeys(t *testing.T, v interface{}) []string {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := map[string]json.RawMessage{}
+	if err := json.Unmarshal(bs, &m); err != nil {
+		t.Fatalf("%T: %v: %s", v, err, bs)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestResultJSON(t *testing.T) {
+	addr := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	tests := []struct {
+		result interface{}
+		keys   []string
+	}{
+		{&TransferResult{To: addr, Amount: amount.NewCoinAmount(1, 0)}, []string{"amount", "to"}},
+		{&TransferResult{To: addr, ToName: "alice", Amount: amount.NewCoinAmount(1, 0)}, []string{"amount", "to", "to_name"}},
+		{&WithdrawResult{UTXOs: []uint64{1, 2}, Amount: amount.NewCoinAmount(1, 0)}, []string{"amount", "utxos"}},
+		{&BurnResult{Amount: amount.NewCoinAmount(1, 0)}, []string{"amount"}},
+		{&CreateAccountResult{Address: addr, Name: "alice"}, []string{"address", "name"}},
+		{&CreateMultiSigAccountResult{Address: addr, Name: "alice", KeyCount: 2}, []string{"address", "key_count", "name"}},
+		{&SetAccountPolicyResult{EffectiveHeight: 10}, []string{"effective_height"}},
+		{&TransferNameResult{Name: "alice", From: addr, To: addr}, []string{"from", "name", "to"}},
+		{&CloseAccountResult{To: addr, Amount: amount.NewCoinAmount(1, 0), ReleasedNames: []string{"alice"}}, []string{"amount", "released_names", "to"}},
+	}
+	for _, tt := range tests {
+		if keys := testJSONKeys(t, tt.result); !reflect.DeepEqual(keys, tt.keys) {
+			t.Errorf("%T: keys %v, want %v", tt.result, keys, tt.keys)
+		}
+	}
+
+	bs, err := json.Marshal(&TransferResult{To: addr, ToName: "alice", Amount: amount.NewCoinAmount(1, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		To     string `json:"to"`
+		ToName string `json:"to_name"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.To != addr.String() || v.ToName != "alice" {
+		t.Errorf("unexpected transfer result %s", bs)
+	}
+}
+
+func TestUnmarshalJSONLimits(t *testing.T) {
+	to := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	hashes := make([]string, 255)
+	for i := range hashes {
+		hashes[i] = test_util.PublicHash(byte(i)).String()
+	}
+	tests := []struct {
+		name  string
+		src   test_util.JSONValue
+		dst   test_util.JSONValue
+		key   string
+		value interface{}
+		field string
+		err   error
+	}{
+		{"transfer tag", &Transfer{Base: testBase(10), Amount: amount.NewCoinAmount(1, 0), To: account_name.NewAddressDestination(to)}, new(Transfer), "tag", hex.EncodeToString(make([]byte, 257)), "Transfer.Tag", codec.ErrExceedByteLength},
+		{"create account name", &CreateAccount{Base: testBase(20), Name: "testaccount", KeyHash: test_util.PublicHash(3)}, new(CreateAccount), "name", strings.Repeat("a", 65), "CreateAccount.Name", codec.ErrExceedByteLength},
+		{"multisig key hashes", &CreateMultiSigAccount{Base: testBase(21), Name: "multisigacc"}, new(CreateMultiSigAccount), "key_hashes", hashes, "CreateMultiSigAccount.KeyHashes", codec.ErrExceedItemCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs, err := tt.src.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := map[string]interface{}{}
+			if err := json.Unmarshal(bs, &m); err != nil {
+				t.Fatal(err)
+			}
+			m[tt.key] = tt.value
+			if bs, err = json.Marshal(m); err != nil {
+				t.Fatal(err)
+			}
+			err = tt.dst.UnmarshalJSON(bs)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			var le *codec.LimitError
+			if !errors.As(err, &le) || le.Field != tt.field {
+				t.Fatalf("expected the limit error of %s but %v", tt.field, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package account_tx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func executeTx(t *testing.T, loader *test_util.Loader, ctx *data.Context, tx transaction.Transaction, signer byte, coord *common.Coordinate) interface{} {
+	t.Helper()
+	if err := loader.Tran.Validate(ctx, tx, []common.PublicHash{test_util.PublicHash(signer)}); err != nil {
+		t.Fatal(err)
+	}
+	res, err := loader.Tran.Execute(ctx, tx, coord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func transferNameTx(t *testing.T, loader *test_util.Loader, from common.Address, seq uint64, name string, to common.Address) *TransferName {
+	tx := newPolicyTx(t, loader, "fleta.TransferName").(*TransferName)
+	tx.From_ = from
+	tx.Seq_ = seq
+	tx.Name = name
+	tx.To = to
+	return tx
+}
+
+func closeAccountTx(t *testing.T, loader *test_util.Loader, from common.Address, seq uint64, to common.Address) *CloseAccount {
+	tx := newPolicyTx(t, loader, "fleta.CloseAccount").(*CloseAccount)
+	tx.From_ = from
+	tx.Seq_ = seq
+	tx.To = to
+	return tx
+}
+
+func TestCloseOriginalNameHolder(t *testing.T) {
+	loader, ctx := newTestContext(t)
+	a, b := test_util.Address(1), test_util.Address(2)
+	executeTx(t, loader, ctx, transferNameTx(t, loader, a, 1, "testaccount1", b), 1, common.NewCoordinate(10, 0))
+
+	// the account that is created with the name is closed but the name stays with its owner
+	res := executeTx(t, loader, ctx, closeAccountTx(t, loader, a, 2, b), 1, common.NewCoordinate(10, 1)).(*CloseAccountResult)
+	if len(res.ReleasedNames) != 0 {
+		t.Fatalf("expected no released name but %v", res.ReleasedNames)
+	}
+	if owner, err := account_name.OwnerOf(ctx, "testaccount1"); err != nil || owner != b {
+		t.Fatalf("expected the owner %v but %v, %v", b, owner, err)
+	}
+	create := newPolicyTx(t, loader, "fleta.CreateAccount").(*CreateAccount)
+	create.From_ = b
+	create.Seq_ = 1
+	create.Name = "testaccount1"
+	create.KeyHash = test_util.PublicHash(3)
+	if err := loader.Tran.Validate(ctx, create, []common.PublicHash{test_util.PublicHash(2)}); !errors.Is(err, ErrExistAccountName) {
+		t.Fatalf("expected %v but %v", ErrExistAccountName, err)
+	}
+
+	// the name is freed when its owner is closed
+	executeTx(t, loader, ctx, closeAccountTx(t, loader, b, 1, test_util.Address(3)), 2, common.NewCoordinate(10, 2))
+	if is, err := account_name.IsExist(ctx, "testaccount1"); err != nil || is {
+		t.Fatalf("expected the freed name but %v, %v", is, err)
+	}
+}
+
+func TestCloseNewNameOwner(t *testing.T) {
+	loader, ctx := newTestContext(t)
+	a, b := test_util.Address(1), test_util.Address(2)
+	executeTx(t, loader, ctx, transferNameTx(t, loader, a, 1, "testaccount1", b), 1, common.NewCoordinate(10, 0))
+
+	// the name is freed when the new owner is closed and it does not go back to the account that is created with it
+	res := executeTx(t, loader, ctx, closeAccountTx(t, loader, b, 1, a), 2, common.NewCoordinate(10, 1)).(*CloseAccountResult)
+	if len(res.ReleasedNames) != 2 || res.ReleasedNames[0] != "testaccount2" || res.ReleasedNames[1] != "testaccount1" {
+		t.Fatalf("unexpected released names %v", res.ReleasedNames)
+	}
+	if is, err := account_name.IsExist(ctx, "testaccount1"); err != nil || is {
+		t.Fatalf("expected the freed name but %v, %v", is, err)
+	}
+	if _, err := account_name.OwnerOf(ctx, "testaccount1"); !errors.Is(err, account_name.ErrNotExistAccountName) {
+		t.Fatalf("expected %v but %v", account_name.ErrNotExistAccountName, err)
+	}
+	acc, err := ctx.Account(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names, err := account_name.NamesOf(ctx, acc); err != nil || len(names) != 0 {
+		t.Fatalf("expected no name of the original holder but %v, %v", names, err)
+	}
+
+	// a new account takes the freed name
+	create := newPolicyTx(t, loader, "fleta.CreateAccount").(*CreateAccount)
+	create.From_ = a
+	create.Seq_ = 2
+	create.Name = "testaccount1"
+	create.KeyHash = test_util.PublicHash(3)
+	created := executeTx(t, loader, ctx, create, 1, common.NewCoordinate(10, 2)).(*CreateAccountResult)
+	if owner, err := account_name.OwnerOf(ctx, "testaccount1"); err != nil || owner != created.Address {
+		t.Fatalf("expected the owner %v but %v, %v", created.Address, owner, err)
+	}
+	for _, e := range ctx.Top().Events {
+		if ev, is := e.(*event_def.AccountCreatedEvent); is && ev.Name != "testaccount1" {
+			t.Errorf("unexpected name of the created event %v", ev.Name)
+		}
+	}
+
+	// the original holder is closed without taking the name back
+	executeTx(t, loader, ctx, closeAccountTx(t, loader, a, 3, created.Address), 1, common.NewCoordinate(10, 3))
+	if owner, err := account_name.OwnerOf(ctx, "testaccount1"); err != nil || owner != created.Address {
+		t.Fatalf("expected the owner %v but %v, %v", created.Address, owner, err)
+	}
+}
@@ -0,0 +1,155 @@
+package account_tx
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func newTestContext(t *testing.T) (*test_util.Loader, *data.Context) {
+	loader := test_util.NewLoader(common.NewCoordinate(0, 0))
+	loader.Act = test_util.Accounter(t, map[string]account.Type{"fleta.SingleAccount": 1})
+	loader.Tran = test_util.Transactor(t, testTransactionTypes)
+	loader.Evt = test_util.Eventer(t, map[string]event.Type{
+		"fleta.TransferEvent":       1,
+		"fleta.AccountCreatedEvent": 2,
+		"fleta.UTXOCreatedEvent":    3,
+	})
+	loader.Height = 10
+	for n := uint16(1); n <= 3; n++ {
+		a, err := loader.Act.NewByTypeName("fleta.SingleAccount")
+		if err != nil {
+			t.Fatal(err)
+		}
+		acc := a.(*account_def.SingleAccount)
+		acc.Address_ = test_util.Address(n)
+		acc.Name_ = fmt.Sprintf("testaccount%d", n)
+		acc.Balance_ = amount.NewCoinAmount(100, 0)
+		acc.KeyHash = test_util.PublicHash(byte(n))
+		loader.AddAccount(acc, 0)
+	}
+	return loader, data.NewContext(loader)
+}
+
+func newPolicyTx(t *testing.T, loader *test_util.Loader, name string) transaction.Transaction {
+	tx, err := loader.Tran.NewByTypeName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestSetAccountPolicy(t *testing.T) {
+	loader, ctx := newTestContext(t)
+	signer := []common.PublicHash{test_util.PublicHash(1)}
+	admin := []common.PublicHash{test_util.PublicHash(9)}
+
+	first := newPolicyTx(t, loader, "fleta.SetAccountPolicy").(*SetAccountPolicy)
+	first.From_ = test_util.Address(1)
+	first.Seq_ = 1
+	first.Policy.AdminKeyHash = test_util.PublicHash(9)
+	first.Policy.ChangeDelay = 5
+	first.Policy.AllowedTypes = []transaction.Type{testTransactionTypes["fleta.Transfer"], testTransactionTypes["fleta.SetAccountPolicy"]}
+
+	// the first policy is attached by the account key
+	if err := loader.Tran.Validate(ctx, first, admin); !errors.Is(err, account_def.ErrInvalidAccountSigner) {
+		t.Fatalf("expected %v but %v", account_def.ErrInvalidAccountSigner, err)
+	}
+	if err := loader.Tran.Validate(ctx, first, signer); err != nil {
+		t.Fatal(err)
+	}
+	if res, err := loader.Tran.Execute(ctx, first, common.NewCoordinate(10, 0)); err != nil {
+		t.Fatal(err)
+	} else if res.(*SetAccountPolicyResult).EffectiveHeight != 10 {
+		t.Fatalf("expected the immediate policy but %+v", res)
+	}
+
+	// the change of the policy is signed by the admin key and delayed
+	change := newPolicyTx(t, loader, "fleta.SetAccountPolicy").(*SetAccountPolicy)
+	change.From_ = test_util.Address(1)
+	change.Seq_ = 2
+	change.Policy.AdminKeyHash = test_util.PublicHash(9)
+	if err := loader.Tran.Validate(ctx, change, signer); !errors.Is(err, ErrInvalidAdminSigner) {
+		t.Fatalf("expected %v but %v", ErrInvalidAdminSigner, err)
+	}
+	if err := loader.Tran.Validate(ctx, change, admin); err != nil {
+		t.Fatal(err)
+	}
+	if res, err := loader.Tran.Execute(ctx, change, common.NewCoordinate(10, 1)); err != nil {
+		t.Fatal(err)
+	} else if res.(*SetAccountPolicyResult).EffectiveHeight != 15 {
+		t.Fatalf("expected the delayed policy but %+v", res)
+	}
+
+	create := newPolicyTx(t, loader, "fleta.CreateAccount").(*CreateAccount)
+	create.From_ = test_util.Address(1)
+	create.Seq_ = 3
+	create.KeyHash = test_util.PublicHash(3)
+	create.Name = "newaccount"
+	if err := loader.Tran.Validate(ctx, create, signer); !errors.Is(err, account_def.ErrNotAllowedTransactionType) {
+		t.Fatalf("expected %v but %v", account_def.ErrNotAllowedTransactionType, err)
+	}
+	if _, err := loader.Tran.Execute(ctx, create, common.NewCoordinate(10, 2)); !errors.Is(err, account_def.ErrNotAllowedTransactionType) {
+		t.Fatalf("expected %v but %v", account_def.ErrNotAllowedTransactionType, err)
+	}
+	loader.Height = 15
+	if err := loader.Tran.Validate(ctx, create, signer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loader.Tran.Execute(ctx, create, common.NewCoordinate(15, 0)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithdrawPolicy(t *testing.T) {
+	loader, ctx := newTestContext(t)
+	signer := []common.PublicHash{test_util.PublicHash(1)}
+	policy := account_def.NewPolicy()
+	policy.AdminKeyHash = test_util.PublicHash(9)
+	policy.AllowedAddresses = []common.Address{test_util.Address(2)}
+	if err := account_def.SetPolicy(ctx, test_util.Address(1), policy, loader.Height); err != nil {
+		t.Fatal(err)
+	}
+
+	withdraw := newPolicyTx(t, loader, "fleta.Withdraw").(*Withdraw)
+	withdraw.From_ = test_util.Address(1)
+	withdraw.Seq_ = 1
+	withdraw.Vout = []*transaction.TxOut{{Amount: amount.NewCoinAmount(1, 0), PublicHash: test_util.PublicHash(5)}}
+	if err := loader.Tran.Validate(ctx, withdraw, signer); !errors.Is(err, account_def.ErrNotAllowedDestination) {
+		t.Fatalf("expected %v but %v", account_def.ErrNotAllowedDestination, err)
+	}
+	if _, err := loader.Tran.Execute(ctx, withdraw, common.NewCoordinate(10, 0)); !errors.Is(err, account_def.ErrNotAllowedDestination) {
+		t.Fatalf("expected %v but %v", account_def.ErrNotAllowedDestination, err)
+	}
+
+	transfer := newPolicyTx(t, loader, "fleta.Transfer").(*Transfer)
+	transfer.From_ = test_util.Address(1)
+	transfer.Seq_ = 1
+	transfer.To = account_name.NewAddressDestination(test_util.Address(2))
+	transfer.Amount = amount.NewCoinAmount(1, 0)
+	if err := loader.Tran.Validate(ctx, transfer, signer); err != nil {
+		t.Fatal(err)
+	}
+
+	// the outputs of UTXOs are allowed by the UTXO address
+	policy.AllowedAddresses = append(policy.AllowedAddresses, account_def.UTXOAddress)
+	if err := account_def.SetPolicy(ctx, test_util.Address(1), policy, loader.Height); err != nil {
+		t.Fatal(err)
+	}
+	if err := loader.Tran.Validate(ctx, withdraw, signer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loader.Tran.Execute(ctx, withdraw, common.NewCoordinate(10, 0)); err != nil {
+		t.Fatal(err)
+	}
+}
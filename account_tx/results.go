@@ -0,0 +1,56 @@
+package account_tx
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+)
+
+// TransferResult is the execution result of the Transfer
+type TransferResult struct {
+	To     common.Address `json:"to"`
+	ToName string         `json:"to_name,omitempty"`
+	Amount *amount.Amount `json:"amount"`
+}
+
+// WithdrawResult is the execution result of the Withdraw
+type WithdrawResult struct {
+	UTXOs  []uint64       `json:"utxos"`
+	Amount *amount.Amount `json:"amount"`
+}
+
+// BurnResult is the execution result of the Burn
+type BurnResult struct {
+	Amount *amount.Amount `json:"amount"`
+}
+
+// CreateAccountResult is the execution result of the CreateAccount
+type CreateAccountResult struct {
+	Address common.Address `json:"address"`
+	Name    string         `json:"name"`
+}
+
+// CreateMultiSigAccountResult is the execution result of the CreateMultiSigAccount
+type CreateMultiSigAccountResult struct {
+	Address  common.Address `json:"address"`
+	Name     string         `json:"name"`
+	KeyCount int            `json:"key_count"`
+}
+
+// SetAccountPolicyResult is the execution result of the SetAccountPolicy
+type SetAccountPolicyResult struct {
+	EffectiveHeight uint32 `json:"effective_height"`
+}
+
+// TransferNameResult is the execution result of the TransferName
+type TransferNameResult struct {
+	Name string         `json:"name"`
+	From common.Address `json:"from"`
+	To   common.Address `json:"to"`
+}
+
+// CloseAccountResult is the execution result of the CloseAccount
+type CloseAccountResult struct {
+	To            common.Address `json:"to"`
+	Amount        *amount.Amount `json:"amount"`
+	ReleasedNames []string       `json:"released_names"`
+}
@@ -0,0 +1,265 @@
+package account_tx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+var testTransactionTypes = map[string]transaction.Type{
+	"fleta.Transfer":              10,
+	"fleta.Withdraw":              18,
+	"fleta.Burn":                  19,
+	"fleta.CreateAccount":         20,
+	"fleta.CreateMultiSigAccount": 21,
+	"fleta.SetAccountPolicy":      24,
+	"fleta.TransferName":          25,
+	"fleta.CloseAccount":          26,
+}
+
+func testBase(t transaction.Type) Base {
+	return Base{
+		Base:  test_util.TxBase(t),
+		Seq_:  test_util.Seq,
+		From_: test_util.From(),
+	}
+}
+
+func testTransactions() []transaction.Transaction {
+	to := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	policy := account_def.NewPolicy()
+	policy.AdminKeyHash = test_util.PublicHash(9)
+	policy.ChangeDelay = 100
+	policy.WindowSize = 8640
+	policy.SpendLimit = amount.NewCoinAmount(1000, 500000000000000000)
+	policy.AllowedAddresses = []common.Address{to}
+	policy.AllowedTypes = []transaction.Type{10, 18}
+
+	return []transaction.Transaction{
+		&Transfer{Base: testBase(10), Amount: amount.NewCoinAmount(12, 300000000000000000), To: account_name.NewAddressDestination(to), Tag: []byte{1, 2, 3}},
+		&Transfer{Base: testBase(10), Amount: amount.NewCoinAmount(1, 0), To: account_name.NewAddressDestination(to)},
+		&Transfer{Base: testBase(10), Amount: amount.NewCoinAmount(1, 0), To: account_name.NewNameDestination("testaccount")},
+		&Withdraw{Base: testBase(18), Vout: []*transaction.TxOut{
+			{Amount: amount.NewCoinAmount(3, 0), PublicHash: test_util.PublicHash(1)},
+			{Amount: amount.NewCoinAmount(0, 100000000000000000), PublicHash: test_util.PublicHash(2)},
+		}},
+		&Withdraw{Base: testBase(18), Vout: []*transaction.TxOut{}},
+		&Burn{Base: testBase(19), Amount: amount.NewCoinAmount(5, 0)},
+		&CreateAccount{Base: testBase(20), Name: "testaccount", KeyHash: test_util.PublicHash(3)},
+		&CreateMultiSigAccount{Base: testBase(21), Name: "multisigacc", KeyHashes: []common.PublicHash{test_util.PublicHash(4), test_util.PublicHash(5)}},
+		&SetAccountPolicy{Base: testBase(24), Policy: policy},
+		&SetAccountPolicy{Base: testBase(24), Policy: account_def.NewPolicy()},
+		&TransferName{Base: testBase(25), Name: "testaccount", To: to},
+		&CloseAccount{Base: testBase(26), To: to},
+	}
+}
+
+func TestTransactionBinary(t *testing.T) {
+	tran := test_util.Transactor(t, testTransactionTypes)
+	for _, src := range testTransactions() {
+		dst, err := tran.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.BinaryRoundTrip(t, src, dst)
+		if src.Hash() != dst.Hash() {
+			t.Errorf("%T: hash mismatch after binary round trip", src)
+		}
+	}
+}
+
+// Burn has always written Seq_ and From_ again after the base; the layout is
+// part of the transaction hash, so it is pinned here rather than changed.
+func TestBurnWireFormat(t *testing.T) {
+	tx := &Burn{Base: testBase(19), Amount: amount.NewCoinAmount(5, 0)}
+
+	var expected bytes.Buffer
+	if _, err := tx.Base.WriteTo(&expected); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := util.WriteUint64(&expected, tx.Seq_); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.From_.WriteTo(&expected); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Amount.WriteTo(&expected); err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	if _, err := tx.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), expected.Bytes()) {
+		t.Errorf("burn layout changed\n got %x\nwant %x", buffer.Bytes(), expected.Bytes())
+	}
+}
+
+func TestTransferTagLimit(t *testing.T) {
+	tx := &Transfer{Base: testBase(10), Amount: amount.NewCoinAmount(1, 0), To: account_name.NewAddressDestination(common.NewAddress(common.NewCoordinate(3, 4), 0)), Tag: make([]byte, 257)}
+	var buffer bytes.Buffer
+	if _, err := tx.WriteTo(&buffer); !errors.Is(err, codec.ErrExceedByteLength) {
+		t.Fatalf("expected %v but %v", codec.ErrExceedByteLength, err)
+	}
+
+	buffer.Reset()
+	if _, err := tx.Base.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Amount.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.To.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := util.WriteBytes(&buffer, tx.Tag); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := new(Transfer).ReadFrom(&buffer); !errors.Is(err, codec.ErrExceedByteLength) {
+		t.Fatalf("expected %v but %v", codec.ErrExceedByteLength, err)
+	}
+}
+
+func TestReadFromLimits(t *testing.T) {
+	base := testBase(0)
+	writeHeader := func(tb testing.TB, w io.Writer, Type transaction.Type) {
+		b := base
+		b.Type_ = Type
+		if _, err := b.WriteTo(w); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	hugePrefix := func(w io.Writer) {
+		util.WriteUint8(w, 255)
+		util.WriteUint32(w, 0xFFFFFFFF)
+	}
+	tests := []struct {
+		name  string
+		tx    io.ReaderFrom
+		write func(tb testing.TB, w io.Writer)
+		field string
+		err   error
+	}{
+		{
+			name: "transfer tag",
+			tx:   new(Transfer),
+			write: func(tb testing.TB, w io.Writer) {
+				writeHeader(tb, w, 10)
+				to := account_name.NewAddressDestination(common.NewAddress(common.NewCoordinate(3, 4), 0))
+				amount.NewCoinAmount(1, 0).WriteTo(w)
+				to.WriteTo(w)
+				hugePrefix(w)
+			},
+			field: "Transfer.Tag",
+			err:   codec.ErrExceedByteLength,
+		},
+		{
+			name: "create account name",
+			tx:   new(CreateAccount),
+			write: func(tb testing.TB, w io.Writer) {
+				writeHeader(tb, w, 20)
+				hugePrefix(w)
+			},
+			field: "CreateAccount.Name",
+			err:   codec.ErrExceedByteLength,
+		},
+		{
+			name: "multisig key hashes",
+			tx:   new(CreateMultiSigAccount),
+			write: func(tb testing.TB, w io.Writer) {
+				writeHeader(tb, w, 21)
+				util.WriteString(w, "TestAccount")
+				util.WriteUint8(w, 255)
+			},
+			field: "CreateMultiSigAccount.KeyHashes",
+			err:   codec.ErrExceedItemCount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			tt.write(t, &buffer)
+			_, err := tt.tx.ReadFrom(&buffer)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v but %v", tt.err, err)
+			}
+			var le *codec.LimitError
+			if !errors.As(err, &le) || le.Field != tt.field {
+				t.Fatalf("expected the limit error of %s but %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestReadFromTruncated(t *testing.T) {
+	tran := test_util.Transactor(t, testTransactionTypes)
+	for _, src := range testTransactions() {
+		var buffer bytes.Buffer
+		if _, err := src.WriteTo(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		bs := buffer.Bytes()
+		for i := 0; i < len(bs); i++ {
+			tx, err := tran.NewByType(src.Type())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tx.ReadFrom(bytes.NewReader(bs[:i])); err == nil {
+				t.Fatalf("%T: expected an error at %d of %d bytes", src, i, len(bs))
+			}
+		}
+	}
+}
+
+func TestDecodeMessageSize(t *testing.T) {
+	src := &Transfer{Base: testBase(10), Amount: amount.NewCoinAmount(1, 0), To: account_name.NewAddressDestination(common.NewAddress(common.NewCoordinate(3, 4), 0)), Tag: make([]byte, 200)}
+	var buffer bytes.Buffer
+	if _, err := src.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	bs := buffer.Bytes()
+	if _, err := codec.Decode(bytes.NewReader(bs), new(Transfer), int64(len(bs)-1)); err != codec.ErrExceedMessageSize {
+		t.Fatalf("expected %v but %v", codec.ErrExceedMessageSize, err)
+	}
+	if n, err := codec.Decode(bytes.NewReader(bs), new(Transfer), int64(len(bs))); err != nil {
+		t.Fatal(err)
+	} else if n != int64(len(bs)) {
+		t.Fatalf("expected %d bytes but %d", len(bs), n)
+	}
+}
+
+func FuzzTransactionReadFrom(f *testing.F) {
+	tran := test_util.Transactor(f, testTransactionTypes)
+	for _, src := range testTransactions() {
+		var buffer bytes.Buffer
+		if _, err := src.WriteTo(&buffer); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(uint8(src.Type()), buffer.Bytes())
+	}
+	f.Fuzz(func(t *testing.T, Type uint8, bs []byte) {
+		tx, err := tran.NewByType(transaction.Type(Type))
+		if err != nil {
+			return
+		}
+		if _, err := tx.ReadFrom(bytes.NewReader(bs)); err != nil {
+			return
+		}
+		dst, err := tran.NewByType(transaction.Type(Type))
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.BinaryRoundTrip(t, tx, dst)
+	})
+}
@@ -1,15 +1,15 @@
 package account_tx
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/transaction"
 )
@@ -26,11 +26,11 @@ func init() {
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*Burn)
-		if tx.Seq() <= loader.Seq(tx.From()) {
-			return ErrInvalidSequence
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
 		}
-		if tx.Amount.Less(amount.COIN.DivC(10)) {
-			return ErrDustAmount
+		if tx.Seq() <= loader.Seq(tx.From()) {
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
 		}
 
 		fromAcc, err := loader.Account(tx.From())
@@ -38,22 +38,28 @@ func init() {
 			return err
 		}
 
-		if err := loader.Accounter().Validate(loader, fromAcc, signers); err != nil {
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), nil, tx.Amount); err != nil {
 			return err
 		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*Burn)
+		policy := chain.PolicyOf(ctx.ChainCoord())
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		if tx.Seq() != ctx.Seq(tx.From())+1 {
-			return nil, ErrInvalidSequence
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
 		}
 		ctx.AddSeq(tx.From())
 
-		if tx.Amount.Less(amount.COIN.DivC(10)) {
-			return nil, ErrDustAmount
+		if policy.IsDust(tx.Amount) {
+			return nil, errs.WrapValue(ErrDustAmount, tx.Type(), "amount", policy.DustAmount, tx.Amount)
 		}
 
 		fromAcc, err := ctx.Account(tx.From())
@@ -66,16 +72,24 @@ func init() {
 		if err := fromAcc.SubBalance(tx.Amount); err != nil {
 			return nil, err
 		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), nil, tx.Amount); err != nil {
+			return nil, err
+		}
+		if err := em.Transfer(tx.From(), common.Address{}, tx.Amount); err != nil {
+			return nil, err
+		}
 		ctx.Commit(sn)
-		return nil, nil
+		return &BurnResult{
+			Amount: tx.Amount.Clone(),
+		}, nil
 	})
 }
 
 // Burn is a fleta.Burn
 // It is used to burn coin from the account
 type Burn struct {
-	Base
-	Amount *amount.Amount
+	Base   `codec:",repeat"`
+	Amount *amount.Amount `codec:"amount"`
 }
 
 // Hash returns the hash value of it
@@ -83,97 +97,21 @@ func (tx *Burn) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *Burn) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteUint64(w, tx.Seq_); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.From_.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.Amount.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *Burn) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *Burn) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if v, n, err := util.ReadUint64(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Seq_ = v
-	}
-	if n, err := tx.From_.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if n, err := tx.Amount.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	return read, nil
+// OutputCount returns the number of the outputs that it makes
+func (tx *Burn) OutputCount() int {
+	return 0
 }
 
-// MarshalJSON is a marshaler function
-func (tx *Burn) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"seq":`)
-	if bs, err := json.Marshal(tx.Seq_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"from":`)
-	if bs, err := tx.From_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"amount":`)
-	if bs, err := tx.Amount.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *Burn) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	policy := chain.PolicyOf(coord)
+	if policy.IsDust(tx.Amount) {
+		return errs.WrapValue(ErrDustAmount, tx.Type(), "amount", policy.DustAmount, tx.Amount)
 	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	return nil
 }
@@ -0,0 +1,139 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package account_tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/json_util"
+)
+
+// WriteTo is a serialization function
+func (tx *Burn) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tx.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint64(w, tx.Seq_); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tx.From_.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tx.Amount.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tx *Burn) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tx.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint64(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.Seq_ = v
+	}
+	if n, err := tx.From_.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	tx.Amount = amount.NewCoinAmount(0, 0)
+	if n, err := tx.Amount.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Burn) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tx.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tx *Burn) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Type      transaction.Type `json:"type"`
+		Timestamp uint64           `json:"timestamp"`
+		Seq       uint64           `json:"seq"`
+		From      string           `json:"from"`
+		Amount    json.RawMessage  `json:"amount"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tx.Type_ = v.Type
+	tx.Timestamp_ = v.Timestamp
+	tx.Seq_ = v.Seq
+	if addr, err := common.ParseAddress(v.From); err != nil {
+		return err
+	} else {
+		tx.From_ = addr
+	}
+	if am, err := json_util.ParseAmount(v.Amount); err != nil {
+		return err
+	} else {
+		tx.Amount = am
+	}
+	return nil
+}
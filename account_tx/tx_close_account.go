@@ -0,0 +1,149 @@
+package account_tx
+
+import (
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+)
+
+func init() {
+	data.RegisterTransaction("fleta.CloseAccount", func(t transaction.Type) transaction.Transaction {
+		return &CloseAccount{
+			Base: Base{
+				Base: transaction.Base{
+					Type_: t,
+				},
+			},
+		}
+	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
+		tx := t.(*CloseAccount)
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
+		}
+		if tx.Seq() <= loader.Seq(tx.From()) {
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
+		}
+		if _, err := loader.Account(tx.To); err != nil {
+			return err
+		}
+
+		fromAcc, err := loader.Account(tx.From())
+		if err != nil {
+			return err
+		}
+		if Name, err := loader.Accounter().NameByType(fromAcc.Type()); err != nil {
+			return err
+		} else if Name != "fleta.SingleAccount" && Name != "fleta.MultiSigAccount" {
+			return errs.WrapValue(ErrNotClosableAccount, tx.Type(), "from", "fleta.SingleAccount or fleta.MultiSigAccount", Name)
+		}
+
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), []common.Address{tx.To}, fromAcc.Balance()); err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
+		tx := t.(*CloseAccount)
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
+		if tx.To == tx.From() {
+			return nil, errs.WrapValue(ErrSelfCloseAccount, tx.Type(), "to", nil, tx.To)
+		}
+
+		sn := ctx.Snapshot()
+		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
+
+		if tx.Seq() != ctx.Seq(tx.From())+1 {
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
+		}
+		ctx.AddSeq(tx.From())
+
+		fromAcc, err := ctx.Account(tx.From())
+		if err != nil {
+			return nil, err
+		}
+		if Name, err := ctx.Accounter().NameByType(fromAcc.Type()); err != nil {
+			return nil, err
+		} else if Name != "fleta.SingleAccount" && Name != "fleta.MultiSigAccount" {
+			return nil, errs.WrapValue(ErrNotClosableAccount, tx.Type(), "from", "fleta.SingleAccount or fleta.MultiSigAccount", Name)
+		}
+		if err := fromAcc.SubBalance(Fee); err != nil {
+			return nil, err
+		}
+		remain := fromAcc.Balance()
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), []common.Address{tx.To}, remain); err != nil {
+			return nil, err
+		}
+		if err := fromAcc.SubBalance(remain); err != nil {
+			return nil, err
+		}
+
+		toAcc, err := ctx.Account(tx.To)
+		if err != nil {
+			return nil, err
+		}
+		toAcc.AddBalance(remain)
+		if err := em.Transfer(tx.From(), tx.To, remain); err != nil {
+			return nil, err
+		}
+
+		names, err := account_name.NamesOf(ctx, fromAcc)
+		if err != nil {
+			return nil, err
+		}
+		if err := account_name.Release(ctx, fromAcc); err != nil {
+			return nil, err
+		}
+		if err := ctx.DeleteAccount(fromAcc); err != nil {
+			return nil, err
+		}
+		ctx.Commit(sn)
+		return &CloseAccountResult{
+			To:            tx.To,
+			Amount:        remain,
+			ReleasedNames: names,
+		}, nil
+	})
+}
+
+// CloseAccount is a fleta.CloseAccount
+// It is used to delete the account after moving its remaining balance to the other account
+// The names that are owned by the account are released so they can be used by new accounts
+// Only a fleta.SingleAccount or a fleta.MultiSigAccount can be closed
+type CloseAccount struct {
+	Base
+	To common.Address `codec:"to"`
+}
+
+// Hash returns the hash value of it
+func (tx *CloseAccount) Hash() hash.Hash256 {
+	return hash.DoubleHashByWriterTo(tx)
+}
+
+// Size returns the serialized byte size of it
+func (tx *CloseAccount) Size() (int64, error) {
+	return fee.Size(tx)
+}
+
+// OutputCount returns the number of the outputs that it makes
+func (tx *CloseAccount) OutputCount() int {
+	return 1
+}
+
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *CloseAccount) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	if tx.To == tx.From() {
+		return errs.WrapValue(ErrSelfCloseAccount, tx.Type(), "to", nil, tx.To)
+	}
+	return nil
+}
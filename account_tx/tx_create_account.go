@@ -1,16 +1,16 @@
 package account_tx
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/core/amount"
 	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/transaction"
 )
@@ -26,15 +26,12 @@ func init() {
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*CreateAccount)
-		if !transaction.IsMainChain(loader.ChainCoord()) {
-			return ErrNotMainChain
-		}
-		if len(tx.Name) < 8 || len(tx.Name) > 16 {
-			return ErrInvalidAccountName
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
 		}
 
 		if tx.Seq() <= loader.Seq(tx.From()) {
-			return ErrInvalidSequence
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
 		}
 
 		fromAcc, err := loader.Account(tx.From())
@@ -42,30 +39,36 @@ func init() {
 			return err
 		}
 
-		if is, err := loader.IsExistAccountName(tx.Name); err != nil {
+		if is, err := account_name.IsExist(loader, tx.Name); err != nil {
 			return err
 		} else if is {
-			return ErrExistAccountName
+			return errs.WrapValue(ErrExistAccountName, tx.Type(), "name", nil, tx.Name)
 		}
 
-		if err := loader.Accounter().Validate(loader, fromAcc, signers); err != nil {
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), nil, nil); err != nil {
 			return err
 		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*CreateAccount)
+		policy := chain.PolicyOf(ctx.ChainCoord())
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
 		if !transaction.IsMainChain(ctx.ChainCoord()) {
-			return nil, ErrNotMainChain
+			return nil, errs.WrapValue(ErrNotMainChain, tx.Type(), "chain_coord", nil, ctx.ChainCoord())
 		}
-		if len(tx.Name) < 8 || len(tx.Name) > 16 {
-			return nil, ErrInvalidAccountName
+		if err := policy.CheckName(tx.Name); err != nil {
+			return nil, errs.WrapValue(err, tx.Type(), "name", nil, tx.Name)
 		}
 
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		if tx.Seq() != ctx.Seq(tx.From())+1 {
-			return nil, ErrInvalidSequence
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
 		}
 		ctx.AddSeq(tx.From())
 
@@ -76,16 +79,19 @@ func init() {
 		if err := fromAcc.SubBalance(Fee); err != nil {
 			return nil, err
 		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), nil, nil); err != nil {
+			return nil, err
+		}
 
 		addr := common.NewAddress(coord, 0)
 		if is, err := ctx.IsExistAccount(addr); err != nil {
 			return nil, err
 		} else if is {
-			return nil, ErrExistAddress
-		} else if isn, err := ctx.IsExistAccountName(tx.Name); err != nil {
+			return nil, errs.WrapValue(ErrExistAddress, tx.Type(), "address", nil, addr)
+		} else if isn, err := account_name.IsExist(ctx, tx.Name); err != nil {
 			return nil, err
 		} else if isn {
-			return nil, ErrExistAccountName
+			return nil, errs.WrapValue(ErrExistAccountName, tx.Type(), "name", nil, tx.Name)
 		} else {
 			a, err := ctx.Accounter().NewByTypeName("fleta.SingleAccount")
 			if err != nil {
@@ -93,12 +99,22 @@ func init() {
 			}
 			acc := a.(*account_def.SingleAccount)
 			acc.Address_ = addr
-			acc.Name_ = tx.Name
 			acc.KeyHash = tx.KeyHash
+			if claimed, err := account_name.Claim(ctx, tx.Name, addr); err != nil {
+				return nil, err
+			} else if !claimed {
+				acc.Name_ = tx.Name
+			}
 			ctx.CreateAccount(acc)
+			if err := em.AccountCreated(acc, tx.Name); err != nil {
+				return nil, err
+			}
 		}
 		ctx.Commit(sn)
-		return nil, nil
+		return &CreateAccountResult{
+			Address: addr,
+			Name:    tx.Name,
+		}, nil
 	})
 }
 
@@ -106,8 +122,8 @@ func init() {
 // It is used to make a single account
 type CreateAccount struct {
 	Base
-	Name    string
-	KeyHash common.PublicHash
+	Name    string            `codec:"name,max=64"`
+	KeyHash common.PublicHash `codec:"key_hash"`
 }
 
 // Hash returns the hash value of it
@@ -115,94 +131,24 @@ func (tx *CreateAccount) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *CreateAccount) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteString(w, tx.Name); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.KeyHash.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *CreateAccount) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *CreateAccount) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if v, n, err := util.ReadString(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Name = v
-	}
-	if n, err := tx.KeyHash.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	return read, nil
+// OutputCount returns the number of the outputs that it makes
+func (tx *CreateAccount) OutputCount() int {
+	return 1
 }
 
-// MarshalJSON is a marshaler function
-func (tx *CreateAccount) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"seq":`)
-	if bs, err := json.Marshal(tx.Seq_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"from":`)
-	if bs, err := tx.From_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"name":`)
-	if bs, err := json.Marshal(tx.Name); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *CreateAccount) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	policy := chain.PolicyOf(coord)
+	if !transaction.IsMainChain(coord) {
+		return errs.WrapValue(ErrNotMainChain, tx.Type(), "chain_coord", nil, coord)
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"key_hash":`)
-	if bs, err := tx.KeyHash.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+	if err := policy.CheckName(tx.Name); err != nil {
+		return errs.WrapValue(err, tx.Type(), "name", nil, tx.Name)
 	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	return nil
 }
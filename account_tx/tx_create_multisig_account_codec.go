@@ -0,0 +1,176 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package account_tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/json_util"
+)
+
+// WriteTo is a serialization function
+func (tx *CreateMultiSigAccount) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tx.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckByteLength("CreateMultiSigAccount.Name", uint64(len(tx.Name)), 64); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteString(w, tx.Name); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckItemCount("CreateMultiSigAccount.KeyHashes", uint64(len(tx.KeyHashes)), 254); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteUint8(w, uint8(len(tx.KeyHashes))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+		for _, v := range tx.KeyHashes {
+			if n, err := v.WriteTo(w); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tx *CreateMultiSigAccount) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tx.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := codec.ReadString(r, "CreateMultiSigAccount.Name", 64); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.Name = v
+	}
+	if Len, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		if err := codec.CheckItemCount("CreateMultiSigAccount.KeyHashes", uint64(Len), 254); err != nil {
+			return read, err
+		}
+		tx.KeyHashes = make([]common.PublicHash, 0, Len)
+		for i := 0; i < int(Len); i++ {
+			var v common.PublicHash
+			if n, err := v.ReadFrom(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				tx.KeyHashes = append(tx.KeyHashes, v)
+			}
+		}
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *CreateMultiSigAccount) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tx.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(tx.Name); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"key_hashes":`)
+	buffer.WriteString(`[`)
+	for i, v := range tx.KeyHashes {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := v.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tx *CreateMultiSigAccount) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Type      transaction.Type `json:"type"`
+		Timestamp uint64           `json:"timestamp"`
+		Seq       uint64           `json:"seq"`
+		From      string           `json:"from"`
+		Name      string           `json:"name"`
+		KeyHashes []string         `json:"key_hashes"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tx.Type_ = v.Type
+	tx.Timestamp_ = v.Timestamp
+	tx.Seq_ = v.Seq
+	if addr, err := common.ParseAddress(v.From); err != nil {
+		return err
+	} else {
+		tx.From_ = addr
+	}
+	if err := codec.CheckByteLength("CreateMultiSigAccount.Name", uint64(len(v.Name)), 64); err != nil {
+		return err
+	}
+	tx.Name = v.Name
+	if err := codec.CheckItemCount("CreateMultiSigAccount.KeyHashes", uint64(len(v.KeyHashes)), 254); err != nil {
+		return err
+	}
+	if list, err := json_util.ParsePublicHashes(v.KeyHashes); err != nil {
+		return err
+	} else {
+		tx.KeyHashes = list
+	}
+	return nil
+}
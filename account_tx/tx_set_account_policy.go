@@ -0,0 +1,128 @@
+package account_tx
+
+import (
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/fee"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+)
+
+func init() {
+	data.RegisterTransaction("fleta.SetAccountPolicy", func(t transaction.Type) transaction.Transaction {
+		return &SetAccountPolicy{
+			Base: Base{
+				Base: transaction.Base{
+					Type_: t,
+				},
+			},
+			Policy: account_def.NewPolicy(),
+		}
+	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
+		tx := t.(*SetAccountPolicy)
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
+		}
+		if tx.Seq() <= loader.Seq(tx.From()) {
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
+		}
+
+		fromAcc, err := loader.Account(tx.From())
+		if err != nil {
+			return err
+		}
+		if Name, err := loader.Accounter().NameByType(fromAcc.Type()); err != nil {
+			return err
+		} else if Name != "fleta.SingleAccount" && Name != "fleta.MultiSigAccount" {
+			return errs.WrapValue(ErrNotPolicyAccount, tx.Type(), "from", "fleta.SingleAccount or fleta.MultiSigAccount", Name)
+		}
+
+		if current, err := account_def.PolicyOf(loader, tx.From()); err != nil {
+			return err
+		} else if current != nil {
+			if len(signers) != 1 || !current.AdminKeyHash.Equal(signers[0]) {
+				return errs.WrapValue(ErrInvalidAdminSigner, tx.Type(), "signers", current.AdminKeyHash, signers)
+			}
+			return nil
+		}
+
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), nil, nil); err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
+		tx := t.(*SetAccountPolicy)
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
+		if !tx.Policy.IsValid() {
+			return nil, errs.Wrap(ErrInvalidPolicy, tx.Type(), "policy")
+		}
+
+		sn := ctx.Snapshot()
+		defer ctx.Revert(sn)
+
+		if tx.Seq() != ctx.Seq(tx.From())+1 {
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
+		}
+		ctx.AddSeq(tx.From())
+
+		fromAcc, err := ctx.Account(tx.From())
+		if err != nil {
+			return nil, err
+		}
+		if err := fromAcc.SubBalance(Fee); err != nil {
+			return nil, err
+		}
+
+		EffectiveHeight := coord.Height
+		if current, err := account_def.PolicyOf(ctx, tx.From()); err != nil {
+			return nil, err
+		} else if current != nil {
+			EffectiveHeight += current.ChangeDelay
+		}
+		if err := account_def.SetPolicy(ctx, tx.From(), tx.Policy, EffectiveHeight); err != nil {
+			return nil, err
+		}
+		ctx.Commit(sn)
+		return &SetAccountPolicyResult{
+			EffectiveHeight: EffectiveHeight,
+		}, nil
+	})
+}
+
+// SetAccountPolicy is a fleta.SetAccountPolicy
+// It is used to attach the spending policy to the account
+// The first policy is attached by the account keys, and changes are signed by the admin key and delayed by the change delay of the current policy
+type SetAccountPolicy struct {
+	Base
+	Policy *account_def.Policy `codec:"policy"`
+}
+
+// Hash returns the hash value of it
+func (tx *SetAccountPolicy) Hash() hash.Hash256 {
+	return hash.DoubleHashByWriterTo(tx)
+}
+
+// Size returns the serialized byte size of it
+func (tx *SetAccountPolicy) Size() (int64, error) {
+	return fee.Size(tx)
+}
+
+// OutputCount returns the number of the outputs that it makes
+func (tx *SetAccountPolicy) OutputCount() int {
+	return 0
+}
+
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *SetAccountPolicy) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	if !tx.Policy.IsValid() {
+		return errs.Wrap(ErrInvalidPolicy, tx.Type(), "policy")
+	}
+	return nil
+}
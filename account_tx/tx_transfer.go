@@ -1,16 +1,17 @@
 package account_tx
 
 import (
-	"bytes"
-	"encoding/hex"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
+	"github.com/fletaio/extension/memo"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/transaction"
 )
@@ -27,11 +28,11 @@ func init() {
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*Transfer)
-		if tx.Seq() <= loader.Seq(tx.From()) {
-			return ErrInvalidSequence
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
 		}
-		if tx.Amount.Less(amount.COIN.DivC(10)) {
-			return ErrDustAmount
+		if tx.Seq() <= loader.Seq(tx.From()) {
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
 		}
 
 		fromAcc, err := loader.Account(tx.From())
@@ -39,23 +40,37 @@ func init() {
 			return err
 		}
 
-		if err := loader.Accounter().Validate(loader, fromAcc, signers); err != nil {
+		to, err := tx.To.Resolve(loader)
+		if err != nil {
+			return errs.Wrap(err, tx.Type(), "to")
+		}
+
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), []common.Address{to}, tx.Amount); err != nil {
 			return err
 		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*Transfer)
+		policy := chain.PolicyOf(ctx.ChainCoord())
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
 
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		if tx.Seq() != ctx.Seq(tx.From())+1 {
-			return nil, ErrInvalidSequence
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
 		}
 		ctx.AddSeq(tx.From())
 
-		if tx.Amount.Less(amount.COIN.DivC(10)) {
-			return nil, ErrDustAmount
+		if policy.IsDust(tx.Amount) {
+			return nil, errs.WrapValue(ErrDustAmount, tx.Type(), "amount", policy.DustAmount, tx.Amount)
+		}
+		if len(tx.Tag) > policy.MaxTagLength {
+			return nil, errs.WrapValue(ErrTooLongTag, tx.Type(), "tag", policy.MaxTagLength, len(tx.Tag))
 		}
 
 		fromAcc, err := ctx.Account(tx.From())
@@ -68,14 +83,28 @@ func init() {
 		if err := fromAcc.SubBalance(tx.Amount); err != nil {
 			return nil, err
 		}
+		to, err := tx.To.Resolve(ctx)
+		if err != nil {
+			return nil, errs.Wrap(err, tx.Type(), "to")
+		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), []common.Address{to}, tx.Amount); err != nil {
+			return nil, err
+		}
 
-		toAcc, err := ctx.Account(tx.To)
+		toAcc, err := ctx.Account(to)
 		if err != nil {
 			return nil, err
 		}
 		toAcc.AddBalance(tx.Amount)
+		if err := em.Transfer(tx.From(), to, tx.Amount); err != nil {
+			return nil, err
+		}
 		ctx.Commit(sn)
-		return nil, nil
+		return &TransferResult{
+			To:     to,
+			ToName: tx.To.Name,
+			Amount: tx.Amount.Clone(),
+		}, nil
 	})
 }
 
@@ -83,9 +112,9 @@ func init() {
 // It is used to transfer coins between accounts
 type Transfer struct {
 	Base
-	Amount *amount.Amount
-	To     common.Address
-	Tag    []byte
+	Amount *amount.Amount           `codec:"amount"`
+	To     account_name.Destination `codec:"to"` // an address or an account name that is resolved at the execution
+	Tag    []byte                   `codec:"tag,max=256,memo"`
 }
 
 // Hash returns the hash value of it
@@ -93,113 +122,34 @@ func (tx *Transfer) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *Transfer) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.Amount.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.To.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteBytes(w, tx.Tag); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *Transfer) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *Transfer) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if n, err := tx.Amount.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if n, err := tx.To.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if bs, n, err := util.ReadBytes(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Tag = bs
-	}
-	return read, nil
+// OutputCount returns the number of the outputs that it makes
+func (tx *Transfer) OutputCount() int {
+	return 1
 }
 
-// MarshalJSON is a marshaler function
-func (tx *Transfer) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"seq":`)
-	if bs, err := json.Marshal(tx.Seq_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"from":`)
-	if bs, err := tx.From_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *Transfer) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	policy := chain.PolicyOf(coord)
+	if policy.IsDust(tx.Amount) {
+		return errs.WrapValue(ErrDustAmount, tx.Type(), "amount", policy.DustAmount, tx.Amount)
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"amount":`)
-	if bs, err := tx.Amount.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+	if len(tx.Tag) > policy.MaxTagLength {
+		return errs.WrapValue(ErrTooLongTag, tx.Type(), "tag", policy.MaxTagLength, len(tx.Tag))
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"to":`)
-	if bs, err := tx.To.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"tag":`)
-	if len(tx.Tag) == 0 {
-		buffer.WriteString(`null`)
-	} else {
-		buffer.WriteString(`"`)
-		buffer.WriteString(hex.EncodeToString(tx.Tag))
-		buffer.WriteString(`"`)
+	return nil
+}
+
+// SetEncryptedTag sets the tag to the memo that has the text encrypted to the public key of the recipient
+func (tx *Transfer) SetEncryptedTag(pubkey common.PublicKey, text []byte) error {
+	tag, err := memo.EncryptTo(pubkey, text)
+	if err != nil {
+		return err
 	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	tx.Tag = tag
+	return nil
 }
@@ -0,0 +1,177 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package account_tx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/json_util"
+	"github.com/fletaio/extension/memo"
+)
+
+// WriteTo is a serialization function
+func (tx *Transfer) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tx.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tx.Amount.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tx.To.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckByteLength("Transfer.Tag", uint64(len(tx.Tag)), 256); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteBytes(w, tx.Tag); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tx *Transfer) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tx.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	tx.Amount = amount.NewCoinAmount(0, 0)
+	if n, err := tx.Amount.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := tx.To.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := codec.ReadBytes(r, "Transfer.Tag", 256); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.Tag = v
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Transfer) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tx.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"to":`)
+	if bs, err := json.Marshal(tx.To); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"tag":`)
+	if len(tx.Tag) == 0 {
+		buffer.WriteString(`null`)
+	} else {
+		buffer.WriteString(`"`)
+		buffer.WriteString(hex.EncodeToString(tx.Tag))
+		buffer.WriteString(`"`)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"memo":`)
+	if bs, err := memo.MarshalTagJSON(tx.Tag); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tx *Transfer) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Type      transaction.Type         `json:"type"`
+		Timestamp uint64                   `json:"timestamp"`
+		Seq       uint64                   `json:"seq"`
+		From      string                   `json:"from"`
+		Amount    json.RawMessage          `json:"amount"`
+		To        account_name.Destination `json:"to"`
+		Tag       *string                  `json:"tag"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tx.Type_ = v.Type
+	tx.Timestamp_ = v.Timestamp
+	tx.Seq_ = v.Seq
+	if addr, err := common.ParseAddress(v.From); err != nil {
+		return err
+	} else {
+		tx.From_ = addr
+	}
+	if am, err := json_util.ParseAmount(v.Amount); err != nil {
+		return err
+	} else {
+		tx.Amount = am
+	}
+	tx.To = v.To
+	if bs, err := json_util.ParseTag(v.Tag, "Transfer.Tag", 256); err != nil {
+		return err
+	} else {
+		tx.Tag = bs
+	}
+	return nil
+}
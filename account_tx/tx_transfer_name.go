@@ -0,0 +1,131 @@
+package account_tx
+
+import (
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/fee"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+)
+
+func init() {
+	data.RegisterTransaction("fleta.TransferName", func(t transaction.Type) transaction.Transaction {
+		return &TransferName{
+			Base: Base{
+				Base: transaction.Base{
+					Type_: t,
+				},
+			},
+		}
+	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
+		tx := t.(*TransferName)
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
+		}
+		if tx.Seq() <= loader.Seq(tx.From()) {
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
+		}
+
+		if owner, err := account_name.OwnerOf(loader, tx.Name); err != nil {
+			return err
+		} else if owner != tx.From() {
+			return errs.WrapValue(ErrNotNameOwner, tx.Type(), "from", owner, tx.From())
+		}
+		if _, err := loader.Account(tx.To); err != nil {
+			return err
+		}
+
+		fromAcc, err := loader.Account(tx.From())
+		if err != nil {
+			return err
+		}
+
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), []common.Address{tx.To}, nil); err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
+		tx := t.(*TransferName)
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
+		if !account_name.IsCanonical(tx.Name) {
+			return nil, errs.WrapValue(ErrInvalidAccountName, tx.Type(), "name", nil, tx.Name)
+		}
+		if tx.To == tx.From() {
+			return nil, errs.WrapValue(ErrSelfNameTransfer, tx.Type(), "to", nil, tx.To)
+		}
+
+		sn := ctx.Snapshot()
+		defer ctx.Revert(sn)
+
+		if tx.Seq() != ctx.Seq(tx.From())+1 {
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
+		}
+		ctx.AddSeq(tx.From())
+
+		fromAcc, err := ctx.Account(tx.From())
+		if err != nil {
+			return nil, err
+		}
+		if err := fromAcc.SubBalance(Fee); err != nil {
+			return nil, err
+		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), []common.Address{tx.To}, nil); err != nil {
+			return nil, err
+		}
+
+		if _, err := ctx.Account(tx.To); err != nil {
+			return nil, err
+		}
+		if err := account_name.Transfer(ctx, tx.Name, tx.From(), tx.To); err != nil {
+			return nil, err
+		}
+		ctx.Commit(sn)
+		return &TransferNameResult{
+			Name: tx.Name,
+			From: tx.From(),
+			To:   tx.To,
+		}, nil
+	})
+}
+
+// TransferName is a fleta.TransferName
+// It is used to transfer the name that is owned by the account to the other account
+type TransferName struct {
+	Base
+	Name string         `codec:"name,max=64"`
+	To   common.Address `codec:"to"`
+}
+
+// Hash returns the hash value of it
+func (tx *TransferName) Hash() hash.Hash256 {
+	return hash.DoubleHashByWriterTo(tx)
+}
+
+// Size returns the serialized byte size of it
+func (tx *TransferName) Size() (int64, error) {
+	return fee.Size(tx)
+}
+
+// OutputCount returns the number of the outputs that it makes
+func (tx *TransferName) OutputCount() int {
+	return 0
+}
+
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *TransferName) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	if !account_name.IsCanonical(tx.Name) {
+		return errs.WrapValue(ErrInvalidAccountName, tx.Type(), "name", nil, tx.Name)
+	}
+	if tx.To == tx.From() {
+		return errs.WrapValue(ErrSelfNameTransfer, tx.Type(), "to", nil, tx.To)
+	}
+	return nil
+}
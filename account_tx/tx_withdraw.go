@@ -1,15 +1,15 @@
 package account_tx
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/transaction"
 )
@@ -26,42 +26,55 @@ func init() {
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*Withdraw)
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
+		}
 		if tx.Seq() <= loader.Seq(tx.From()) {
-			return ErrInvalidSequence
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
 		}
 
 		fromAcc, err := loader.Account(tx.From())
 		if err != nil {
 			return err
 		}
+		spend := amount.NewCoinAmount(0, 0)
 		for _, vout := range tx.Vout {
-			if vout.Amount.Less(amount.COIN.DivC(10)) {
-				return ErrDustAmount
-			}
+			spend = spend.Add(vout.Amount)
 		}
 
-		if err := loader.Accounter().Validate(loader, fromAcc, signers); err != nil {
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), []common.Address{account_def.UTXOAddress}, spend); err != nil {
 			return err
 		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*Withdraw)
+		policy := chain.PolicyOf(ctx.ChainCoord())
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
 
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		if tx.Seq() != ctx.Seq(tx.From())+1 {
-			return nil, ErrInvalidSequence
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
 		}
 		ctx.AddSeq(tx.From())
 
-		outsum := Fee.Clone()
+		outsum := tx.Charge(Fee)
+		ids := make([]uint64, 0, len(tx.Vout))
 		for n, vout := range tx.Vout {
-			if vout.Amount.Less(amount.COIN.DivC(10)) {
-				return nil, ErrDustAmount
+			if policy.IsDust(vout.Amount) {
+				return nil, errs.WrapValue(ErrDustAmount, tx.Type(), "vout", policy.DustAmount, vout.Amount)
 			}
-			outsum = outsum.Add(vout.Amount)
-			if err := ctx.CreateUTXO(transaction.MarshalID(coord.Height, coord.Index, uint16(n)), vout); err != nil {
+			id := transaction.MarshalID(coord.Height, coord.Index, uint16(n))
+			if err := ctx.CreateUTXO(id, vout); err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+			if err := em.UTXOCreated(id, vout); err != nil {
 				return nil, err
 			}
 		}
@@ -76,8 +89,17 @@ func init() {
 		if err := fromAcc.SubBalance(outsum); err != nil {
 			return nil, err
 		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), []common.Address{account_def.UTXOAddress}, outsum.Sub(Fee)); err != nil {
+			return nil, err
+		}
+		if err := em.Transfer(tx.From(), common.Address{}, outsum.Sub(Fee)); err != nil {
+			return nil, err
+		}
 		ctx.Commit(sn)
-		return nil, nil
+		return &WithdrawResult{
+			UTXOs:  ids,
+			Amount: outsum.Sub(Fee),
+		}, nil
 	})
 }
 
@@ -85,7 +107,7 @@ func init() {
 // It is used to make UTXO from the account
 type Withdraw struct {
 	Base
-	Vout []*transaction.TxOut
+	Vout []*transaction.TxOut `codec:"vout"`
 }
 
 // Hash returns the hash value of it
@@ -93,100 +115,33 @@ func (tx *Withdraw) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *Withdraw) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteUint8(w, uint8(len(tx.Vout))); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-		for _, v := range tx.Vout {
-			if n, err := v.WriteTo(w); err != nil {
-				return wrote, err
-			} else {
-				wrote += n
-			}
-		}
-	}
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *Withdraw) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *Withdraw) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
+// Charge returns the amount that is subtracted from the balance by the execution after the fee
+// It is the sum of the outputs with the fee, so the fee is paid twice by a withdrawal
+func (tx *Withdraw) Charge(Fee *amount.Amount) *amount.Amount {
+	outsum := Fee.Clone()
+	for _, vout := range tx.Vout {
+		outsum = outsum.Add(vout.Amount)
 	}
-	if Len, n, err := util.ReadUint8(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Vout = make([]*transaction.TxOut, 0, Len)
-		for i := 0; i < int(Len); i++ {
-			vout := transaction.NewTxOut()
-			if n, err := vout.ReadFrom(r); err != nil {
-				return read, err
-			} else {
-				read += n
-				tx.Vout = append(tx.Vout, vout)
-			}
-		}
-	}
-	return read, nil
+	return outsum
 }
 
-// MarshalJSON is a marshaler function
-func (tx *Withdraw) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"seq":`)
-	if bs, err := json.Marshal(tx.Seq_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"from":`)
-	if bs, err := tx.From_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"vout":`)
-	buffer.WriteString(`[`)
-	for i, vout := range tx.Vout {
-		if i > 0 {
-			buffer.WriteString(`,`)
-		}
-		if bs, err := vout.MarshalJSON(); err != nil {
-			return nil, err
-		} else {
-			buffer.Write(bs)
+// OutputCount returns the number of the outputs that it makes
+func (tx *Withdraw) OutputCount() int {
+	return len(tx.Vout)
+}
+
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *Withdraw) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	policy := chain.PolicyOf(coord)
+	for _, vout := range tx.Vout {
+		if policy.IsDust(vout.Amount) {
+			return errs.WrapValue(ErrDustAmount, tx.Type(), "vout", policy.DustAmount, vout.Amount)
 		}
 	}
-	buffer.WriteString(`]`)
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	return nil
 }
@@ -0,0 +1,147 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package account_tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/json_util"
+)
+
+// WriteTo is a serialization function
+func (tx *Withdraw) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tx.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckItemCount("Withdraw.Vout", uint64(len(tx.Vout)), 255); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteUint8(w, uint8(len(tx.Vout))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+		for _, v := range tx.Vout {
+			if n, err := v.WriteTo(w); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tx *Withdraw) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tx.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if Len, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.Vout = make([]*transaction.TxOut, 0, Len)
+		for i := 0; i < int(Len); i++ {
+			v := transaction.NewTxOut()
+			if n, err := v.ReadFrom(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				tx.Vout = append(tx.Vout, v)
+			}
+		}
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *Withdraw) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tx.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"vout":`)
+	buffer.WriteString(`[`)
+	for i, v := range tx.Vout {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := v.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tx *Withdraw) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Type      transaction.Type  `json:"type"`
+		Timestamp uint64            `json:"timestamp"`
+		Seq       uint64            `json:"seq"`
+		From      string            `json:"from"`
+		Vout      []json.RawMessage `json:"vout"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tx.Type_ = v.Type
+	tx.Timestamp_ = v.Timestamp
+	tx.Seq_ = v.Seq
+	if addr, err := common.ParseAddress(v.From); err != nil {
+		return err
+	} else {
+		tx.From_ = addr
+	}
+	if err := codec.CheckItemCount("Withdraw.Vout", uint64(len(v.Vout)), 255); err != nil {
+		return err
+	}
+	if list, err := json_util.ParseVout(v.Vout); err != nil {
+		return err
+	} else {
+		tx.Vout = list
+	}
+	return nil
+}
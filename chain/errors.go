@@ -0,0 +1,12 @@
+package chain
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// chain errors
+var (
+	ErrInvalidChainPolicy  = errs.ErrInvalidChainPolicy
+	ErrInvalidAccountName  = errs.ErrInvalidAccountName
+	ErrReservedAccountName = errs.ErrReservedAccountName
+)
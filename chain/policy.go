@@ -0,0 +1,144 @@
+// Package chain holds the policy parameters of each chain that are consulted by the extension validators and executors.
+package chain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/memo"
+)
+
+// bounds of the policy parameters that are limited by the codecs of the transactions
+const (
+	MaxNameLength           = account_name.MaxNameLength
+	MaxMultiSigKeyHashCount = 254
+)
+
+// Range is an inclusive range of lengths or counts
+type Range struct {
+	Min int
+	Max int
+}
+
+// Contains returns true when n is in the range
+func (r Range) Contains(n int) bool {
+	return n >= r.Min && n <= r.Max
+}
+
+// String returns the text form of the range
+func (r Range) String() string {
+	return fmt.Sprintf("%d to %d", r.Min, r.Max)
+}
+
+// Policy is the parameters of the chain that are applied to the extension transactions
+type Policy struct {
+	DustAmount           *amount.Amount // an output that is less than it is rejected
+	NameLength           Range          // the byte length of account names
+	MultiSigKeyHashCount Range          // the key hash count of multisig accounts
+	MaxTagLength         int            // the byte length of tags that is not greater than memo.MaxTagLength
+	ReservedNames        []string       // the canonical names that cannot be used by new accounts
+}
+
+// DefaultPolicy returns the policy of the chain that has no registered policy
+func DefaultPolicy() *Policy {
+	return &Policy{
+		DustAmount:           amount.COIN.DivC(10),
+		NameLength:           Range{Min: 8, Max: 16},
+		MultiSigKeyHashCount: Range{Min: 2, Max: 10},
+		MaxTagLength:         memo.MaxTagLength,
+	}
+}
+
+// IsValid returns true when the parameters are in their bounds
+func (p *Policy) IsValid() bool {
+	if p.DustAmount == nil || p.DustAmount.IsMinus() {
+		return false
+	}
+	if p.NameLength.Min < 1 || p.NameLength.Min > p.NameLength.Max || p.NameLength.Max > MaxNameLength {
+		return false
+	}
+	if p.MultiSigKeyHashCount.Min < 2 || p.MultiSigKeyHashCount.Min > p.MultiSigKeyHashCount.Max || p.MultiSigKeyHashCount.Max > MaxMultiSigKeyHashCount {
+		return false
+	}
+	if p.MaxTagLength < 0 || p.MaxTagLength > memo.MaxTagLength {
+		return false
+	}
+	for _, name := range p.ReservedNames {
+		if !account_name.IsCanonical(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsDust returns true when the amount is less than the dust amount
+func (p *Policy) IsDust(a *amount.Amount) bool {
+	return a.Less(p.DustAmount)
+}
+
+// IsValidNameLength returns true when the byte length of the name is in the range
+func (p *Policy) IsValidNameLength(name string) bool {
+	return p.NameLength.Contains(len(name))
+}
+
+// IsReservedName returns true when the name is reserved
+func (p *Policy) IsReservedName(name string) bool {
+	for _, v := range p.ReservedNames {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckName returns an error when the name cannot be used by a new account
+func (p *Policy) CheckName(name string) error {
+	if !p.IsValidNameLength(name) || !account_name.IsCanonical(name) {
+		return ErrInvalidAccountName
+	}
+	if p.IsReservedName(name) {
+		return ErrReservedAccountName
+	}
+	return nil
+}
+
+// Clone returns the copy of the policy
+func (p *Policy) Clone() *Policy {
+	c := *p
+	c.DustAmount = p.DustAmount.Clone()
+	c.ReservedNames = append([]string{}, p.ReservedNames...)
+	return &c
+}
+
+var policyLock sync.RWMutex
+var policyMap = map[common.Coordinate]*Policy{}
+
+// RegisterPolicy sets the policy of the chain
+func RegisterPolicy(coord *common.Coordinate, p *Policy) error {
+	if !p.IsValid() {
+		return ErrInvalidChainPolicy
+	}
+
+	policyLock.Lock()
+	defer policyLock.Unlock()
+
+	policyMap[*coord] = p.Clone()
+	return nil
+}
+
+// PolicyOf returns the policy of the chain or the default policy if it is not registered
+// The returned policy is shared so it must not be modified
+func PolicyOf(coord *common.Coordinate) *Policy {
+	policyLock.RLock()
+	defer policyLock.RUnlock()
+
+	if p, has := policyMap[*coord]; has {
+		return p
+	}
+	return defaultPolicy
+}
+
+var defaultPolicy = DefaultPolicy()
@@ -0,0 +1,88 @@
+package chain
+
+import (
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	p := DefaultPolicy()
+	if !p.IsValid() {
+		t.Fatal("the default policy is invalid")
+	}
+	if !p.IsDust(amount.COIN.DivC(20)) || p.IsDust(amount.COIN.DivC(10)) {
+		t.Fatal("unexpected dust amount of the default policy")
+	}
+	if p.IsValidNameLength("short") || !p.IsValidNameLength("TestAccount") || p.IsValidNameLength("TestAccountTooLong") {
+		t.Fatal("unexpected name length of the default policy")
+	}
+	if p.MultiSigKeyHashCount.Contains(1) || !p.MultiSigKeyHashCount.Contains(10) || p.MultiSigKeyHashCount.Contains(11) {
+		t.Fatal("unexpected multisig key hash count of the default policy")
+	}
+}
+
+func TestRegisterPolicy(t *testing.T) {
+	coord := common.NewCoordinate(100, 1)
+	other := common.NewCoordinate(100, 2)
+
+	p := DefaultPolicy()
+	p.DustAmount = amount.NewCoinAmount(0, 0)
+	p.NameLength = Range{Min: 4, Max: 32}
+	if err := RegisterPolicy(coord, p); err != nil {
+		t.Fatal(err)
+	}
+	p.NameLength.Max = 1 // the registered policy is not changed by the caller
+
+	if got := PolicyOf(coord); got.NameLength != (Range{Min: 4, Max: 32}) || !got.DustAmount.IsZero() {
+		t.Fatalf("unexpected registered policy %+v", got)
+	}
+	if got := PolicyOf(other); got.NameLength != DefaultPolicy().NameLength {
+		t.Fatalf("expected the default policy but %+v", got)
+	}
+}
+
+func TestRegisterInvalidPolicy(t *testing.T) {
+	coord := common.NewCoordinate(100, 3)
+	tests := []func(p *Policy){
+		func(p *Policy) { p.DustAmount = nil },
+		func(p *Policy) { p.NameLength = Range{Min: 0, Max: 16} },
+		func(p *Policy) { p.NameLength = Range{Min: 8, Max: MaxNameLength + 1} },
+		func(p *Policy) { p.MultiSigKeyHashCount = Range{Min: 1, Max: 10} },
+		func(p *Policy) { p.MultiSigKeyHashCount = Range{Min: 5, Max: 4} },
+		func(p *Policy) { p.MaxTagLength = 1024 },
+	}
+	for i, modify := range tests {
+		p := DefaultPolicy()
+		modify(p)
+		if err := RegisterPolicy(coord, p); err != ErrInvalidChainPolicy {
+			t.Fatalf("%d: expected %v but %v", i, ErrInvalidChainPolicy, err)
+		}
+	}
+}
+
+func TestCheckName(t *testing.T) {
+	p := DefaultPolicy()
+	p.ReservedNames = []string{"fletafoundation"}
+	if !p.IsValid() {
+		t.Fatal("the policy is invalid")
+	}
+	tests := map[string]error{
+		"testaccount":     nil,
+		"short":           ErrInvalidAccountName,
+		"TestAccount":     ErrInvalidAccountName,
+		"test account":    ErrInvalidAccountName,
+		"fletafoundation": ErrReservedAccountName,
+	}
+	for name, expected := range tests {
+		if err := p.CheckName(name); err != expected {
+			t.Errorf("%q: expected %v but %v", name, expected, err)
+		}
+	}
+
+	p.ReservedNames = []string{"Not Canonical"}
+	if p.IsValid() {
+		t.Fatal("the policy that has a reserved name out of the canonical form is valid")
+	}
+}
@@ -0,0 +1,258 @@
+package chainkit
+
+import (
+	"encoding/hex"
+	"net"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/consensus"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/formulator"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/key"
+	"github.com/fletaio/core/observer"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/framework/peer"
+	"github.com/fletaio/framework/router"
+	"github.com/fletaio/framework/router/evilnode"
+)
+
+// Chain is the observers and the formulators of the chain that are built from the config
+type Chain struct {
+	Config            *Config
+	Observers         []*observer.Observer
+	ObserverKernels   []*kernel.Kernel
+	Formulators       []*formulator.Formulator
+	FormulatorKernels []*kernel.Kernel
+}
+
+// New builds the kernels, observers and formulators of the chain
+func New(cfg *Config) (*Chain, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	obkeys := make([]key.Key, 0, len(cfg.Observers))
+	ObserverKeyMap := map[common.PublicHash]bool{}
+	NetAddressMap := map[common.PublicHash]string{}
+	NetAddressMapForFr := map[common.PublicHash]string{}
+	for _, v := range cfg.Observers {
+		Key, err := ParseKey(v.Key)
+		if err != nil {
+			return nil, err
+		}
+		obkeys = append(obkeys, Key)
+		pubhash := common.NewPublicHash(Key.PublicKey())
+		ObserverKeyMap[pubhash] = true
+		NetAddressMap[pubhash] = v.ObserverAddress
+		NetAddressMapForFr[pubhash] = v.FormulatorAddress
+	}
+
+	c := &Chain{
+		Config: cfg,
+	}
+	for _, obkey := range obkeys {
+		StoreRoot := cfg.StoreRoot + "/observer/" + common.NewPublicHash(obkey.PublicKey()).String()
+		kn, err := NewKernel(cfg, StoreRoot, ObserverKeyMap)
+		if err != nil {
+			return nil, err
+		}
+		ob, err := observer.NewObserver(&observer.Config{
+			ChainCoord:     cfg.ChainCoord,
+			Key:            obkey,
+			ObserverKeyMap: NetAddressMap,
+		}, kn)
+		if err != nil {
+			return nil, err
+		}
+		c.Observers = append(c.Observers, ob)
+		c.ObserverKernels = append(c.ObserverKernels, kn)
+	}
+
+	for _, v := range cfg.Formulators {
+		frkey, err := ParseKey(v.Key)
+		if err != nil {
+			return nil, err
+		}
+		StoreRoot := cfg.StoreRoot + "/formulator/" + common.NewPublicHash(frkey.PublicKey()).String()
+		kn, err := NewKernel(cfg, StoreRoot, ObserverKeyMap)
+		if err != nil {
+			return nil, err
+		}
+		fr, err := formulator.NewFormulator(&formulator.Config{
+			Key:            frkey,
+			ObserverKeyMap: NetAddressMapForFr,
+			Formulator:     v.Address,
+			Router: router.Config{
+				Network: "tcp",
+				Port:    v.Port,
+				EvilNodeConfig: evilnode.Config{
+					StorePath: StoreRoot + "/router",
+				},
+			},
+			Peer: peer.Config{
+				StorePath: StoreRoot + "/peers",
+			},
+		}, kn)
+		if err != nil {
+			return nil, err
+		}
+		c.Formulators = append(c.Formulators, fr)
+		c.FormulatorKernels = append(c.FormulatorKernels, kn)
+	}
+	return c, nil
+}
+
+// Kernel returns the kernel of the last formulator or the first observer when there is no formulator
+func (c *Chain) Kernel() *kernel.Kernel {
+	if len(c.FormulatorKernels) > 0 {
+		return c.FormulatorKernels[len(c.FormulatorKernels)-1]
+	}
+	return c.ObserverKernels[0]
+}
+
+// RunObservers runs the observers in the background using the ports of the observer nodes
+func (c *Chain) RunObservers() {
+	for i, ob := range c.Observers {
+		node := c.Config.Observers[i]
+		go func(BindOb string, BindFr string, ob *observer.Observer) {
+			ob.Run(BindOb, BindFr)
+		}(bindAddress(node.ObserverAddress), bindAddress(node.FormulatorAddress), ob)
+	}
+}
+
+// RunFormulators runs the formulators in the background
+func (c *Chain) RunFormulators() {
+	for _, fr := range c.Formulators {
+		go func(fr *formulator.Formulator) {
+			fr.Run()
+		}(fr)
+	}
+}
+
+// NewKernel returns a kernel of the chain that is stored at the path
+func NewKernel(cfg *Config, StoreRoot string, ObserverKeyMap map[common.PublicHash]bool) (*kernel.Kernel, error) {
+	if err := RegisterPolicy(cfg); err != nil {
+		return nil, err
+	}
+	act := data.NewAccounter(cfg.ChainCoord)
+	tran := data.NewTransactor(cfg.ChainCoord)
+	evt := data.NewEventer(cfg.ChainCoord)
+	if err := RegisterTypes(cfg, act, tran, evt); err != nil {
+		return nil, err
+	}
+	GenesisContextData, err := NewGenesisContextData(cfg, act, tran, evt)
+	if err != nil {
+		return nil, err
+	}
+	ks, err := kernel.NewStore(StoreRoot+"/kernel", cfg.Version, act, tran, evt, true)
+	if err != nil {
+		return nil, err
+	}
+	return kernel.NewKernel(&kernel.Config{
+		ChainCoord:              cfg.ChainCoord,
+		ObserverKeyMap:          ObserverKeyMap,
+		MaxBlocksPerFormulator:  cfg.MaxBlocksPerFormulator,
+		MaxTransactionsPerBlock: cfg.MaxTransactionsPerBlock,
+	}, ks, cfg.Rewarder, GenesisContextData)
+}
+
+// RegisterPolicy registers the policy of the config or the genesis file to the chain package
+// The chain without a policy uses the default policy
+func RegisterPolicy(cfg *Config) error {
+	if p := cfg.ChainPolicy(); p != nil {
+		if err := chain.RegisterPolicy(cfg.ChainCoord, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterTypes registers the transaction, account and event types of the config
+func RegisterTypes(cfg *Config, act *data.Accounter, tran *data.Transactor, evt *data.Eventer) error {
+	for _, v := range cfg.Transactions {
+		if err := tran.RegisterType(v.Name, v.Type, v.Fee); err != nil {
+			return err
+		}
+	}
+	for _, v := range cfg.Accounts {
+		if err := act.RegisterType(v.Name, v.Type); err != nil {
+			return err
+		}
+	}
+	for _, v := range cfg.Events {
+		if err := evt.RegisterType(v.Name, v.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewGenesisContextData returns the context data that creates the genesis file and the genesis accounts of the config
+func NewGenesisContextData(cfg *Config, act *data.Accounter, tran *data.Transactor, evt *data.Eventer) (*data.ContextData, error) {
+	var ctd *data.ContextData
+	if cfg.Genesis != nil {
+		GenesisContextData, err := cfg.Genesis.ContextData(act, tran, evt)
+		if err != nil {
+			return nil, err
+		}
+		ctd = GenesisContextData
+	} else {
+		loader := data.NewEmptyLoader(act.ChainCoord(), act, tran, evt)
+		ctd = data.NewContextData(loader, nil)
+	}
+	for _, v := range cfg.GenesisAccounts {
+		a, err := act.NewByTypeName(v.Type)
+		if err != nil {
+			return nil, err
+		}
+		Balance := v.Balance
+		if Balance == nil {
+			Balance = amount.NewCoinAmount(0, 0)
+		}
+		switch acc := a.(type) {
+		case *account_def.SingleAccount:
+			acc.Address_ = v.Address
+			acc.Name_ = v.Name
+			acc.Balance_ = Balance
+			acc.KeyHash = v.KeyHash
+		case *account_def.LockedAccount:
+			acc.Address_ = v.Address
+			acc.Name_ = v.Name
+			acc.Balance_ = Balance
+			acc.UnlockHeight = v.UnlockHeight
+			acc.KeyHash = v.KeyHash
+		case *consensus.FormulationAccount:
+			acc.Address_ = v.Address
+			acc.Name_ = v.Name
+			acc.Amount = amount.NewCoinAmount(0, 0)
+			acc.Balance_ = Balance
+			acc.KeyHash = v.KeyHash
+		default:
+			return nil, errs.WrapField(ErrUnsupportedGenesisAccount, "type", "single, locked or formulation account", v.Type)
+		}
+		ctd.CreatedAccountMap[a.Address()] = a
+	}
+	return ctd, nil
+}
+
+// ParseKey returns the memory key of the hex encoded private key
+func ParseKey(str string) (key.Key, error) {
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	Key, err := key.NewMemoryKeyFromBytes(bs)
+	if err != nil {
+		return nil, err
+	}
+	return Key, nil
+}
+
+func bindAddress(addr string) string {
+	_, port, _ := net.SplitHostPort(addr)
+	return ":" + port
+}
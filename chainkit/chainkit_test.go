@@ -0,0 +1,187 @@
+package chainkit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/account_def"
+	_ "github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/genesis"
+	"github.com/fletaio/extension/type_registry"
+)
+
+type testRewarder struct{}
+
+func (rd *testRewarder) ProcessReward(addr common.Address, ctx *data.Context) error {
+	return nil
+}
+
+func testConfig() *Config {
+	return &Config{
+		ChainCoord: common.NewCoordinate(0, 0),
+		Version:    1,
+		StoreRoot:  "./testchain",
+		Rewarder:   &testRewarder{},
+		Transactions: []TransactionType{
+			{Name: "fleta.Transfer", Type: 10, Fee: amount.COIN.DivC(10)},
+			{Name: "fleta.CreateAccount", Type: 20, Fee: amount.COIN.MulC(10)},
+		},
+		Accounts: []AccountType{
+			{Name: "fleta.SingleAccount", Type: 10},
+			{Name: "fleta.MultiSigAccount", Type: 11},
+			{Name: "fleta.LockedAccount", Type: 19},
+		},
+		Observers: []ObserverNode{
+			{Key: "cd7cca6359869f4f58bb31aa11c2c4825d4621406f7b514058bc4dbe788c29be", ObserverAddress: "127.0.0.1:3001", FormulatorAddress: "127.0.0.1:5001"},
+		},
+		GenesisAccounts: []GenesisAccount{
+			{Type: "fleta.SingleAccount", Address: common.NewAddress(common.NewCoordinate(0, 0), 0), Name: "genesisaccount", Balance: amount.NewCoinAmount(100, 0)},
+			{Type: "fleta.LockedAccount", Address: common.NewAddress(common.NewCoordinate(0, 1), 0), Name: "lockedaccount", UnlockHeight: 1000},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := testConfig().Validate(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		field  string
+		modify func(cfg *Config)
+	}{
+		{"chain_coord", func(cfg *Config) { cfg.ChainCoord = nil }},
+		{"store_root", func(cfg *Config) { cfg.StoreRoot = "" }},
+		{"rewarder", func(cfg *Config) { cfg.Rewarder = nil }},
+		{"observers", func(cfg *Config) { cfg.Observers = nil }},
+		{"transactions", func(cfg *Config) { cfg.Transactions[1].Type = 10 }},
+		{"transactions.fee", func(cfg *Config) { cfg.Transactions[0].Fee = nil }},
+		{"accounts", func(cfg *Config) { cfg.Accounts[1].Name = "fleta.SingleAccount" }},
+		{"observers.observer_address", func(cfg *Config) { cfg.Observers[0].ObserverAddress = "3001" }},
+		{"genesis_accounts.type", func(cfg *Config) { cfg.GenesisAccounts[0].Type = "fleta.TokenAccount" }},
+		{"genesis_accounts.address", func(cfg *Config) { cfg.GenesisAccounts[1].Address = cfg.GenesisAccounts[0].Address }},
+		{"genesis_accounts.name", func(cfg *Config) { cfg.GenesisAccounts[0].Name = "genesisAccount" }},
+		{"genesis_accounts.name", func(cfg *Config) { cfg.GenesisAccounts[0].Name = "genesis" }},
+		{"genesis_accounts.name", func(cfg *Config) { cfg.GenesisAccounts[1].Name = cfg.GenesisAccounts[0].Name }},
+	}
+	for _, tt := range tests {
+		cfg := testConfig()
+		tt.modify(cfg)
+		err := cfg.Validate()
+		if errs.CodeOf(err) != CodeInvalidChainConfig {
+			t.Errorf("%s: expected the invalid chain config but %v", tt.field, err)
+			continue
+		}
+		var cerr *errs.ContextError
+		if !errors.As(err, &cerr) || cerr.Field != tt.field {
+			t.Errorf("%s: unexpected field of %v", tt.field, err)
+		}
+	}
+}
+
+func TestNewGenesisContextData(t *testing.T) {
+	cfg := testConfig()
+	act := data.NewAccounter(cfg.ChainCoord)
+	tran := data.NewTransactor(cfg.ChainCoord)
+	evt := data.NewEventer(cfg.ChainCoord)
+	if err := RegisterTypes(cfg, act, tran, evt); err != nil {
+		t.Fatal(err)
+	}
+	if fee, err := tran.Fee(10); err != nil || !fee.Equal(amount.COIN.DivC(10)) {
+		t.Fatalf("unexpected fee %v %v", fee, err)
+	}
+
+	ctd, err := NewGenesisContextData(cfg, act, tran, evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ctd.CreatedAccountMap) != 2 {
+		t.Fatalf("expected 2 accounts but %d", len(ctd.CreatedAccountMap))
+	}
+	single := ctd.CreatedAccountMap[cfg.GenesisAccounts[0].Address].(*account_def.SingleAccount)
+	if single.Name() != "genesisaccount" || !single.Balance().Equal(amount.NewCoinAmount(100, 0)) {
+		t.Errorf("unexpected single account %v %v", single.Name(), single.Balance())
+	}
+	locked := ctd.CreatedAccountMap[cfg.GenesisAccounts[1].Address].(*account_def.LockedAccount)
+	if locked.UnlockHeight != 1000 || !locked.Balance().IsZero() {
+		t.Errorf("unexpected locked account %v %v", locked.UnlockHeight, locked.Balance())
+	}
+
+	cfg.GenesisAccounts = append(cfg.GenesisAccounts, GenesisAccount{Type: "fleta.MultiSigAccount", Address: common.NewAddress(common.NewCoordinate(0, 2), 0), Name: "multisigaccount"})
+	if _, err := NewGenesisContextData(cfg, act, tran, evt); errs.CodeOf(err) != genesis.CodeUnsupportedGenesisAccount {
+		t.Errorf("expected the unsupported genesis account but %v", err)
+	}
+}
+
+func TestAddTable(t *testing.T) {
+	cfg := testConfig()
+	cfg.Transactions = nil
+	cfg.Accounts = nil
+	if err := cfg.AddTable(type_registry.Default(), type_registry.DefaultFees()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Transactions) != len(type_registry.Default().Transactions) || len(cfg.Events) != len(type_registry.Default().Events) {
+		t.Fatalf("unexpected types %d %d", len(cfg.Transactions), len(cfg.Events))
+	}
+	if err := type_registry.CheckCompatible(type_registry.Default(), cfg.Table()); err != nil {
+		t.Fatal(err)
+	}
+
+	fees := type_registry.DefaultFees()
+	delete(fees, "fleta.Transfer")
+	if err := testConfig().AddTable(type_registry.Default(), fees); errs.CodeOf(err) != type_registry.CodeMissingTransactionFee {
+		t.Errorf("expected the missing transaction fee but %v", err)
+	}
+}
+
+func TestRegisterPolicy(t *testing.T) {
+	cfg := testConfig()
+	cfg.ChainCoord = common.NewCoordinate(100, 1)
+	cfg.Policy = chain.DefaultPolicy()
+	cfg.Policy.NameLength = chain.Range{Min: 4, Max: 32}
+	cfg.Policy.ReservedNames = []string{"fletafoundation"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterPolicy(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if p := chain.PolicyOf(cfg.ChainCoord); p.NameLength != cfg.Policy.NameLength || p.CheckName("fletafoundation") != chain.ErrReservedAccountName {
+		t.Fatalf("unexpected registered policy %+v", p)
+	}
+
+	// the policy of the genesis file is registered when the config has no policy
+	act, err := genesis.NewAccounter(cfg.ChainCoord, genesis.DefaultAccountTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := genesis.Parse(act, []byte(`{"chain_coord": {"height": 100, "index": 1}, "policy": {"name_length": {"min": 6, "max": 16}, "reserved_names": ["fletagenesis"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Genesis = g
+	if err := cfg.Validate(); errs.CodeOf(err) != CodeInvalidChainConfig {
+		t.Fatalf("expected the invalid chain config by the two policies but %v", err)
+	}
+	cfg.Policy = nil
+	if err := RegisterPolicy(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if p := chain.PolicyOf(cfg.ChainCoord); p.NameLength != g.Policy.NameLength || !p.IsReservedName("fletagenesis") || p.IsReservedName("fletafoundation") {
+		t.Fatalf("unexpected registered policy %+v", p)
+	}
+
+	cfg.Genesis = nil
+	cfg.Policy = chain.DefaultPolicy()
+	cfg.Policy.MaxTagLength = -1
+	if err := cfg.Validate(); errs.CodeOf(err) != CodeInvalidChainConfig {
+		t.Fatalf("expected the invalid chain config but %v", err)
+	}
+}
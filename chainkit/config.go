@@ -0,0 +1,249 @@
+// Package chainkit builds the kernels, observers and formulators of a chain from a declarative config.
+package chainkit
+
+import (
+	"net"
+	"sort"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/genesis"
+	"github.com/fletaio/extension/type_registry"
+)
+
+// TransactionType is the type id and the fee of the transaction that is registered to the chain
+type TransactionType struct {
+	Name string
+	Type transaction.Type
+	Fee  *amount.Amount
+}
+
+// AccountType is the type id of the account that is registered to the chain
+type AccountType struct {
+	Name string
+	Type account.Type
+}
+
+// EventType is the type id of the event that is registered to the chain
+type EventType struct {
+	Name string
+	Type event.Type
+}
+
+// ObserverNode is the key and the listen addresses of the observer
+type ObserverNode struct {
+	Key               string // hex encoded private key
+	ObserverAddress   string // the address that is connected by other observers
+	FormulatorAddress string // the address that is connected by formulators
+}
+
+// FormulatorNode is the key and the formulation account of the formulator
+type FormulatorNode struct {
+	Key     string // hex encoded private key
+	Address common.Address
+	Port    int
+}
+
+// GenesisAccount is the account that is created by the genesis context
+type GenesisAccount struct {
+	Type         string // the registered account name like fleta.SingleAccount
+	Address      common.Address
+	Name         string
+	KeyHash      common.PublicHash
+	Balance      *amount.Amount // nil means zero
+	UnlockHeight uint32         // only for fleta.LockedAccount
+}
+
+// Config is the declarative definition of the chain
+type Config struct {
+	ChainCoord              *common.Coordinate
+	Version                 uint16
+	StoreRoot               string
+	MaxBlocksPerFormulator  uint32
+	MaxTransactionsPerBlock int
+	Rewarder                kernel.Rewarder
+	Policy                  *chain.Policy // nil means the policy of the genesis file or the default policy
+	Transactions            []TransactionType
+	Accounts                []AccountType
+	Events                  []EventType
+	Observers               []ObserverNode
+	Formulators             []FormulatorNode
+	GenesisAccounts         []GenesisAccount
+	Genesis                 *genesis.Genesis // the accounts and the UTXOs of the genesis file that is loaded by the account types of the config
+}
+
+// AddTable appends the types of the table to the config with the fees of the transactions
+func (cfg *Config) AddTable(table *type_registry.Table, fees map[string]*amount.Amount) error {
+	names := []string{}
+	for name := range table.Transactions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fee, has := fees[name]
+		if !has {
+			return errs.WrapField(type_registry.ErrMissingTransactionFee, "fees", nil, name)
+		}
+		cfg.Transactions = append(cfg.Transactions, TransactionType{Name: name, Type: table.Transactions[name], Fee: fee})
+	}
+
+	names = []string{}
+	for name := range table.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cfg.Accounts = append(cfg.Accounts, AccountType{Name: name, Type: table.Accounts[name]})
+	}
+
+	names = []string{}
+	for name := range table.Events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cfg.Events = append(cfg.Events, EventType{Name: name, Type: table.Events[name]})
+	}
+	return nil
+}
+
+// Table returns the type table of the config
+func (cfg *Config) Table() *type_registry.Table {
+	t := &type_registry.Table{
+		Transactions: map[string]transaction.Type{},
+		Accounts:     map[string]account.Type{},
+		Events:       map[string]event.Type{},
+	}
+	for _, v := range cfg.Transactions {
+		t.Transactions[v.Name] = v.Type
+	}
+	for _, v := range cfg.Accounts {
+		t.Accounts[v.Name] = v.Type
+	}
+	for _, v := range cfg.Events {
+		t.Events[v.Name] = v.Type
+	}
+	return t
+}
+
+// ChainPolicy returns the policy of the config or the one of the genesis file, and nil when neither has a policy
+func (cfg *Config) ChainPolicy() *chain.Policy {
+	if cfg.Policy != nil {
+		return cfg.Policy
+	}
+	if cfg.Genesis != nil {
+		return cfg.Genesis.Policy
+	}
+	return nil
+}
+
+// Validate returns an error when the config cannot build a chain
+func (cfg *Config) Validate() error {
+	if cfg.ChainCoord == nil {
+		return errs.WrapField(ErrInvalidChainConfig, "chain_coord", "coordinate", nil)
+	}
+	if len(cfg.StoreRoot) == 0 {
+		return errs.WrapField(ErrInvalidChainConfig, "store_root", "path", "")
+	}
+	if cfg.Rewarder == nil {
+		return errs.WrapField(ErrInvalidChainConfig, "rewarder", "rewarder", nil)
+	}
+	if len(cfg.Observers) == 0 {
+		return errs.WrapField(ErrInvalidChainConfig, "observers", ">= 1", 0)
+	}
+	if cfg.Policy != nil {
+		if !cfg.Policy.IsValid() {
+			return errs.WrapField(ErrInvalidChainConfig, "policy", "valid policy", cfg.Policy)
+		}
+		if cfg.Genesis != nil && cfg.Genesis.Policy != nil {
+			return errs.WrapField(ErrInvalidChainConfig, "genesis.policy", "the policy of the config or the genesis file", "both")
+		}
+	}
+
+	txNames := map[string]bool{}
+	txTypes := map[transaction.Type]bool{}
+	for _, v := range cfg.Transactions {
+		if txNames[v.Name] || txTypes[v.Type] {
+			return errs.WrapField(ErrInvalidChainConfig, "transactions", "unique", v.Name)
+		}
+		if v.Fee == nil {
+			return errs.WrapField(ErrInvalidChainConfig, "transactions.fee", "amount", v.Name)
+		}
+		txNames[v.Name] = true
+		txTypes[v.Type] = true
+	}
+	accNames := map[string]bool{}
+	accTypes := map[account.Type]bool{}
+	for _, v := range cfg.Accounts {
+		if accNames[v.Name] || accTypes[v.Type] {
+			return errs.WrapField(ErrInvalidChainConfig, "accounts", "unique", v.Name)
+		}
+		accNames[v.Name] = true
+		accTypes[v.Type] = true
+	}
+	evNames := map[string]bool{}
+	evTypes := map[event.Type]bool{}
+	for _, v := range cfg.Events {
+		if evNames[v.Name] || evTypes[v.Type] {
+			return errs.WrapField(ErrInvalidChainConfig, "events", "unique", v.Name)
+		}
+		evNames[v.Name] = true
+		evTypes[v.Type] = true
+	}
+
+	for _, v := range cfg.Observers {
+		if _, _, err := net.SplitHostPort(v.ObserverAddress); err != nil {
+			return errs.WrapField(ErrInvalidChainConfig, "observers.observer_address", "host:port", v.ObserverAddress)
+		}
+		if _, _, err := net.SplitHostPort(v.FormulatorAddress); err != nil {
+			return errs.WrapField(ErrInvalidChainConfig, "observers.formulator_address", "host:port", v.FormulatorAddress)
+		}
+	}
+
+	// the names of the genesis accounts follow the name rule of the chain like the genesis file
+	policy := cfg.ChainPolicy()
+	if policy == nil {
+		policy = chain.PolicyOf(cfg.ChainCoord)
+	}
+	addrs := map[common.Address]bool{}
+	names := map[string]bool{}
+	if cfg.Genesis != nil {
+		if !cfg.Genesis.ChainCoord.Equal(cfg.ChainCoord) {
+			return errs.WrapField(ErrInvalidChainConfig, "genesis.chain_coord", cfg.ChainCoord, cfg.Genesis.ChainCoord)
+		}
+		for _, acc := range cfg.Genesis.Accounts {
+			if !accTypes[acc.Type()] {
+				return errs.WrapField(ErrInvalidChainConfig, "genesis.accounts.type", "registered account", acc.Type())
+			}
+			if !policy.IsValidNameLength(acc.Name()) || !account_name.IsCanonical(acc.Name()) {
+				return errs.WrapField(ErrInvalidChainConfig, "genesis.accounts.name", policy.NameLength, acc.Name())
+			}
+			addrs[acc.Address()] = true
+			names[acc.Name()] = true
+		}
+	}
+	for _, v := range cfg.GenesisAccounts {
+		if !accNames[v.Type] {
+			return errs.WrapField(ErrInvalidChainConfig, "genesis_accounts.type", "registered account", v.Type)
+		}
+		if addrs[v.Address] {
+			return errs.WrapField(ErrInvalidChainConfig, "genesis_accounts.address", "unique", v.Address.String())
+		}
+		if !policy.IsValidNameLength(v.Name) || !account_name.IsCanonical(v.Name) {
+			return errs.WrapField(ErrInvalidChainConfig, "genesis_accounts.name", policy.NameLength, v.Name)
+		}
+		if names[v.Name] {
+			return errs.WrapField(ErrInvalidChainConfig, "genesis_accounts.name", "unique", v.Name)
+		}
+		addrs[v.Address] = true
+		names[v.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package chainkit
+
+import (
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/genesis"
+)
+
+// chain kit error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeInvalidChainConfig errs.Code = 1901
+)
+
+// chain kit errors
+var (
+	ErrInvalidChainConfig        = errs.New(CodeInvalidChainConfig, "invalid chain config")
+	ErrUnsupportedGenesisAccount = genesis.ErrUnsupportedGenesisAccount
+)
@@ -0,0 +1,26 @@
+// Package codec holds the runtime parts of the binary and JSON codecs that are generated by codecgen.
+//
+// The codecs are generated from the codec struct tags of the extension types:
+//
+//	type Transfer struct {
+//		Base
+//		Amount *amount.Amount `codec:"amount"`
+//		To     common.Address `codec:"to"`
+//		Tag    []byte         `codec:"tag,max=256,memo"`
+//	}
+//
+// The name of the tag is the JSON key and the field is not serialized when the tag is "-".
+// The options are
+//
+//	len=uintN  the width of the length prefix of a slice (uint8 by default)
+//	max=N      the maximum item count of a slice or byte length of a string or bytes
+//	memo       the bytes are also written as a memo next to the hex form in JSON
+//	base64     the bytes are written in the base64 form of encoding/json instead of the hex form in JSON
+//	repeat     the own fields of the embedded base are written again after it
+//
+// Embedded fields do not need a tag and their fields are flattened in JSON.
+//
+// The max option is required for strings, bytes and slices that have a wider length prefix than uint8.
+// The generated ReadFrom checks the lengths before the allocation and returns a LimitError when a length exceeds its maximum.
+// Decode bounds the total size of a message by reading it through a LimitedReader.
+package codec
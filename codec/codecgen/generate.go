@@ -0,0 +1,717 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+type kind int
+
+const (
+	kindUint kind = iota
+	kindType
+	kindString
+	kindBytes
+	kindAmount
+	kindValue
+	kindCoordinate
+	kindTxIn
+	kindTxOut
+	kindPointer
+	kindStruct
+	kindSlice
+)
+
+// knownImports are the import paths of the qualifiers that the generated code uses
+var knownImports = map[string]string{
+	"bytes":       "bytes",
+	"hex":         "encoding/hex",
+	"json":        "encoding/json",
+	"io":          "io",
+	"common":      "github.com/fletaio/common",
+	"hash":        "github.com/fletaio/common/hash",
+	"util":        "github.com/fletaio/common/util",
+	"account":     "github.com/fletaio/core/account",
+	"amount":      "github.com/fletaio/core/amount",
+	"event":       "github.com/fletaio/core/event",
+	"transaction": "github.com/fletaio/core/transaction",
+	"codec":       modulePath + "/codec",
+	"json_util":   modulePath + "/json_util",
+	"memo":        modulePath + "/memo",
+}
+
+// valueParsers are the parse functions of the value types that are written as a JSON string
+var valueParsers = map[string]struct {
+	Parse string
+	Var   string
+}{
+	"common.Address":    {"common.ParseAddress", "addr"},
+	"common.PublicHash": {"common.ParsePublicHash", "pubhash"},
+	"hash.Hash256":      {"hash.ParseHash", "h"},
+}
+
+// sliceParsers are the parse functions of the slices that are not unmarshaled directly
+var sliceParsers = map[string]struct {
+	JSONType string
+	Parse    string
+	Error    bool
+}{
+	"[]common.Address":     {"[]string", "json_util.ParseAddresses", true},
+	"[]common.PublicHash":  {"[]string", "json_util.ParsePublicHashes", true},
+	"[]*transaction.TxIn":  {"[]uint64", "json_util.ParseVin", false},
+	"[]*transaction.TxOut": {"[]json.RawMessage", "json_util.ParseVout", true},
+}
+
+func kindOf(typ string) kind {
+	switch typ {
+	case "uint8", "byte", "uint16", "uint32", "uint64":
+		return kindUint
+	case "transaction.Type", "account.Type", "event.Type":
+		return kindType
+	case "string":
+		return kindString
+	case "[]byte":
+		return kindBytes
+	case "*amount.Amount":
+		return kindAmount
+	case "common.Address", "common.PublicHash", "hash.Hash256":
+		return kindValue
+	case "common.Coordinate", "*common.Coordinate":
+		return kindCoordinate
+	case "*transaction.TxIn":
+		return kindTxIn
+	case "*transaction.TxOut":
+		return kindTxOut
+	}
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		return kindSlice
+	case strings.HasPrefix(typ, "*"):
+		return kindPointer
+	}
+	return kindStruct
+}
+
+func uintWidth(typ string) string {
+	switch typ {
+	case "uint8", "byte":
+		return "Uint8"
+	case "uint16":
+		return "Uint16"
+	case "uint32":
+		return "Uint32"
+	}
+	return "Uint64"
+}
+
+func lenLimit(lenType string) int {
+	switch lenType {
+	case "uint16":
+		return 65535
+	case "uint32":
+		return 4294967295
+	}
+	return 255
+}
+
+func newOf(typ string) string {
+	switch typ {
+	case "*amount.Amount":
+		return "amount.NewCoinAmount(0, 0)"
+	case "*transaction.TxOut":
+		return "transaction.NewTxOut()"
+	}
+	return "&" + strings.TrimPrefix(typ, "*") + "{}"
+}
+
+type generator struct {
+	pkg     *Package
+	buffer  bytes.Buffer
+	imports map[string]bool
+}
+
+func newGenerator(pkg *Package) *generator {
+	return &generator{
+		pkg:     pkg,
+		imports: map[string]bool{},
+	}
+}
+
+func (g *generator) p(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buffer, format, args...)
+	g.buffer.WriteString("\n")
+}
+
+// use records the import of the qualifiers in the type expression or code
+func (g *generator) use(qualifiers ...string) {
+	for _, q := range qualifiers {
+		g.imports[q] = true
+	}
+}
+
+func (g *generator) useType(typ string) {
+	typ = strings.TrimLeft(typ, "[]*")
+	if idx := strings.Index(typ, "."); idx >= 0 {
+		g.use(typ[:idx])
+	}
+}
+
+func (g *generator) source() ([]byte, error) {
+	var out bytes.Buffer
+	out.WriteString("// Code generated by codecgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", g.pkg.Name)
+	var std, ext []string
+	for q := range g.imports {
+		path, has := g.pkg.Imports[q]
+		if !has {
+			path, has = knownImports[q]
+		}
+		if !has {
+			return nil, fmt.Errorf("unknown import of %s", q)
+		}
+		line := fmt.Sprintf("%q", path)
+		if q != path[strings.LastIndex(path, "/")+1:] {
+			line = q + " " + line
+		}
+		if strings.Contains(path, ".") {
+			ext = append(ext, line)
+		} else {
+			std = append(std, line)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(ext)
+	out.WriteString("import (\n")
+	for _, line := range std {
+		out.WriteString("\t" + line + "\n")
+	}
+	if len(std) > 0 && len(ext) > 0 {
+		out.WriteString("\n")
+	}
+	for _, line := range ext {
+		out.WriteString("\t" + line + "\n")
+	}
+	out.WriteString(")\n")
+	out.Write(g.buffer.Bytes())
+	return format.Source(out.Bytes())
+}
+
+func (g *generator) generate(st *Struct) error {
+	g.use("io")
+	if err := g.writeTo(st); err != nil {
+		return err
+	}
+	if err := g.readFrom(st); err != nil {
+		return err
+	}
+	if st.BinaryOnly {
+		return nil
+	}
+	if err := g.marshalJSON(st); err != nil {
+		return err
+	}
+	return g.unmarshalJSON(st)
+}
+
+// jsonFields returns the flattened fields of the struct
+func jsonFields(st *Struct) []*Field {
+	list := []*Field{}
+	for _, f := range st.Fields {
+		if f.Embedded {
+			list = append(list, f.Fields...)
+		} else {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+func (g *generator) writeTo(st *Struct) error {
+	r := st.Recv
+	g.p("")
+	g.p("// WriteTo is a serialization function")
+	g.p("func (%s *%s) WriteTo(w io.Writer) (int64, error) {", r, st.Name)
+	g.p("var wrote int64")
+	for _, f := range st.Fields {
+		if f.Embedded {
+			g.p("if n, err := %s.%s.WriteTo(w); err != nil {", r, f.Name)
+			g.p("return wrote, err")
+			g.p("} else {")
+			g.p("wrote += n")
+			g.p("}")
+			if f.Repeat {
+				for _, o := range f.Own {
+					if err := g.writeField(o, st.Name+"."+o.Name, r+"."+o.Name); err != nil {
+						return fmt.Errorf("%s.%s: %v", st.Name, o.Name, err)
+					}
+				}
+			}
+			continue
+		}
+		if err := g.writeField(f, st.Name+"."+f.Name, r+"."+f.Name); err != nil {
+			return fmt.Errorf("%s.%s: %v", st.Name, f.Name, err)
+		}
+	}
+	g.p("return wrote, nil")
+	g.p("}")
+	return nil
+}
+
+// itemLimit returns the maximum item count of the slice field
+// A wider length than uint8 needs the max option to bound the allocation of the reader
+func itemLimit(f *Field) (string, int, error) {
+	lenType := f.LenType
+	if len(lenType) == 0 {
+		lenType = "uint8"
+	}
+	max := lenLimit(lenType)
+	if f.Max > 0 && f.Max < max {
+		max = f.Max
+	} else if lenType != "uint8" {
+		return "", 0, fmt.Errorf("max is required for len=%s", lenType)
+	}
+	return lenType, max, nil
+}
+
+func (g *generator) writeField(f *Field, name string, x string) error {
+	switch kindOf(f.Type) {
+	case kindSlice:
+		elem := f.Type[2:]
+		lenType, max, err := itemLimit(f)
+		if err != nil {
+			return err
+		}
+		g.use("util", "codec")
+		g.p("if err := codec.CheckItemCount(%q, uint64(len(%s)), %d); err != nil {", name, x, max)
+		g.p("return wrote, err")
+		g.p("}")
+		g.p("if n, err := util.Write%s(w, %s(len(%s))); err != nil {", uintWidth(lenType), lenType, x)
+		g.p("return wrote, err")
+		g.p("} else {")
+		g.p("wrote += n")
+		g.p("for _, v := range %s {", x)
+		if err := g.writeValue(elem, "v"); err != nil {
+			return err
+		}
+		g.p("}")
+		g.p("}")
+		return nil
+	case kindString, kindBytes:
+		if f.Max == 0 {
+			return fmt.Errorf("max is required for %s", f.Type)
+		}
+		g.use("codec")
+		g.p("if err := codec.CheckByteLength(%q, uint64(len(%s)), %d); err != nil {", name, x, f.Max)
+		g.p("return wrote, err")
+		g.p("}")
+	}
+	return g.writeValue(f.Type, x)
+}
+
+func (g *generator) writeValue(typ string, x string) error {
+	switch kindOf(typ) {
+	case kindUint:
+		g.use("util")
+		g.p("if n, err := util.Write%s(w, %s); err != nil {", uintWidth(typ), x)
+	case kindType:
+		g.use("util")
+		g.p("if n, err := util.WriteUint8(w, uint8(%s)); err != nil {", x)
+	case kindString:
+		g.use("util")
+		g.p("if n, err := util.WriteString(w, %s); err != nil {", x)
+	case kindBytes:
+		g.use("util")
+		g.p("if n, err := util.WriteBytes(w, %s); err != nil {", x)
+	case kindSlice:
+		return fmt.Errorf("nested slice %s is not supported", typ)
+	default:
+		g.p("if n, err := %s.WriteTo(w); err != nil {", x)
+	}
+	g.p("return wrote, err")
+	g.p("} else {")
+	g.p("wrote += n")
+	g.p("}")
+	return nil
+}
+
+func (g *generator) readFrom(st *Struct) error {
+	r := st.Recv
+	g.p("")
+	g.p("// ReadFrom is a deserialization function")
+	g.p("func (%s *%s) ReadFrom(r io.Reader) (int64, error) {", r, st.Name)
+	g.p("var read int64")
+	for _, f := range st.Fields {
+		if f.Embedded {
+			g.p("if n, err := %s.%s.ReadFrom(r); err != nil {", r, f.Name)
+			g.p("return read, err")
+			g.p("} else {")
+			g.p("read += n")
+			g.p("}")
+			if f.Repeat {
+				for _, o := range f.Own {
+					if err := g.readField(o, st.Name+"."+o.Name, r+"."+o.Name); err != nil {
+						return fmt.Errorf("%s.%s: %v", st.Name, o.Name, err)
+					}
+				}
+			}
+			continue
+		}
+		if err := g.readField(f, st.Name+"."+f.Name, r+"."+f.Name); err != nil {
+			return fmt.Errorf("%s.%s: %v", st.Name, f.Name, err)
+		}
+	}
+	g.p("return read, nil")
+	g.p("}")
+	return nil
+}
+
+func (g *generator) readField(f *Field, name string, x string) error {
+	switch kindOf(f.Type) {
+	case kindSlice:
+		elem := f.Type[2:]
+		lenType, max, err := itemLimit(f)
+		if err != nil {
+			return err
+		}
+		g.use("util")
+		g.useType(elem)
+		g.p("if Len, n, err := util.Read%s(r); err != nil {", uintWidth(lenType))
+		g.p("return read, err")
+		g.p("} else {")
+		g.p("read += n")
+		if max < lenLimit(lenType) {
+			g.use("codec")
+			g.p("if err := codec.CheckItemCount(%q, uint64(Len), %d); err != nil {", name, max)
+			g.p("return read, err")
+			g.p("}")
+		}
+		g.p("%s = make(%s, 0, Len)", x, f.Type)
+		g.p("for i := 0; i < int(Len); i++ {")
+		if err := g.readElement(elem, x); err != nil {
+			return err
+		}
+		g.p("}")
+		g.p("}")
+		return nil
+	case kindString, kindBytes:
+		if f.Max == 0 {
+			return fmt.Errorf("max is required for %s", f.Type)
+		}
+		g.use("codec")
+		fn := "ReadString"
+		if kindOf(f.Type) == kindBytes {
+			fn = "ReadBytes"
+		}
+		g.p("if v, n, err := codec.%s(r, %q, %d); err != nil {", fn, name, f.Max)
+		g.p("return read, err")
+		g.p("} else {")
+		g.p("read += n")
+		g.p("%s = v", x)
+		g.p("}")
+		return nil
+	case kindUint, kindType:
+		g.use("util")
+		g.p("if v, n, err := util.Read%s(r); err != nil {", uintWidth(baseUint(f.Type)))
+		g.p("return read, err")
+		g.p("} else {")
+		g.p("read += n")
+		g.p("%s = %s", x, convert(f.Type, "v"))
+		g.p("}")
+		return nil
+	case kindAmount, kindTxOut, kindTxIn, kindPointer:
+		g.useType(f.Type)
+		g.p("%s = %s", x, newOf(f.Type))
+	}
+	g.p("if n, err := %s.ReadFrom(r); err != nil {", x)
+	g.p("return read, err")
+	g.p("} else {")
+	g.p("read += n")
+	g.p("}")
+	return nil
+}
+
+func (g *generator) readElement(typ string, x string) error {
+	switch kindOf(typ) {
+	case kindSlice:
+		return fmt.Errorf("nested slice %s is not supported", typ)
+	case kindString, kindBytes:
+		return fmt.Errorf("slice of %s is not supported because its items have no max", typ)
+	case kindUint, kindType:
+		g.use("util")
+		g.p("if v, n, err := util.Read%s(r); err != nil {", uintWidth(baseUint(typ)))
+		g.p("return read, err")
+		g.p("} else {")
+		g.p("read += n")
+		g.p("%s = append(%s, %s)", x, x, convert(typ, "v"))
+		g.p("}")
+		return nil
+	case kindAmount, kindTxOut, kindTxIn, kindPointer:
+		g.p("v := %s", newOf(typ))
+	default:
+		g.p("var v %s", typ)
+	}
+	g.p("if n, err := v.ReadFrom(r); err != nil {")
+	g.p("return read, err")
+	g.p("} else {")
+	g.p("read += n")
+	g.p("%s = append(%s, v)", x, x)
+	g.p("}")
+	return nil
+}
+
+func baseUint(typ string) string {
+	if kindOf(typ) == kindType {
+		return "uint8"
+	}
+	return typ
+}
+
+func convert(typ string, v string) string {
+	switch typ {
+	case "uint8", "byte", "uint16", "uint32", "uint64", "string", "[]byte":
+		return v
+	}
+	return typ + "(" + v + ")"
+}
+
+func (g *generator) marshalJSON(st *Struct) error {
+	r := st.Recv
+	g.use("bytes", "json")
+	g.p("")
+	g.p("// MarshalJSON is a marshaler function")
+	g.p("func (%s *%s) MarshalJSON() ([]byte, error) {", r, st.Name)
+	g.p("var buffer bytes.Buffer")
+	g.p("buffer.WriteString(`{`)")
+	for i, f := range jsonFields(st) {
+		if i > 0 {
+			g.p("buffer.WriteString(`,`)")
+		}
+		x := r + "." + f.Name
+		g.p("buffer.WriteString(`\"%s\":`)", f.Key)
+		if f.Base64 {
+			if kindOf(f.Type) != kindBytes {
+				return fmt.Errorf("%s.%s: base64 is only for the bytes", st.Name, f.Name)
+			}
+			g.p("if bs, err := json.Marshal(%s); err != nil {", x)
+			g.p("return nil, err")
+			g.p("} else {")
+			g.p("buffer.Write(bs)")
+			g.p("}")
+		} else if err := g.marshalValue(f.Type, x); err != nil {
+			return fmt.Errorf("%s.%s: %v", st.Name, f.Name, err)
+		}
+		if f.Memo {
+			if kindOf(f.Type) != kindBytes {
+				return fmt.Errorf("%s.%s: memo is only for the bytes", st.Name, f.Name)
+			}
+			g.use("memo")
+			g.p("buffer.WriteString(`,`)")
+			g.p("buffer.WriteString(`\"memo\":`)")
+			g.p("if bs, err := memo.MarshalTagJSON(%s); err != nil {", x)
+			g.p("return nil, err")
+			g.p("} else {")
+			g.p("buffer.Write(bs)")
+			g.p("}")
+		}
+	}
+	g.p("buffer.WriteString(`}`)")
+	g.p("return buffer.Bytes(), nil")
+	g.p("}")
+	return nil
+}
+
+func (g *generator) marshalValue(typ string, x string) error {
+	switch kindOf(typ) {
+	case kindBytes:
+		g.use("hex")
+		g.p("if len(%s) == 0 {", x)
+		g.p("buffer.WriteString(`null`)")
+		g.p("} else {")
+		g.p("buffer.WriteString(`\"`)")
+		g.p("buffer.WriteString(hex.EncodeToString(%s))", x)
+		g.p("buffer.WriteString(`\"`)")
+		g.p("}")
+		return nil
+	case kindSlice:
+		elem := typ[2:]
+		switch kindOf(elem) {
+		case kindSlice:
+			return fmt.Errorf("nested slice %s is not supported", typ)
+		case kindStruct:
+			g.p("if bs, err := json.Marshal(%s); err != nil {", x)
+		default:
+			g.p("buffer.WriteString(`[`)")
+			g.p("for i, v := range %s {", x)
+			g.p("if i > 0 {")
+			g.p("buffer.WriteString(`,`)")
+			g.p("}")
+			if err := g.marshalValue(elem, "v"); err != nil {
+				return err
+			}
+			g.p("}")
+			g.p("buffer.WriteString(`]`)")
+			return nil
+		}
+	case kindCoordinate:
+		g.use("json_util")
+		g.p("if bs, err := json.Marshal(&json_util.Coordinate{")
+		g.p("Height: %s.Height,", x)
+		g.p("Index:  %s.Index,", x)
+		g.p("}); err != nil {")
+	case kindTxIn:
+		g.p("if bs, err := json.Marshal(%s.ID()); err != nil {", x)
+	case kindAmount, kindValue, kindTxOut, kindPointer:
+		g.p("if bs, err := %s.MarshalJSON(); err != nil {", x)
+	default:
+		g.p("if bs, err := json.Marshal(%s); err != nil {", x)
+	}
+	g.p("return nil, err")
+	g.p("} else {")
+	g.p("buffer.Write(bs)")
+	g.p("}")
+	return nil
+}
+
+// jsonType returns the type of the field in the JSON form
+func jsonType(typ string) string {
+	switch kindOf(typ) {
+	case kindBytes:
+		return "*string"
+	case kindAmount, kindPointer, kindTxOut:
+		return "json.RawMessage"
+	case kindValue:
+		return "string"
+	case kindCoordinate:
+		return "json_util.Coordinate"
+	case kindTxIn:
+		return "uint64"
+	case kindSlice:
+		if p, has := sliceParsers[typ]; has && len(p.JSONType) > 0 {
+			return p.JSONType
+		}
+	}
+	return typ
+}
+
+func (g *generator) unmarshalJSON(st *Struct) error {
+	r := st.Recv
+	fields := jsonFields(st)
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, strings.TrimSuffix(f.Name, "_"))
+	}
+	g.use("json")
+	g.p("")
+	g.p("// UnmarshalJSON is a unmarshaler function")
+	g.p("func (%s *%s) UnmarshalJSON(bs []byte) error {", r, st.Name)
+	g.p("var v struct {")
+	for i, f := range fields {
+		typ := jsonType(f.Type)
+		if f.Base64 {
+			typ = f.Type
+		}
+		g.useType(typ)
+		g.p("%s %s `json:\"%s\"`", names[i], typ, f.Key)
+	}
+	g.p("}")
+	g.p("if err := json.Unmarshal(bs, &v); err != nil {")
+	g.p("return err")
+	g.p("}")
+	for i, f := range fields {
+		x := r + "." + f.Name
+		V := "v." + names[i]
+		if err := g.checkJSONLength(f, V); err != nil {
+			return fmt.Errorf("%s.%s: %v", st.Name, f.Name, err)
+		}
+		parse := func(call string, tmp string) {
+			g.p("if %s, err := %s; err != nil {", tmp, call)
+			g.p("return err")
+			g.p("} else {")
+			g.p("%s = %s", x, tmp)
+			g.p("}")
+		}
+		switch kindOf(f.Type) {
+		case kindBytes:
+			if f.Base64 {
+				g.p("%s = %s", x, V)
+				break
+			}
+			g.use("json_util")
+			parse(fmt.Sprintf("json_util.ParseTag(%s, %q, %d)", V, f.Owner+"."+f.Name, f.Max), "bs")
+		case kindAmount:
+			g.use("json_util")
+			parse("json_util.ParseAmount("+V+")", "am")
+		case kindValue:
+			vp := valueParsers[f.Type]
+			g.useType(f.Type)
+			parse(vp.Parse+"("+V+")", vp.Var)
+		case kindCoordinate:
+			g.use("common")
+			if strings.HasPrefix(f.Type, "*") {
+				g.p("%s = common.NewCoordinate(%s.Height, %s.Index)", x, V, V)
+			} else {
+				g.p("%s = *common.NewCoordinate(%s.Height, %s.Index)", x, V, V)
+			}
+		case kindPointer:
+			g.useType(f.Type)
+			g.p("%s = %s", x, newOf(f.Type))
+			g.p("if err := %s.UnmarshalJSON(%s); err != nil {", x, V)
+			g.p("return err")
+			g.p("}")
+		case kindTxIn, kindTxOut:
+			return fmt.Errorf("%s.%s: %s is only supported in a slice", st.Name, f.Name, f.Type)
+		case kindSlice:
+			p, has := sliceParsers[f.Type]
+			switch {
+			case !has || len(p.Parse) == 0:
+				g.p("%s = %s", x, V)
+			case p.Error:
+				g.use("json_util")
+				parse(p.Parse+"("+V+")", "list")
+			default:
+				g.use("json_util")
+				g.p("%s = %s(%s)", x, p.Parse, V)
+			}
+		default:
+			g.p("%s = %s", x, V)
+		}
+	}
+	g.p("return nil")
+	g.p("}")
+	return nil
+}
+
+// checkJSONLength writes the length check of the JSON value that ReadFrom does for the field
+// The hex bytes are checked by json_util.ParseTag
+func (g *generator) checkJSONLength(f *Field, V string) error {
+	name := f.Owner + "." + f.Name
+	switch kindOf(f.Type) {
+	case kindSlice:
+		_, max, err := itemLimit(f)
+		if err != nil {
+			return err
+		}
+		g.use("codec")
+		g.p("if err := codec.CheckItemCount(%q, uint64(len(%s)), %d); err != nil {", name, V, max)
+	case kindString:
+		if f.Max == 0 {
+			return nil
+		}
+		g.use("codec")
+		g.p("if err := codec.CheckByteLength(%q, uint64(len(%s)), %d); err != nil {", name, V, f.Max)
+	case kindBytes:
+		if !f.Base64 {
+			return nil
+		}
+		g.use("codec")
+		g.p("if err := codec.CheckByteLength(%q, uint64(len(%s)), %d); err != nil {", name, V, f.Max)
+	default:
+		return nil
+	}
+	g.p("return err")
+	g.p("}")
+	return nil
+}
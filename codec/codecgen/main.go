@@ -0,0 +1,103 @@
+// Command codecgen generates WriteTo, ReadFrom, MarshalJSON and UnmarshalJSON of the extension types from their codec struct tags.
+//
+// It is run by go generate in the package directory:
+//
+//	//go:generate go run ../codec/codecgen -type=Base:binary,Transfer,Withdraw
+//
+// A type that is suffixed by :binary only gets WriteTo and ReadFrom.
+// The code of the types that are declared in a file is written to the file that has the _codec.go suffix.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("codecgen: ")
+
+	types := flag.String("type", "", "comma separated list of the type names")
+	root := flag.String("root", "..", "directory of the extension module")
+	dir := flag.String("dir", ".", "directory of the package")
+	flag.Parse()
+
+	if len(*types) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	files, err := Generate(*dir, *root, strings.Split(*types, ","))
+	if err != nil {
+		log.Fatal(err)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ioutil.WriteFile(filepath.Join(*dir, name), files[name], 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// Generate returns the generated files of the types in the package directory by their file names
+func Generate(dir string, root string, specs []string) (map[string][]byte, error) {
+	ld := newLoader(root)
+	pkg, err := ld.load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byFile := map[string][]*Struct{}
+	fileOrder := []string{}
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if len(spec) == 0 {
+			continue
+		}
+		name := spec
+		binaryOnly := false
+		if idx := strings.Index(spec, ":"); idx >= 0 {
+			name = spec[:idx]
+			switch spec[idx+1:] {
+			case "binary":
+				binaryOnly = true
+			default:
+				return nil, fmt.Errorf("invalid type option %q", spec)
+			}
+		}
+		st, err := ld.structOf(pkg, name)
+		if err != nil {
+			return nil, err
+		}
+		st.BinaryOnly = binaryOnly
+		if _, has := byFile[st.File]; !has {
+			fileOrder = append(fileOrder, st.File)
+		}
+		byFile[st.File] = append(byFile[st.File], st)
+	}
+
+	files := map[string][]byte{}
+	for _, file := range fileOrder {
+		g := newGenerator(pkg)
+		for _, st := range byFile[file] {
+			if err := g.generate(st); err != nil {
+				return nil, err
+			}
+		}
+		src, err := g.source()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		files[strings.TrimSuffix(file, ".go")+"_codec.go"] = src
+	}
+	return files, nil
+}
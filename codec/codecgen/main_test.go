@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var generateDirective = regexp.MustCompile(`//go:generate go run \.\./codec/codecgen -type=(\S+)`)
+
+// TestGeneratedFiles checks that the generated files of the packages are up to date
+func TestGeneratedFiles(t *testing.T) {
+	root := filepath.Join("..", "..")
+	dirs, err := ioutil.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for _, fi := range dirs {
+		if !fi.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, fi.Name())
+		src, err := ioutil.ReadFile(filepath.Join(dir, "generate.go"))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		m := generateDirective.FindSubmatch(src)
+		if m == nil {
+			continue
+		}
+		files, err := Generate(dir, root, strings.Split(string(m[1]), ","))
+		if err != nil {
+			t.Fatalf("%s: %v", fi.Name(), err)
+		}
+		for name, expected := range files {
+			actual, err := ioutil.ReadFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatalf("%s: %v", fi.Name(), err)
+			}
+			if !bytes.Equal(actual, expected) {
+				t.Errorf("%s/%s is out of date; run go generate", fi.Name(), name)
+			}
+			count++
+		}
+	}
+	if count == 0 {
+		t.Fatal("no generated file is found")
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	field := &Field{}
+	if err := parseTag(field, "tag,max=256,memo,len=uint16"); err != nil {
+		t.Fatal(err)
+	}
+	if field.Key != "tag" || field.Max != 256 || !field.Memo || field.LenType != "uint16" {
+		t.Errorf("invalid field %+v", field)
+	}
+	field = &Field{}
+	if err := parseTag(field, "tag,base64"); err != nil {
+		t.Fatal(err)
+	}
+	if !field.Base64 {
+		t.Errorf("invalid field %+v", field)
+	}
+	for _, tag := range []string{"tag,max=0", "tag,len=uint64", "tag,unknown"} {
+		if err := parseTag(&Field{}, tag); err == nil {
+			t.Errorf("%s: expected an error", tag)
+		}
+	}
+}
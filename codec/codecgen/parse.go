@@ -0,0 +1,369 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const modulePath = "github.com/fletaio/extension"
+
+// Package is a parsed package of the extension module
+type Package struct {
+	Name      string
+	Path      string
+	Imports   map[string]string // qualifier to import path
+	structs   map[string]*ast.StructType
+	files     map[string]string // type name to file name
+	receivers map[string]string // type name to receiver name
+}
+
+// Struct is a type that has codec struct tags
+type Struct struct {
+	Name       string
+	Recv       string
+	File       string
+	BinaryOnly bool
+	Fields     []*Field
+}
+
+// Field is a serialized field of the struct
+type Field struct {
+	Name     string // the name of the field or the type name of the embedded field
+	Owner    string // the name of the struct that declares the field
+	Key      string // the JSON key
+	Type     string // the type expression with the package qualifier
+	Embedded bool
+	Repeat   bool
+	Memo     bool
+	Base64   bool
+	Max      int
+	LenType  string
+	Fields   []*Field // the flattened fields of the embedded field
+	Own      []*Field // the own fields of the embedded field that are written again by repeat
+	Binary   bool     // the embedded field has WriteTo and ReadFrom
+}
+
+// builtinBases are the embedded bases of the core that are serialized by their WriteTo and ReadFrom
+var builtinBases = map[string][]*Field{
+	"github.com/fletaio/core/transaction.Base": {
+		{Name: "Type_", Key: "type", Type: "transaction.Type"},
+		{Name: "Timestamp_", Key: "timestamp", Type: "uint64"},
+	},
+	"github.com/fletaio/core/account.Base": {
+		{Name: "Address_", Key: "address", Type: "common.Address"},
+		{Name: "Type_", Key: "type", Type: "account.Type"},
+		{Name: "Name_", Key: "name", Type: "string"},
+		{Name: "Balance_", Key: "balance", Type: "*amount.Amount"},
+	},
+	"github.com/fletaio/core/event.Base": {
+		{Name: "Coord_", Key: "coord", Type: "*common.Coordinate"},
+		{Name: "Index_", Key: "index", Type: "uint16"},
+		{Name: "Type_", Key: "type", Type: "event.Type"},
+	},
+}
+
+type loader struct {
+	root string
+	pkgs map[string]*Package
+}
+
+func newLoader(root string) *loader {
+	return &loader{
+		root: root,
+		pkgs: map[string]*Package{},
+	}
+}
+
+func (ld *loader) load(dir string) (*Package, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if pkg, has := ld.pkgs[abs]; has {
+		return pkg, nil
+	}
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseDir(fset, abs, func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return !strings.HasSuffix(name, "_test.go") && !strings.HasSuffix(name, "_codec.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed) != 1 {
+		return nil, fmt.Errorf("%s: expected one package but found %d", dir, len(parsed))
+	}
+	pkg := &Package{
+		Path:      modulePath + "/" + filepath.Base(abs),
+		Imports:   map[string]string{},
+		structs:   map[string]*ast.StructType{},
+		files:     map[string]string{},
+		receivers: map[string]string{},
+	}
+	for name, p := range parsed {
+		pkg.Name = name
+		for filename, file := range p.Files {
+			for _, imp := range file.Imports {
+				path, err := strconv.Unquote(imp.Path.Value)
+				if err != nil {
+					return nil, err
+				}
+				qualifier := filepath.Base(path)
+				if imp.Name != nil {
+					qualifier = imp.Name.Name
+				}
+				pkg.Imports[qualifier] = path
+			}
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.GenDecl:
+					for _, spec := range d.Specs {
+						ts, is := spec.(*ast.TypeSpec)
+						if !is {
+							continue
+						}
+						if st, is := ts.Type.(*ast.StructType); is {
+							pkg.structs[ts.Name.Name] = st
+							pkg.files[ts.Name.Name] = filepath.Base(filename)
+						}
+					}
+				case *ast.FuncDecl:
+					if d.Recv == nil || len(d.Recv.List) != 1 || len(d.Recv.List[0].Names) != 1 {
+						continue
+					}
+					typ := d.Recv.List[0].Type
+					if star, is := typ.(*ast.StarExpr); is {
+						typ = star.X
+					}
+					if id, is := typ.(*ast.Ident); is {
+						if _, has := pkg.receivers[id.Name]; !has {
+							pkg.receivers[id.Name] = d.Recv.List[0].Names[0].Name
+						}
+					}
+				}
+			}
+		}
+	}
+	ld.pkgs[abs] = pkg
+	return pkg, nil
+}
+
+func (ld *loader) structOf(pkg *Package, name string) (*Struct, error) {
+	st, has := pkg.structs[name]
+	if !has {
+		return nil, fmt.Errorf("%s: not exist struct %s", pkg.Path, name)
+	}
+	fields, err := ld.fieldsOf(pkg, name, st)
+	if err != nil {
+		return nil, err
+	}
+	recv, has := pkg.receivers[name]
+	if !has {
+		recv = initials(name)
+	}
+	return &Struct{
+		Name:   name,
+		Recv:   recv,
+		File:   pkg.files[name],
+		Fields: fields,
+	}, nil
+}
+
+func (ld *loader) fieldsOf(pkg *Package, name string, st *ast.StructType) ([]*Field, error) {
+	fields := []*Field{}
+	for _, f := range st.Fields.List {
+		typ := types.ExprString(f.Type)
+		var tag string
+		if f.Tag != nil {
+			raw, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(raw).Get("codec")
+		}
+		if len(f.Names) == 0 {
+			field, err := ld.embeddedOf(pkg, typ, tag)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", name, typ, err)
+			}
+			fields = append(fields, field)
+			continue
+		}
+		if len(tag) == 0 {
+			return nil, fmt.Errorf("%s.%s: no codec tag", name, f.Names[0].Name)
+		}
+		if tag == "-" {
+			continue
+		}
+		for _, id := range f.Names {
+			field := &Field{
+				Name:  id.Name,
+				Owner: name,
+				Type:  typ,
+			}
+			if err := parseTag(field, tag); err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", name, id.Name, err)
+			}
+			if field.Repeat {
+				return nil, fmt.Errorf("%s.%s: repeat is only for the embedded field", name, id.Name)
+			}
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+func (ld *loader) embeddedOf(pkg *Package, typ string, tag string) (*Field, error) {
+	field := &Field{
+		Type:     typ,
+		Embedded: true,
+		Binary:   true,
+	}
+	if len(tag) > 0 {
+		if err := parseTag(field, tag); err != nil {
+			return nil, err
+		}
+	}
+
+	target := pkg
+	name := typ
+	if idx := strings.Index(typ, "."); idx >= 0 {
+		name = typ[idx+1:]
+		path, has := pkg.Imports[typ[:idx]]
+		if !has {
+			return nil, fmt.Errorf("not imported %s", typ[:idx])
+		}
+		if list, has := builtinBases[path+"."+name]; has {
+			field.Name = name
+			field.Fields = list
+			if field.Repeat {
+				return nil, fmt.Errorf("repeat is not supported for %s", typ)
+			}
+			return field, nil
+		}
+		if !strings.HasPrefix(path, modulePath+"/") {
+			return nil, fmt.Errorf("not supported embedded type %s", typ)
+		}
+		p, err := ld.load(filepath.Join(ld.root, strings.TrimPrefix(path, modulePath+"/")))
+		if err != nil {
+			return nil, err
+		}
+		target = p
+	}
+	field.Name = name
+	st, has := target.structs[name]
+	if !has {
+		return nil, fmt.Errorf("not exist struct %s", typ)
+	}
+	fields, err := ld.fieldsOf(target, name, st)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if f.Embedded {
+			field.Fields = append(field.Fields, f.Fields...)
+		} else {
+			field.Fields = append(field.Fields, qualify(f, target, pkg))
+			field.Own = append(field.Own, qualify(f, target, pkg))
+		}
+	}
+	return field, nil
+}
+
+// qualify returns the field that has the type expression of the package that embeds it
+func qualify(f *Field, from *Package, to *Package) *Field {
+	if from == to {
+		return f
+	}
+	c := *f
+	c.Type = requalify(f.Type, from, to)
+	return &c
+}
+
+func requalify(typ string, from *Package, to *Package) string {
+	prefix := ""
+	for strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "*") {
+		if strings.HasPrefix(typ, "[]") {
+			prefix += "[]"
+			typ = typ[2:]
+		} else {
+			prefix += "*"
+			typ = typ[1:]
+		}
+	}
+	if idx := strings.Index(typ, "."); idx >= 0 {
+		path := from.Imports[typ[:idx]]
+		for qualifier, p := range to.Imports {
+			if p == path {
+				return prefix + qualifier + typ[idx:]
+			}
+		}
+		to.Imports[typ[:idx]] = path
+		return prefix + typ
+	}
+	if isBasic(typ) {
+		return prefix + typ
+	}
+	to.Imports[from.Name] = from.Path
+	return prefix + from.Name + "." + typ
+}
+
+func parseTag(field *Field, tag string) error {
+	parts := strings.Split(tag, ",")
+	field.Key = parts[0]
+	for _, opt := range parts[1:] {
+		switch {
+		case opt == "memo":
+			field.Memo = true
+		case opt == "base64":
+			field.Base64 = true
+		case opt == "repeat":
+			field.Repeat = true
+		case strings.HasPrefix(opt, "max="):
+			v, err := strconv.Atoi(opt[len("max="):])
+			if err != nil || v <= 0 {
+				return fmt.Errorf("invalid max %q", opt)
+			}
+			field.Max = v
+		case strings.HasPrefix(opt, "len="):
+			switch v := opt[len("len="):]; v {
+			case "uint8", "uint16", "uint32":
+				field.LenType = v
+			default:
+				return fmt.Errorf("invalid len %q", opt)
+			}
+		default:
+			return fmt.Errorf("invalid option %q", opt)
+		}
+	}
+	return nil
+}
+
+func initials(name string) string {
+	var s []rune
+	for _, c := range name {
+		if c >= 'A' && c <= 'Z' {
+			s = append(s, c-'A'+'a')
+		}
+	}
+	if len(s) == 0 {
+		return "v"
+	}
+	return string(s)
+}
+
+func isBasic(typ string) bool {
+	switch typ {
+	case "uint8", "uint16", "uint32", "uint64", "byte", "string", "bool":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package codec
+
+import (
+	"fmt"
+
+	"github.com/fletaio/extension/errs"
+)
+
+// codec errors
+var (
+	ErrExceedItemCount   = errs.ErrExceedItemCount
+	ErrExceedByteLength  = errs.ErrExceedByteLength
+	ErrExceedMessageSize = errs.ErrExceedMessageSize
+)
+
+// LimitError is returned when the length of a field exceeds its maximum
+// It wraps ErrExceedItemCount or ErrExceedByteLength
+type LimitError struct {
+	Field  string
+	Length uint64
+	Max    uint64
+	Err    error
+}
+
+// Error returns the message of the error
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %v (%d > %d)", e.Field, e.Err, e.Length, e.Max)
+}
+
+// Unwrap returns ErrExceedItemCount or ErrExceedByteLength
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
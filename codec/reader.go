@@ -0,0 +1,130 @@
+package codec
+
+import (
+	"io"
+
+	"github.com/fletaio/common/util"
+)
+
+// LimitedReader reads from R and fails when more than N bytes are read from it
+// It is different from io.LimitedReader that returns io.EOF at the limit so a truncated message is not mistaken for a short one
+type LimitedReader struct {
+	R io.Reader
+	N int64
+}
+
+// NewLimitedReader returns a LimitedReader
+func NewLimitedReader(r io.Reader, max int64) *LimitedReader {
+	return &LimitedReader{
+		R: r,
+		N: max,
+	}
+}
+
+// Read reads up to the remained limit
+func (lr *LimitedReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if lr.N <= 0 {
+		return 0, ErrExceedMessageSize
+	}
+	if int64(len(p)) > lr.N {
+		p = p[:lr.N]
+	}
+	n, err := lr.R.Read(p)
+	lr.N -= int64(n)
+	return n, err
+}
+
+// Decode reads v from r and fails when v takes more than max bytes
+func Decode(r io.Reader, v io.ReaderFrom, max int64) (int64, error) {
+	return v.ReadFrom(NewLimitedReader(r, max))
+}
+
+// CheckItemCount returns a LimitError when the item count exceeds the maximum
+func CheckItemCount(field string, count uint64, max uint64) error {
+	if count > max {
+		return &LimitError{
+			Field:  field,
+			Length: count,
+			Max:    max,
+			Err:    ErrExceedItemCount,
+		}
+	}
+	return nil
+}
+
+// CheckByteLength returns a LimitError when the byte length exceeds the maximum
+func CheckByteLength(field string, length uint64, max uint64) error {
+	if length > max {
+		return &LimitError{
+			Field:  field,
+			Length: length,
+			Max:    max,
+			Err:    ErrExceedByteLength,
+		}
+	}
+	return nil
+}
+
+// ReadBytes reads the bytes that are written by util.WriteBytes
+// The length is checked before the allocation against the maximum and the remained limit of the LimitedReader
+func ReadBytes(r io.Reader, field string, max uint64) ([]byte, int64, error) {
+	var read int64
+	Len, n, err := readLength(r)
+	read += n
+	if err != nil {
+		return nil, read, err
+	}
+	if err := CheckByteLength(field, Len, max); err != nil {
+		return nil, read, err
+	}
+	if lr, is := r.(*LimitedReader); is && int64(Len) > lr.N {
+		return nil, read, ErrExceedMessageSize
+	}
+	bs := make([]byte, Len)
+	if n, err := io.ReadFull(r, bs); err != nil {
+		return nil, read + int64(n), err
+	} else {
+		read += int64(n)
+	}
+	return bs, read, nil
+}
+
+// ReadString reads the string that is written by util.WriteString
+func ReadString(r io.Reader, field string, max uint64) (string, int64, error) {
+	bs, n, err := ReadBytes(r, field, max)
+	if err != nil {
+		return "", n, err
+	}
+	return string(bs), n, nil
+}
+
+// readLength reads the length prefix of util.WriteBytes
+func readLength(r io.Reader) (uint64, int64, error) {
+	var read int64
+	v, n, err := util.ReadUint8(r)
+	read += n
+	if err != nil {
+		return 0, read, err
+	}
+	switch v {
+	case 254:
+		v16, n, err := util.ReadUint16(r)
+		read += n
+		if err != nil {
+			return 0, read, err
+		}
+		return uint64(v16), read, nil
+	case 255:
+		v32, n, err := util.ReadUint32(r)
+		read += n
+		if err != nil {
+			return 0, read, err
+		}
+		return uint64(v32), read, nil
+	default:
+		return uint64(v), read, nil
+	}
+}
@@ -0,0 +1,102 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/fletaio/common/util"
+)
+
+func TestReadBytes(t *testing.T) {
+	for _, size := range []int{0, 1, 253, 254, 255, 300, 65535, 65536, 70000} {
+		src := make([]byte, size)
+		for i := range src {
+			src[i] = byte(i)
+		}
+		var buffer bytes.Buffer
+		wrote, err := util.WriteBytes(&buffer, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bs, read, err := ReadBytes(&buffer, "Test", 70000)
+		if err != nil {
+			t.Fatalf("%d: %v", size, err)
+		}
+		if read != wrote {
+			t.Fatalf("%d: wrote %d but read %d", size, wrote, read)
+		}
+		if !bytes.Equal(bs, src) {
+			t.Fatalf("%d: mismatched bytes", size)
+		}
+	}
+}
+
+func TestReadBytesLimit(t *testing.T) {
+	var buffer bytes.Buffer
+	util.WriteBytes(&buffer, make([]byte, 257))
+	_, _, err := ReadBytes(&buffer, "Test", 256)
+	var le *LimitError
+	if !errors.As(err, &le) {
+		t.Fatalf("expected a limit error but %v", err)
+	}
+	if le.Field != "Test" || le.Length != 257 || le.Max != 256 || le.Err != ErrExceedByteLength {
+		t.Fatalf("unexpected limit error %+v", le)
+	}
+}
+
+func TestReadBytesHugePrefix(t *testing.T) {
+	var buffer bytes.Buffer
+	util.WriteUint8(&buffer, 255)
+	util.WriteUint32(&buffer, 0xFFFFFFFF)
+	bs := buffer.Bytes()
+
+	allocs := testing.AllocsPerRun(10, func() {
+		if _, _, err := ReadBytes(bytes.NewReader(bs), "Test", 256); !errors.Is(err, ErrExceedByteLength) {
+			t.Fatalf("expected %v but %v", ErrExceedByteLength, err)
+		}
+	})
+	if allocs > 4 {
+		t.Fatalf("allocated %v times before the limit check", allocs)
+	}
+}
+
+func TestReadBytesMessageSize(t *testing.T) {
+	var buffer bytes.Buffer
+	util.WriteBytes(&buffer, make([]byte, 200))
+	if _, _, err := ReadBytes(NewLimitedReader(&buffer, 50), "Test", 256); err != ErrExceedMessageSize {
+		t.Fatalf("expected %v but %v", ErrExceedMessageSize, err)
+	}
+}
+
+func TestReadBytesTruncated(t *testing.T) {
+	var buffer bytes.Buffer
+	util.WriteBytes(&buffer, make([]byte, 100))
+	bs := buffer.Bytes()
+	for i := 0; i < len(bs); i++ {
+		if _, _, err := ReadBytes(bytes.NewReader(bs[:i]), "Test", 256); err == nil {
+			t.Fatalf("expected an error at %d", i)
+		}
+	}
+}
+
+func TestLimitedReader(t *testing.T) {
+	src := []byte("0123456789")
+	lr := NewLimitedReader(bytes.NewReader(src), 10)
+	bs := make([]byte, 10)
+	if _, err := io.ReadFull(lr, bs); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bs, src) {
+		t.Fatal("mismatched bytes")
+	}
+
+	lr = NewLimitedReader(bytes.NewReader(src), 5)
+	if _, err := io.ReadFull(lr, bs); err != ErrExceedMessageSize {
+		t.Fatalf("expected %v but %v", ErrExceedMessageSize, err)
+	}
+	if lr.N != 0 {
+		t.Fatalf("expected no remained limit but %d", lr.N)
+	}
+}
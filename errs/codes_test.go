@@ -0,0 +1,51 @@
+package errs_test
+
+import (
+	"testing"
+
+	"github.com/fletaio/extension/account_history"
+	"github.com/fletaio/extension/chainkit"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/genesis"
+	"github.com/fletaio/extension/pool_guard"
+	"github.com/fletaio/extension/snapshot"
+	"github.com/fletaio/extension/store"
+	"github.com/fletaio/extension/tx_builder"
+	"github.com/fletaio/extension/type_registry"
+	"github.com/fletaio/extension/utxo_index"
+)
+
+// TestPackageCodes checks that the codes of the package-local errors do not overlap the codes of the errs package
+func TestPackageCodes(t *testing.T) {
+	list := []*errs.Error{
+		errs.ErrInvalidSequence, errs.ErrInsufficientBalance, errs.ErrDustAmount, errs.ErrNotMainChain, errs.ErrInvalidTransactionSignature, errs.ErrInvalidSignerCount, errs.ErrTooLongTag,
+		errs.ErrExistAddress, errs.ErrExistAccountName, errs.ErrInvalidAccountName, errs.ErrInvalidMultiSigKeyHashCount, errs.ErrInvalidAccountSigner, errs.ErrLockedAccount, errs.ErrFromTypeMustTokenAccount,
+		errs.ErrNotExistAccountName, errs.ErrReservedAccountName, errs.ErrNotNameOwner, errs.ErrSelfNameTransfer, errs.ErrSelfCloseAccount, errs.ErrInvalidDestination, errs.ErrNotClosableAccount,
+		errs.ErrInvalidPolicy, errs.ErrNotPolicyAccount, errs.ErrInvalidAdminSigner, errs.ErrNotAllowedTransactionType, errs.ErrNotAllowedDestination, errs.ErrExceedSpendLimit,
+		errs.ErrInvalidTxInCount, errs.ErrInvalidTxOutCount, errs.ErrInvalidOutputAmount,
+		errs.ErrInvalidInterval, errs.ErrInvalidSchedule, errs.ErrNotExistOrder, errs.ErrNotOrderOwner, errs.ErrSelfStandingOrder, errs.ErrInvalidStandingOrder,
+		errs.ErrInvalidMemoType, errs.ErrInvalidMemoPayload, errs.ErrTooLongMemo,
+		errs.ErrExceedItemCount, errs.ErrExceedByteLength, errs.ErrExceedMessageSize,
+		errs.ErrInvalidChainPolicy,
+
+		tx_builder.ErrUnknownTransactionField, tx_builder.ErrInvalidSignedTransaction,
+		chainkit.ErrInvalidChainConfig,
+		genesis.ErrInvalidGenesis, genesis.ErrUnsupportedGenesisAccount,
+		type_registry.ErrIncompatibleTypeTable, type_registry.ErrMissingTransactionFee,
+		snapshot.ErrInvalidSnapshot,
+		account_history.ErrInvalidHistoryHeight, account_history.ErrCorruptedHistoryStore,
+		utxo_index.ErrInvalidUTXOIndexHeight, utxo_index.ErrNotRollbackableHeight,
+		store.ErrCorruptedStore,
+		pool_guard.ErrExceedSequenceWindow, pool_guard.ErrPendingSequence, pool_guard.ErrPendingUTXO,
+	}
+	codes := map[errs.Code]string{}
+	for _, e := range list {
+		if e.Code == errs.CodeUnknown {
+			t.Fatalf("%v has the unknown code", e)
+		}
+		if prev, has := codes[e.Code]; has {
+			t.Fatalf("%v and %v have the same code %d", prev, e, e.Code)
+		}
+		codes[e.Code] = e.Message
+	}
+}
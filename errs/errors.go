@@ -0,0 +1,120 @@
+package errs
+
+// error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeUnknown Code = 0
+
+	// transaction
+	CodeInvalidSequence             Code = 1001
+	CodeInsufficientBalance         Code = 1002
+	CodeDustAmount                  Code = 1003
+	CodeNotMainChain                Code = 1004
+	CodeInvalidTransactionSignature Code = 1005
+	CodeInvalidSignerCount          Code = 1006
+	CodeTooLongTag                  Code = 1007
+
+	// account
+	CodeExistAddress                Code = 1101
+	CodeExistAccountName            Code = 1102
+	CodeInvalidAccountName          Code = 1103
+	CodeInvalidMultiSigKeyHashCount Code = 1104
+	CodeInvalidAccountSigner        Code = 1105
+	CodeLockedAccount               Code = 1106
+	CodeFromTypeMustTokenAccount    Code = 1107
+	CodeNotExistAccountName         Code = 1108
+	CodeReservedAccountName         Code = 1109
+	CodeNotNameOwner                Code = 1110
+	CodeSelfNameTransfer            Code = 1111
+	CodeSelfCloseAccount            Code = 1112
+	CodeInvalidDestination          Code = 1113
+	CodeNotClosableAccount          Code = 1114
+
+	// policy
+	CodeInvalidPolicy             Code = 1201
+	CodeNotPolicyAccount          Code = 1202
+	CodeInvalidAdminSigner        Code = 1203
+	CodeNotAllowedTransactionType Code = 1204
+	CodeNotAllowedDestination     Code = 1205
+	CodeExceedSpendLimit          Code = 1206
+
+	// utxo
+	CodeInvalidTxInCount    Code = 1301
+	CodeInvalidTxOutCount   Code = 1302
+	CodeInvalidOutputAmount Code = 1303
+
+	// standing order
+	CodeInvalidInterval      Code = 1401
+	CodeInvalidSchedule      Code = 1402
+	CodeNotExistOrder        Code = 1403
+	CodeNotOrderOwner        Code = 1404
+	CodeSelfStandingOrder    Code = 1405
+	CodeInvalidStandingOrder Code = 1406
+
+	// memo
+	CodeInvalidMemoType    Code = 1501
+	CodeInvalidMemoPayload Code = 1502
+	CodeTooLongMemo        Code = 1503
+
+	// codec
+	CodeExceedItemCount   Code = 1601
+	CodeExceedByteLength  Code = 1602
+	CodeExceedMessageSize Code = 1603
+
+	// chain
+	CodeInvalidChainPolicy Code = 1701
+)
+
+// extension errors
+var (
+	ErrInvalidSequence             = New(CodeInvalidSequence, "invalid sequence")
+	ErrInsufficientBalance         = New(CodeInsufficientBalance, "insufficient balance")
+	ErrDustAmount                  = New(CodeDustAmount, "dust amount")
+	ErrNotMainChain                = New(CodeNotMainChain, "not main chain")
+	ErrInvalidTransactionSignature = New(CodeInvalidTransactionSignature, "invalid transaction signature")
+	ErrInvalidSignerCount          = New(CodeInvalidSignerCount, "invalid signer count")
+	ErrTooLongTag                  = New(CodeTooLongTag, "too long tag")
+
+	ErrExistAddress                = New(CodeExistAddress, "exist address")
+	ErrExistAccountName            = New(CodeExistAccountName, "exist account name")
+	ErrInvalidAccountName          = New(CodeInvalidAccountName, "invalid account name")
+	ErrInvalidMultiSigKeyHashCount = New(CodeInvalidMultiSigKeyHashCount, "invalid multisig key hash count")
+	ErrInvalidAccountSigner        = New(CodeInvalidAccountSigner, "invalid account signer")
+	ErrLockedAccount               = New(CodeLockedAccount, "locked account")
+	ErrFromTypeMustTokenAccount    = New(CodeFromTypeMustTokenAccount, "only TokenAccount can initialize the chain")
+	ErrNotExistAccountName         = New(CodeNotExistAccountName, "not exist account name")
+	ErrReservedAccountName         = New(CodeReservedAccountName, "reserved account name")
+	ErrNotNameOwner                = New(CodeNotNameOwner, "not name owner")
+	ErrSelfNameTransfer            = New(CodeSelfNameTransfer, "self name transfer")
+	ErrSelfCloseAccount            = New(CodeSelfCloseAccount, "self close account")
+	ErrInvalidDestination          = New(CodeInvalidDestination, "invalid destination")
+	ErrNotClosableAccount          = New(CodeNotClosableAccount, "not closable account")
+
+	ErrInvalidPolicy             = New(CodeInvalidPolicy, "invalid policy")
+	ErrNotPolicyAccount          = New(CodeNotPolicyAccount, "not policy account")
+	ErrInvalidAdminSigner        = New(CodeInvalidAdminSigner, "invalid admin signer")
+	ErrNotAllowedTransactionType = New(CodeNotAllowedTransactionType, "not allowed transaction type")
+	ErrNotAllowedDestination     = New(CodeNotAllowedDestination, "not allowed destination")
+	ErrExceedSpendLimit          = New(CodeExceedSpendLimit, "exceed spend limit")
+
+	ErrInvalidTxInCount    = New(CodeInvalidTxInCount, "invalid txin count")
+	ErrInvalidTxOutCount   = New(CodeInvalidTxOutCount, "invalid txout count")
+	ErrInvalidOutputAmount = New(CodeInvalidOutputAmount, "invalid output amount")
+
+	ErrInvalidInterval      = New(CodeInvalidInterval, "invalid interval")
+	ErrInvalidSchedule      = New(CodeInvalidSchedule, "invalid schedule")
+	ErrNotExistOrder        = New(CodeNotExistOrder, "not exist order")
+	ErrNotOrderOwner        = New(CodeNotOrderOwner, "not order owner")
+	ErrSelfStandingOrder    = New(CodeSelfStandingOrder, "self standing order")
+	ErrInvalidStandingOrder = New(CodeInvalidStandingOrder, "invalid standing order")
+
+	ErrInvalidMemoType    = New(CodeInvalidMemoType, "invalid memo type")
+	ErrInvalidMemoPayload = New(CodeInvalidMemoPayload, "invalid memo payload")
+	ErrTooLongMemo        = New(CodeTooLongMemo, "too long memo")
+
+	ErrExceedItemCount   = New(CodeExceedItemCount, "exceed item count")
+	ErrExceedByteLength  = New(CodeExceedByteLength, "exceed byte length")
+	ErrExceedMessageSize = New(CodeExceedMessageSize, "exceed message size")
+
+	ErrInvalidChainPolicy = New(CodeInvalidChainPolicy, "invalid chain policy")
+)
@@ -0,0 +1,113 @@
+// Package errs holds the errors that are shared by the extension packages.
+//
+// Every error has a stable numeric code for RPC clients. The packages re-export the errors that they return
+// so errors.Is matches the same error across the packages.
+// Validators and executors wrap the errors by ContextError to carry the transaction type, the field and the values.
+package errs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fletaio/core/transaction"
+)
+
+// Code is the stable numeric code of an error
+type Code uint16
+
+// Error is an error that has a stable code
+type Error struct {
+	Code    Code
+	Message string
+}
+
+// New returns an Error
+func New(code Code, msg string) *Error {
+	return &Error{
+		Code:    code,
+		Message: msg,
+	}
+}
+
+// Error returns the message of the error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// CodeOf returns the code of the first Error in the chain of err
+// It returns CodeUnknown when there is no Error in the chain
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeUnknown
+}
+
+// ContextError is an error that has the context where it occurs
+type ContextError struct {
+	TxType    transaction.Type
+	HasTxType bool
+	Field     string
+	Expected  interface{}
+	Actual    interface{}
+	Err       error
+}
+
+// Wrap returns the error that has the transaction type and the field where err occurs
+func Wrap(err error, t transaction.Type, field string) error {
+	return &ContextError{
+		TxType:    t,
+		HasTxType: true,
+		Field:     field,
+		Err:       err,
+	}
+}
+
+// WrapValue returns the error that has the transaction type, the field and the expected and the actual values
+func WrapValue(err error, t transaction.Type, field string, expected interface{}, actual interface{}) error {
+	return &ContextError{
+		TxType:    t,
+		HasTxType: true,
+		Field:     field,
+		Expected:  expected,
+		Actual:    actual,
+		Err:       err,
+	}
+}
+
+// WrapField returns the error that has the field and the values but is not bound to a transaction
+// The expected and the actual values can be nil
+func WrapField(err error, field string, expected interface{}, actual interface{}) error {
+	return &ContextError{
+		Field:    field,
+		Expected: expected,
+		Actual:   actual,
+		Err:      err,
+	}
+}
+
+// Error returns the message of the error
+func (e *ContextError) Error() string {
+	msg := e.Err.Error()
+	if len(e.Field) > 0 {
+		msg = e.Field + ": " + msg
+	}
+	if e.HasTxType {
+		msg = fmt.Sprintf("tx type %d: %s", e.TxType, msg)
+	}
+	switch {
+	case e.Expected != nil && e.Actual != nil:
+		msg += fmt.Sprintf(" (expected %v, actual %v)", e.Expected, e.Actual)
+	case e.Expected != nil:
+		msg += fmt.Sprintf(" (expected %v)", e.Expected)
+	case e.Actual != nil:
+		msg += fmt.Sprintf(" (actual %v)", e.Actual)
+	}
+	return msg
+}
+
+// Unwrap returns the wrapped error
+func (e *ContextError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,60 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestContextError(t *testing.T) {
+	err := WrapValue(ErrInvalidSequence, 10, "seq", 5, 3)
+	if !errors.Is(err, ErrInvalidSequence) {
+		t.Fatalf("expected %v in the chain of %v", ErrInvalidSequence, err)
+	}
+	if CodeOf(err) != CodeInvalidSequence {
+		t.Fatalf("expected the code %d but %d", CodeInvalidSequence, CodeOf(err))
+	}
+	if msg := err.Error(); msg != "tx type 10: seq: invalid sequence (expected 5, actual 3)" {
+		t.Fatalf("unexpected message %q", msg)
+	}
+
+	err = fmt.Errorf("validate: %w", WrapField(ErrInvalidSignerCount, "signers", nil, 2))
+	if CodeOf(err) != CodeInvalidSignerCount {
+		t.Fatalf("expected the code %d but %d", CodeInvalidSignerCount, CodeOf(err))
+	}
+	var ce *ContextError
+	if !errors.As(err, &ce) || ce.HasTxType || ce.Field != "signers" || ce.Actual != 2 {
+		t.Fatalf("unexpected context error %+v", ce)
+	}
+	if msg := ce.Error(); msg != "signers: invalid signer count (actual 2)" {
+		t.Fatalf("unexpected message %q", msg)
+	}
+
+	if CodeOf(errors.New("other")) != CodeUnknown {
+		t.Fatal("expected the unknown code")
+	}
+}
+
+func TestUniqueCodes(t *testing.T) {
+	list := []*Error{
+		ErrInvalidSequence, ErrInsufficientBalance, ErrDustAmount, ErrNotMainChain, ErrInvalidTransactionSignature, ErrInvalidSignerCount, ErrTooLongTag,
+		ErrExistAddress, ErrExistAccountName, ErrInvalidAccountName, ErrInvalidMultiSigKeyHashCount, ErrInvalidAccountSigner, ErrLockedAccount, ErrFromTypeMustTokenAccount,
+		ErrNotExistAccountName, ErrReservedAccountName, ErrNotNameOwner, ErrSelfNameTransfer, ErrSelfCloseAccount, ErrInvalidDestination, ErrNotClosableAccount,
+		ErrInvalidPolicy, ErrNotPolicyAccount, ErrInvalidAdminSigner, ErrNotAllowedTransactionType, ErrNotAllowedDestination, ErrExceedSpendLimit,
+		ErrInvalidTxInCount, ErrInvalidTxOutCount, ErrInvalidOutputAmount,
+		ErrInvalidInterval, ErrInvalidSchedule, ErrNotExistOrder, ErrNotOrderOwner, ErrSelfStandingOrder, ErrInvalidStandingOrder,
+		ErrInvalidMemoType, ErrInvalidMemoPayload, ErrTooLongMemo,
+		ErrExceedItemCount, ErrExceedByteLength, ErrExceedMessageSize,
+		ErrInvalidChainPolicy,
+	}
+	codes := map[Code]string{}
+	for _, e := range list {
+		if e.Code == CodeUnknown {
+			t.Fatalf("%v has the unknown code", e)
+		}
+		if prev, has := codes[e.Code]; has {
+			t.Fatalf("%v and %v have the same code %d", prev, e, e.Code)
+		}
+		codes[e.Code] = e.Message
+	}
+}
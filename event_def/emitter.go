@@ -0,0 +1,141 @@
+package event_def
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/transaction"
+)
+
+// Emitter emits the events of a transaction to the context
+// The events of the transaction share the coordinate of the transaction and are indexed in the emitted order
+type Emitter struct {
+	ctx   *data.Context
+	coord *common.Coordinate
+	index uint16
+}
+
+// NewEmitter returns a Emitter of the transaction at the coordinate
+func NewEmitter(ctx *data.Context, coord *common.Coordinate) *Emitter {
+	return &Emitter{
+		ctx:   ctx,
+		coord: coord.Clone(),
+	}
+}
+
+func (em *Emitter) emit(e event.Event, base *event.Base) error {
+	base.Coord_ = em.coord
+	base.Index_ = em.index
+	if err := em.ctx.EmitEvent(e); err != nil {
+		return err
+	}
+	em.index++
+	return nil
+}
+
+// Transfer emits a TransferEvent
+func (em *Emitter) Transfer(From common.Address, To common.Address, am *amount.Amount) error {
+	e, err := em.ctx.Eventer().NewByTypeName("fleta.TransferEvent")
+	if err != nil {
+		return err
+	}
+	ev := e.(*TransferEvent)
+	ev.From = From
+	ev.To = To
+	ev.Amount = am.Clone()
+	return em.emit(ev, &ev.Base)
+}
+
+// AccountCreated emits a AccountCreatedEvent
+// The name is given because a claimed name is owned through the registry and is not the name of the account
+func (em *Emitter) AccountCreated(acc account.Account, name string) error {
+	e, err := em.ctx.Eventer().NewByTypeName("fleta.AccountCreatedEvent")
+	if err != nil {
+		return err
+	}
+	ev := e.(*AccountCreatedEvent)
+	ev.Address = acc.Address()
+	ev.Name = name
+	ev.AccountType = acc.Type()
+	return em.emit(ev, &ev.Base)
+}
+
+// UTXOCreated emits a UTXOCreatedEvent
+func (em *Emitter) UTXOCreated(id uint64, vout *transaction.TxOut) error {
+	e, err := em.ctx.Eventer().NewByTypeName("fleta.UTXOCreatedEvent")
+	if err != nil {
+		return err
+	}
+	ev := e.(*UTXOCreatedEvent)
+	ev.ID = id
+	ev.PublicHash = vout.PublicHash
+	ev.Amount = vout.Amount.Clone()
+	return em.emit(ev, &ev.Base)
+}
+
+// UTXOSpent emits a UTXOSpentEvent
+func (em *Emitter) UTXOSpent(id uint64, am *amount.Amount) error {
+	e, err := em.ctx.Eventer().NewByTypeName("fleta.UTXOSpentEvent")
+	if err != nil {
+		return err
+	}
+	ev := e.(*UTXOSpentEvent)
+	ev.ID = id
+	ev.Amount = am.Clone()
+	return em.emit(ev, &ev.Base)
+}
+
+// TokenCreated emits a TokenCreatedEvent
+func (em *Emitter) TokenCreated(addr common.Address, TokenName string, TokenCoord *common.Coordinate, TokenPublicHash common.PublicHash) error {
+	e, err := em.ctx.Eventer().NewByTypeName("fleta.TokenCreatedEvent")
+	if err != nil {
+		return err
+	}
+	ev := e.(*TokenCreatedEvent)
+	ev.Address = addr
+	ev.TokenName = TokenName
+	ev.TokenCoord = *TokenCoord.Clone()
+	ev.TokenPublicHash = TokenPublicHash
+	return em.emit(ev, &ev.Base)
+}
+
+// ChainInitialized emits a ChainInitializedEvent
+func (em *Emitter) ChainInitialized(addr common.Address, GenesisContextHash hash.Hash256) error {
+	e, err := em.ctx.Eventer().NewByTypeName("fleta.ChainInitializedEvent")
+	if err != nil {
+		return err
+	}
+	ev := e.(*ChainInitializedEvent)
+	ev.Address = addr
+	ev.GenesisContextHash = GenesisContextHash
+	return em.emit(ev, &ev.Base)
+}
+
+// TokenIssued emits a TokenIssuedEvent
+func (em *Emitter) TokenIssued(TokenAddress common.Address, Height uint32, am *amount.Amount) error {
+	e, err := em.ctx.Eventer().NewByTypeName("fleta.TokenIssuedEvent")
+	if err != nil {
+		return err
+	}
+	ev := e.(*TokenIssuedEvent)
+	ev.TokenAddress = TokenAddress
+	ev.Height = Height
+	ev.Amount = am.Clone()
+	return em.emit(ev, &ev.Base)
+}
+
+// DappEngraved emits a DappEngravedEvent
+func (em *Emitter) DappEngraved(addr common.Address, Height uint32, BlockHash hash.Hash256) error {
+	e, err := em.ctx.Eventer().NewByTypeName("fleta.DappEngravedEvent")
+	if err != nil {
+		return err
+	}
+	ev := e.(*DappEngravedEvent)
+	ev.Address = addr
+	ev.Height = Height
+	ev.BlockHash = BlockHash
+	return em.emit(ev, &ev.Base)
+}
@@ -0,0 +1,129 @@
+package event_def
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/event"
+)
+
+func init() {
+	data.RegisterEvent("fleta.TransferEvent", func(coord *common.Coordinate, index uint16, t event.Type) event.Event {
+		return &TransferEvent{
+			Base:   event.Base{Coord_: coord, Index_: index, Type_: t},
+			Amount: amount.NewCoinAmount(0, 0),
+		}
+	})
+	data.RegisterEvent("fleta.AccountCreatedEvent", func(coord *common.Coordinate, index uint16, t event.Type) event.Event {
+		return &AccountCreatedEvent{
+			Base: event.Base{Coord_: coord, Index_: index, Type_: t},
+		}
+	})
+	data.RegisterEvent("fleta.UTXOCreatedEvent", func(coord *common.Coordinate, index uint16, t event.Type) event.Event {
+		return &UTXOCreatedEvent{
+			Base:   event.Base{Coord_: coord, Index_: index, Type_: t},
+			Amount: amount.NewCoinAmount(0, 0),
+		}
+	})
+	data.RegisterEvent("fleta.UTXOSpentEvent", func(coord *common.Coordinate, index uint16, t event.Type) event.Event {
+		return &UTXOSpentEvent{
+			Base:   event.Base{Coord_: coord, Index_: index, Type_: t},
+			Amount: amount.NewCoinAmount(0, 0),
+		}
+	})
+	data.RegisterEvent("fleta.TokenCreatedEvent", func(coord *common.Coordinate, index uint16, t event.Type) event.Event {
+		return &TokenCreatedEvent{
+			Base: event.Base{Coord_: coord, Index_: index, Type_: t},
+		}
+	})
+	data.RegisterEvent("fleta.ChainInitializedEvent", func(coord *common.Coordinate, index uint16, t event.Type) event.Event {
+		return &ChainInitializedEvent{
+			Base: event.Base{Coord_: coord, Index_: index, Type_: t},
+		}
+	})
+	data.RegisterEvent("fleta.TokenIssuedEvent", func(coord *common.Coordinate, index uint16, t event.Type) event.Event {
+		return &TokenIssuedEvent{
+			Base:   event.Base{Coord_: coord, Index_: index, Type_: t},
+			Amount: amount.NewCoinAmount(0, 0),
+		}
+	})
+	data.RegisterEvent("fleta.DappEngravedEvent", func(coord *common.Coordinate, index uint16, t event.Type) event.Event {
+		return &DappEngravedEvent{
+			Base: event.Base{Coord_: coord, Index_: index, Type_: t},
+		}
+	})
+}
+
+// TransferEvent is a fleta.TransferEvent
+// It is emitted when the balance is moved from an account to another
+// From is empty when the amount comes from the UTXOs and To is empty when the amount leaves the accounts by a withdrawal or a burn
+type TransferEvent struct {
+	event.Base
+	From   common.Address `codec:"from"`
+	To     common.Address `codec:"to"`
+	Amount *amount.Amount `codec:"amount"`
+}
+
+// AccountCreatedEvent is a fleta.AccountCreatedEvent
+// It is emitted when a new account is created by a transaction
+type AccountCreatedEvent struct {
+	event.Base
+	Address     common.Address `codec:"address"`
+	Name        string         `codec:"name,max=64"`
+	AccountType account.Type   `codec:"account_type"`
+}
+
+// UTXOCreatedEvent is a fleta.UTXOCreatedEvent
+// It is emitted for each UTXO that is created by a transaction
+type UTXOCreatedEvent struct {
+	event.Base
+	ID         uint64            `codec:"id"`
+	PublicHash common.PublicHash `codec:"public_hash"`
+	Amount     *amount.Amount    `codec:"amount"`
+}
+
+// UTXOSpentEvent is a fleta.UTXOSpentEvent
+// It is emitted for each UTXO that is spent by a transaction
+type UTXOSpentEvent struct {
+	event.Base
+	ID     uint64         `codec:"id"`
+	Amount *amount.Amount `codec:"amount"`
+}
+
+// TokenCreatedEvent is a fleta.TokenCreatedEvent
+// It is emitted when a token account is created so the token chain can be started from it
+type TokenCreatedEvent struct {
+	event.Base
+	Address         common.Address    `codec:"address"`
+	TokenName       string            `codec:"token_name,max=64"`
+	TokenCoord      common.Coordinate `codec:"token_coord"`
+	TokenPublicHash common.PublicHash `codec:"token_public_hash"`
+}
+
+// ChainInitializedEvent is a fleta.ChainInitializedEvent
+// It is emitted when the token chain is initialized by the token account
+type ChainInitializedEvent struct {
+	event.Base
+	Address            common.Address `codec:"address"`
+	GenesisContextHash hash.Hash256   `codec:"genesis_context_hash"`
+}
+
+// TokenIssuedEvent is a fleta.TokenIssuedEvent
+// It is emitted when the token is issued to the token account
+type TokenIssuedEvent struct {
+	event.Base
+	TokenAddress common.Address `codec:"token_address"`
+	Height       uint32         `codec:"height"`
+	Amount       *amount.Amount `codec:"amount"`
+}
+
+// DappEngravedEvent is a fleta.DappEngravedEvent
+// It is emitted when the block of the dapp chain is engraved to the main chain
+type DappEngravedEvent struct {
+	event.Base
+	Address   common.Address `codec:"address"`
+	Height    uint32         `codec:"height"`
+	BlockHash hash.Hash256   `codec:"block_hash"`
+}
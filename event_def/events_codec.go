@@ -0,0 +1,1093 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package event_def
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/json_util"
+)
+
+// WriteTo is a serialization function
+func (te *TransferEvent) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := te.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := te.From.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := te.To.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := te.Amount.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (te *TransferEvent) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := te.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := te.From.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := te.To.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	te.Amount = amount.NewCoinAmount(0, 0)
+	if n, err := te.Amount.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (te *TransferEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: te.Coord_.Height,
+		Index:  te.Coord_.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(te.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(te.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := te.From.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"to":`)
+	if bs, err := te.To.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := te.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (te *TransferEvent) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Coord  json_util.Coordinate `json:"coord"`
+		Index  uint16               `json:"index"`
+		Type   event.Type           `json:"type"`
+		From   string               `json:"from"`
+		To     string               `json:"to"`
+		Amount json.RawMessage      `json:"amount"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	te.Coord_ = common.NewCoordinate(v.Coord.Height, v.Coord.Index)
+	te.Index_ = v.Index
+	te.Type_ = v.Type
+	if addr, err := common.ParseAddress(v.From); err != nil {
+		return err
+	} else {
+		te.From = addr
+	}
+	if addr, err := common.ParseAddress(v.To); err != nil {
+		return err
+	} else {
+		te.To = addr
+	}
+	if am, err := json_util.ParseAmount(v.Amount); err != nil {
+		return err
+	} else {
+		te.Amount = am
+	}
+	return nil
+}
+
+// WriteTo is a serialization function
+func (ace *AccountCreatedEvent) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := ace.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := ace.Address.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckByteLength("AccountCreatedEvent.Name", uint64(len(ace.Name)), 64); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteString(w, ace.Name); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint8(w, uint8(ace.AccountType)); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (ace *AccountCreatedEvent) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := ace.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := ace.Address.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := codec.ReadString(r, "AccountCreatedEvent.Name", 64); err != nil {
+		return read, err
+	} else {
+		read += n
+		ace.Name = v
+	}
+	if v, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		ace.AccountType = account.Type(v)
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (ace *AccountCreatedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: ace.Coord_.Height,
+		Index:  ace.Coord_.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(ace.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(ace.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"address":`)
+	if bs, err := ace.Address.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(ace.Name); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"account_type":`)
+	if bs, err := json.Marshal(ace.AccountType); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (ace *AccountCreatedEvent) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Coord       json_util.Coordinate `json:"coord"`
+		Index       uint16               `json:"index"`
+		Type        event.Type           `json:"type"`
+		Address     string               `json:"address"`
+		Name        string               `json:"name"`
+		AccountType account.Type         `json:"account_type"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	ace.Coord_ = common.NewCoordinate(v.Coord.Height, v.Coord.Index)
+	ace.Index_ = v.Index
+	ace.Type_ = v.Type
+	if addr, err := common.ParseAddress(v.Address); err != nil {
+		return err
+	} else {
+		ace.Address = addr
+	}
+	if err := codec.CheckByteLength("AccountCreatedEvent.Name", uint64(len(v.Name)), 64); err != nil {
+		return err
+	}
+	ace.Name = v.Name
+	ace.AccountType = v.AccountType
+	return nil
+}
+
+// WriteTo is a serialization function
+func (utxoce *UTXOCreatedEvent) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := utxoce.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint64(w, utxoce.ID); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := utxoce.PublicHash.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := utxoce.Amount.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (utxoce *UTXOCreatedEvent) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := utxoce.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint64(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		utxoce.ID = v
+	}
+	if n, err := utxoce.PublicHash.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	utxoce.Amount = amount.NewCoinAmount(0, 0)
+	if n, err := utxoce.Amount.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (utxoce *UTXOCreatedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: utxoce.Coord_.Height,
+		Index:  utxoce.Coord_.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(utxoce.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(utxoce.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"id":`)
+	if bs, err := json.Marshal(utxoce.ID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"public_hash":`)
+	if bs, err := utxoce.PublicHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := utxoce.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (utxoce *UTXOCreatedEvent) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Coord      json_util.Coordinate `json:"coord"`
+		Index      uint16               `json:"index"`
+		Type       event.Type           `json:"type"`
+		ID         uint64               `json:"id"`
+		PublicHash string               `json:"public_hash"`
+		Amount     json.RawMessage      `json:"amount"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	utxoce.Coord_ = common.NewCoordinate(v.Coord.Height, v.Coord.Index)
+	utxoce.Index_ = v.Index
+	utxoce.Type_ = v.Type
+	utxoce.ID = v.ID
+	if pubhash, err := common.ParsePublicHash(v.PublicHash); err != nil {
+		return err
+	} else {
+		utxoce.PublicHash = pubhash
+	}
+	if am, err := json_util.ParseAmount(v.Amount); err != nil {
+		return err
+	} else {
+		utxoce.Amount = am
+	}
+	return nil
+}
+
+// WriteTo is a serialization function
+func (utxose *UTXOSpentEvent) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := utxose.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint64(w, utxose.ID); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := utxose.Amount.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (utxose *UTXOSpentEvent) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := utxose.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint64(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		utxose.ID = v
+	}
+	utxose.Amount = amount.NewCoinAmount(0, 0)
+	if n, err := utxose.Amount.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (utxose *UTXOSpentEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: utxose.Coord_.Height,
+		Index:  utxose.Coord_.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(utxose.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(utxose.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"id":`)
+	if bs, err := json.Marshal(utxose.ID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := utxose.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (utxose *UTXOSpentEvent) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Coord  json_util.Coordinate `json:"coord"`
+		Index  uint16               `json:"index"`
+		Type   event.Type           `json:"type"`
+		ID     uint64               `json:"id"`
+		Amount json.RawMessage      `json:"amount"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	utxose.Coord_ = common.NewCoordinate(v.Coord.Height, v.Coord.Index)
+	utxose.Index_ = v.Index
+	utxose.Type_ = v.Type
+	utxose.ID = v.ID
+	if am, err := json_util.ParseAmount(v.Amount); err != nil {
+		return err
+	} else {
+		utxose.Amount = am
+	}
+	return nil
+}
+
+// WriteTo is a serialization function
+func (tce *TokenCreatedEvent) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tce.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tce.Address.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckByteLength("TokenCreatedEvent.TokenName", uint64(len(tce.TokenName)), 64); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteString(w, tce.TokenName); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tce.TokenCoord.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tce.TokenPublicHash.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tce *TokenCreatedEvent) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tce.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := tce.Address.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := codec.ReadString(r, "TokenCreatedEvent.TokenName", 64); err != nil {
+		return read, err
+	} else {
+		read += n
+		tce.TokenName = v
+	}
+	if n, err := tce.TokenCoord.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := tce.TokenPublicHash.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tce *TokenCreatedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: tce.Coord_.Height,
+		Index:  tce.Coord_.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(tce.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tce.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"address":`)
+	if bs, err := tce.Address.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_name":`)
+	if bs, err := json.Marshal(tce.TokenName); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: tce.TokenCoord.Height,
+		Index:  tce.TokenCoord.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_public_hash":`)
+	if bs, err := tce.TokenPublicHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tce *TokenCreatedEvent) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Coord           json_util.Coordinate `json:"coord"`
+		Index           uint16               `json:"index"`
+		Type            event.Type           `json:"type"`
+		Address         string               `json:"address"`
+		TokenName       string               `json:"token_name"`
+		TokenCoord      json_util.Coordinate `json:"token_coord"`
+		TokenPublicHash string               `json:"token_public_hash"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tce.Coord_ = common.NewCoordinate(v.Coord.Height, v.Coord.Index)
+	tce.Index_ = v.Index
+	tce.Type_ = v.Type
+	if addr, err := common.ParseAddress(v.Address); err != nil {
+		return err
+	} else {
+		tce.Address = addr
+	}
+	if err := codec.CheckByteLength("TokenCreatedEvent.TokenName", uint64(len(v.TokenName)), 64); err != nil {
+		return err
+	}
+	tce.TokenName = v.TokenName
+	tce.TokenCoord = *common.NewCoordinate(v.TokenCoord.Height, v.TokenCoord.Index)
+	if pubhash, err := common.ParsePublicHash(v.TokenPublicHash); err != nil {
+		return err
+	} else {
+		tce.TokenPublicHash = pubhash
+	}
+	return nil
+}
+
+// WriteTo is a serialization function
+func (cie *ChainInitializedEvent) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := cie.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := cie.Address.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := cie.GenesisContextHash.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (cie *ChainInitializedEvent) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := cie.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := cie.Address.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := cie.GenesisContextHash.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (cie *ChainInitializedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: cie.Coord_.Height,
+		Index:  cie.Coord_.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(cie.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(cie.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"address":`)
+	if bs, err := cie.Address.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"genesis_context_hash":`)
+	if bs, err := cie.GenesisContextHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (cie *ChainInitializedEvent) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Coord              json_util.Coordinate `json:"coord"`
+		Index              uint16               `json:"index"`
+		Type               event.Type           `json:"type"`
+		Address            string               `json:"address"`
+		GenesisContextHash string               `json:"genesis_context_hash"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	cie.Coord_ = common.NewCoordinate(v.Coord.Height, v.Coord.Index)
+	cie.Index_ = v.Index
+	cie.Type_ = v.Type
+	if addr, err := common.ParseAddress(v.Address); err != nil {
+		return err
+	} else {
+		cie.Address = addr
+	}
+	if h, err := hash.ParseHash(v.GenesisContextHash); err != nil {
+		return err
+	} else {
+		cie.GenesisContextHash = h
+	}
+	return nil
+}
+
+// WriteTo is a serialization function
+func (tie *TokenIssuedEvent) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tie.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tie.TokenAddress.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, tie.Height); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tie.Amount.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tie *TokenIssuedEvent) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tie.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := tie.TokenAddress.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		tie.Height = v
+	}
+	tie.Amount = amount.NewCoinAmount(0, 0)
+	if n, err := tie.Amount.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tie *TokenIssuedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: tie.Coord_.Height,
+		Index:  tie.Coord_.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(tie.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tie.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_address":`)
+	if bs, err := tie.TokenAddress.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(tie.Height); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tie.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tie *TokenIssuedEvent) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Coord        json_util.Coordinate `json:"coord"`
+		Index        uint16               `json:"index"`
+		Type         event.Type           `json:"type"`
+		TokenAddress string               `json:"token_address"`
+		Height       uint32               `json:"height"`
+		Amount       json.RawMessage      `json:"amount"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tie.Coord_ = common.NewCoordinate(v.Coord.Height, v.Coord.Index)
+	tie.Index_ = v.Index
+	tie.Type_ = v.Type
+	if addr, err := common.ParseAddress(v.TokenAddress); err != nil {
+		return err
+	} else {
+		tie.TokenAddress = addr
+	}
+	tie.Height = v.Height
+	if am, err := json_util.ParseAmount(v.Amount); err != nil {
+		return err
+	} else {
+		tie.Amount = am
+	}
+	return nil
+}
+
+// WriteTo is a serialization function
+func (dee *DappEngravedEvent) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := dee.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := dee.Address.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, dee.Height); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := dee.BlockHash.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (dee *DappEngravedEvent) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := dee.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := dee.Address.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		dee.Height = v
+	}
+	if n, err := dee.BlockHash.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (dee *DappEngravedEvent) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: dee.Coord_.Height,
+		Index:  dee.Coord_.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"index":`)
+	if bs, err := json.Marshal(dee.Index_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(dee.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"address":`)
+	if bs, err := dee.Address.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(dee.Height); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"block_hash":`)
+	if bs, err := dee.BlockHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (dee *DappEngravedEvent) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Coord     json_util.Coordinate `json:"coord"`
+		Index     uint16               `json:"index"`
+		Type      event.Type           `json:"type"`
+		Address   string               `json:"address"`
+		Height    uint32               `json:"height"`
+		BlockHash string               `json:"block_hash"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	dee.Coord_ = common.NewCoordinate(v.Coord.Height, v.Coord.Index)
+	dee.Index_ = v.Index
+	dee.Type_ = v.Type
+	if addr, err := common.ParseAddress(v.Address); err != nil {
+		return err
+	} else {
+		dee.Address = addr
+	}
+	dee.Height = v.Height
+	if h, err := hash.ParseHash(v.BlockHash); err != nil {
+		return err
+	} else {
+		dee.BlockHash = h
+	}
+	return nil
+}
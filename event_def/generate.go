@@ -0,0 +1,3 @@
+package event_def
+
+//go:generate go run ../codec/codecgen -type=TransferEvent,AccountCreatedEvent,UTXOCreatedEvent,UTXOSpentEvent,TokenCreatedEvent,ChainInitializedEvent,TokenIssuedEvent,DappEngravedEvent
@@ -0,0 +1,33 @@
+package event_def
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func TestEventJSON(t *testing.T) {
+	for _, pair := range testEvents() {
+		test_util.JSONRoundTrip(t, pair[0].(test_util.JSONValue), pair[1].(test_util.JSONValue))
+	}
+
+	bs, err := json.Marshal(testEvents()[0][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		Coord struct {
+			Height uint32 `json:"height"`
+			Index  uint16 `json:"index"`
+		} `json:"coord"`
+		Index uint16 `json:"index"`
+		Type  uint8  `json:"type"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Coord.Height != 1 || v.Coord.Index != 2 || v.Index != 3 || v.Type != 10 {
+		t.Errorf("unexpected event base %s", bs)
+	}
+}
@@ -0,0 +1,69 @@
+package event_def
+
+import (
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func testBase(t event.Type) event.Base {
+	return event.Base{
+		Coord_: common.NewCoordinate(1, 2),
+		Index_: 3,
+		Type_:  t,
+	}
+}
+
+// testEvents returns the pairs of a filled event and an empty event of the same type
+func testEvents() [][2]event.Event {
+	from := common.NewAddress(common.NewCoordinate(1, 2), 0)
+	to := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	return [][2]event.Event{
+		{
+			&TransferEvent{Base: testBase(10), From: from, To: to, Amount: amount.NewCoinAmount(1, 5)},
+			&TransferEvent{Amount: amount.NewCoinAmount(0, 0)},
+		},
+		{
+			&AccountCreatedEvent{Base: testBase(11), Address: to, Name: "testaccount", AccountType: 10},
+			&AccountCreatedEvent{},
+		},
+		{
+			&UTXOCreatedEvent{Base: testBase(20), ID: 1234, PublicHash: test_util.PublicHash(1), Amount: amount.NewCoinAmount(2, 0)},
+			&UTXOCreatedEvent{Amount: amount.NewCoinAmount(0, 0)},
+		},
+		{
+			&UTXOSpentEvent{Base: testBase(21), ID: 5678, Amount: amount.NewCoinAmount(3, 0)},
+			&UTXOSpentEvent{Amount: amount.NewCoinAmount(0, 0)},
+		},
+		{
+			&TokenCreatedEvent{Base: testBase(30), Address: to, TokenName: "testtoken", TokenCoord: *common.NewCoordinate(3, 4), TokenPublicHash: test_util.PublicHash(2)},
+			&TokenCreatedEvent{},
+		},
+		{
+			&ChainInitializedEvent{Base: testBase(31), Address: to, GenesisContextHash: hash.DoubleHash([]byte("genesis"))},
+			&ChainInitializedEvent{},
+		},
+		{
+			&TokenIssuedEvent{Base: testBase(32), TokenAddress: to, Height: 100, Amount: amount.NewCoinAmount(4, 0)},
+			&TokenIssuedEvent{Amount: amount.NewCoinAmount(0, 0)},
+		},
+		{
+			&DappEngravedEvent{Base: testBase(33), Address: from, Height: 200, BlockHash: hash.DoubleHash([]byte("block"))},
+			&DappEngravedEvent{},
+		},
+	}
+}
+
+func TestEventBinary(t *testing.T) {
+	for _, pair := range testEvents() {
+		src, dst := pair[0], pair[1]
+		test_util.BinaryRoundTrip(t, src, dst)
+		if !dst.Coord().Equal(src.Coord()) || dst.Index() != src.Index() || dst.Type() != src.Type() {
+			t.Errorf("%T: base mismatch", src)
+		}
+	}
+}
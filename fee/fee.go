@@ -0,0 +1,107 @@
+package fee
+
+import (
+	"io"
+	"sync"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+)
+
+// Measurable is a transaction that reports its size and output count to the fee model
+type Measurable interface {
+	Size() (int64, error)
+	OutputCount() int
+}
+
+// Model is a fee model that adds size and output count based components to the flat fee of the transaction type
+type Model struct {
+	FreeBytes int64
+	PerByte   *amount.Amount
+	PerOutput *amount.Amount
+}
+
+// Required returns the fee that is required for the measured transaction
+// It returns the error of the serialization when the transaction cannot be measured like a field over its limit
+func (m *Model) Required(Fee *amount.Amount, tx Measurable) (*amount.Amount, error) {
+	required := Fee.Clone()
+	if m.PerByte != nil {
+		size, err := tx.Size()
+		if err != nil {
+			return nil, err
+		}
+		if size -= m.FreeBytes; size > 0 {
+			required = required.Add(m.PerByte.MulC(size))
+		}
+	}
+	if m.PerOutput != nil {
+		if cnt := tx.OutputCount(); cnt > 0 {
+			required = required.Add(m.PerOutput.MulC(int64(cnt)))
+		}
+	}
+	return required, nil
+}
+
+var modelLock sync.RWMutex
+var modelMap = map[common.Coordinate]*Model{}
+
+// RegisterModel sets the fee model of the chain
+func RegisterModel(coord *common.Coordinate, m *Model) {
+	modelLock.Lock()
+	defer modelLock.Unlock()
+
+	modelMap[*coord] = m
+}
+
+// ModelOf returns the fee model of the chain or nil if it is not registered
+func ModelOf(coord *common.Coordinate) *Model {
+	modelLock.RLock()
+	defer modelLock.RUnlock()
+
+	return modelMap[*coord]
+}
+
+// Required returns the fee that is required for the transaction in the chain
+// It returns the flat fee when the chain has no fee model or the transaction is not measurable
+func Required(coord *common.Coordinate, Fee *amount.Amount, t transaction.Transaction) (*amount.Amount, error) {
+	m := ModelOf(coord)
+	if m == nil {
+		return Fee, nil
+	}
+	tx, is := t.(Measurable)
+	if !is {
+		return Fee, nil
+	}
+	return m.Required(Fee, tx)
+}
+
+// Estimate returns the fee that is required for the transaction using the flat fee of the transactor
+// It should be called after all fields except signatures are filled
+func Estimate(tran *data.Transactor, t transaction.Transaction) (*amount.Amount, error) {
+	Fee, err := tran.Fee(t.Type())
+	if err != nil {
+		return nil, err
+	}
+	return Required(tran.ChainCoord(), Fee, t)
+}
+
+// Size returns the serialized byte size of the value
+// It returns the error of the serialization like the LimitError of a field over its limit
+func Size(v io.WriterTo) (int64, error) {
+	var cw countWriter
+	if _, err := v.WriteTo(&cw); err != nil {
+		return 0, err
+	}
+	return cw.n, nil
+}
+
+type countWriter struct {
+	n int64
+}
+
+func (cw *countWriter) Write(bs []byte) (int, error) {
+	cw.n += int64(len(bs))
+	return len(bs), nil
+}
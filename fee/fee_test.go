@@ -0,0 +1,136 @@
+package fee_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/fee"
+	"github.com/fletaio/extension/internal/test_util"
+	"github.com/fletaio/extension/type_registry"
+	"github.com/fletaio/extension/utxo_tx"
+)
+
+type testTx struct {
+	transaction.Transaction
+	size    int64
+	outputs int
+	err     error
+}
+
+func (tx *testTx) Size() (int64, error) { return tx.size, tx.err }
+func (tx *testTx) OutputCount() int     { return tx.outputs }
+
+func coin(n int64) *amount.Amount {
+	return amount.NewCoinAmount(uint64(n), 0)
+}
+
+func TestModelRequired(t *testing.T) {
+	m := &fee.Model{
+		FreeBytes: 100,
+		PerByte:   coin(1),
+		PerOutput: coin(10),
+	}
+	tests := []struct {
+		name     string
+		model    *fee.Model
+		tx       *testTx
+		expected *amount.Amount
+	}{
+		{"below free bytes", m, &testTx{size: 50}, coin(5)},
+		{"at free bytes", m, &testTx{size: 100}, coin(5)},
+		{"above free bytes", m, &testTx{size: 130}, coin(35)},
+		{"outputs", m, &testTx{size: 100, outputs: 3}, coin(35)},
+		{"bytes and outputs", m, &testTx{size: 102, outputs: 1}, coin(17)},
+		{"no per byte", &fee.Model{PerOutput: coin(10)}, &testTx{size: 1000, outputs: 2}, coin(25)},
+		{"no per output", &fee.Model{PerByte: coin(1)}, &testTx{size: 3, outputs: 2}, coin(8)},
+	}
+	for _, tt := range tests {
+		required, err := tt.model.Required(coin(5), tt.tx)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !required.Equal(tt.expected) {
+			t.Errorf("%s: expected %v but %v", tt.name, tt.expected, required)
+		}
+	}
+
+	if _, err := m.Required(coin(5), &testTx{err: codec.ErrExceedByteLength}); err != codec.ErrExceedByteLength {
+		t.Errorf("expected %v but %v", codec.ErrExceedByteLength, err)
+	}
+}
+
+func TestRequired(t *testing.T) {
+	coord := common.NewCoordinate(101, 0)
+	tx := &testTx{size: 1000, outputs: 10}
+
+	// the flat fee is required without a model
+	if required, err := fee.Required(coord, coin(5), tx); err != nil || !required.Equal(coin(5)) {
+		t.Errorf("expected the flat fee but %v %v", required, err)
+	}
+	fee.RegisterModel(coord, &fee.Model{PerOutput: coin(1)})
+	if required, err := fee.Required(coord, coin(5), tx); err != nil || !required.Equal(coin(15)) {
+		t.Errorf("expected the fee of the model but %v %v", required, err)
+	}
+	if required, err := fee.Required(coord, coin(5), tx.Transaction); err != nil || !required.Equal(coin(5)) {
+		t.Errorf("expected the flat fee of the not measurable transaction but %v %v", required, err)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	coord := common.NewCoordinate(102, 0)
+	tran := data.NewTransactor(coord)
+	if err := type_registry.Register(data.NewAccounter(coord), tran, data.NewEventer(coord)); err != nil {
+		t.Fatal(err)
+	}
+	m := &fee.Model{
+		FreeBytes: 10,
+		PerByte:   amount.NewCoinAmount(0, 1000),
+		PerOutput: coin(1),
+	}
+	fee.RegisterModel(coord, m)
+	newTx := func(name string) transaction.Transaction {
+		tx, err := tran.NewByTypeName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	transfer := newTx("fleta.Transfer").(*account_tx.Transfer)
+	transfer.Amount = coin(1)
+	transfer.To = account_name.NewAddressDestination(test_util.Address(1))
+	withdraw := newTx("fleta.Withdraw").(*account_tx.Withdraw)
+	withdraw.Vout = []*transaction.TxOut{{Amount: coin(1)}, {Amount: coin(2)}}
+	assign := newTx("fleta.Assign").(*utxo_tx.Assign)
+	assign.Vin = []*transaction.TxIn{transaction.NewTxIn(1)}
+	assign.Vout = []*transaction.TxOut{{Amount: coin(1)}}
+
+	for _, tx := range []fee.Measurable{transfer, withdraw, assign} {
+		flat, err := tran.Fee(tx.(transaction.Transaction).Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		size, err := tx.Size()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := flat.Add(m.PerByte.MulC(size - m.FreeBytes)).Add(m.PerOutput.MulC(int64(tx.OutputCount())))
+		if estimated, err := fee.Estimate(tran, tx.(transaction.Transaction)); err != nil || !estimated.Equal(expected) {
+			t.Errorf("expected %v but %v %v", expected, estimated, err)
+		}
+	}
+
+	// the transaction over the limit of the codec is rejected
+	transfer.Tag = make([]byte, 257)
+	var le *codec.LimitError
+	if _, err := fee.Estimate(tran, transfer); !errors.As(err, &le) {
+		t.Errorf("expected a limit error but %v", err)
+	}
+}
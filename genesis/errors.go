@@ -0,0 +1,23 @@
+package genesis
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// genesis error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeInvalidGenesis            errs.Code = 2001
+	CodeUnsupportedGenesisAccount errs.Code = 1902
+)
+
+// genesis errors
+var (
+	ErrInvalidGenesis              = errs.New(CodeInvalidGenesis, "invalid genesis")
+	ErrUnsupportedGenesisAccount   = errs.New(CodeUnsupportedGenesisAccount, "unsupported genesis account")
+	ErrExistAddress                = errs.ErrExistAddress
+	ErrExistAccountName            = errs.ErrExistAccountName
+	ErrInvalidAccountName          = errs.ErrInvalidAccountName
+	ErrInvalidMultiSigKeyHashCount = errs.ErrInvalidMultiSigKeyHashCount
+	ErrDustAmount                  = errs.ErrDustAmount
+)
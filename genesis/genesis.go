@@ -0,0 +1,407 @@
+// Package genesis loads the genesis file that declares the extension accounts and the UTXOs of a chain.
+//
+// The genesis file is a JSON object of the chain coordinate, the accounts and the UTXOs:
+//
+//	{
+//		"chain_coord": {"height": 0, "index": 0},
+//		"policy": {"dust_amount": "0.1", "name_length": {"min": 8, "max": 16}, "multisig_key_hash_count": {"min": 2, "max": 10}, "max_tag_length": 256, "reserved_names": ["fletafoundation"]},
+//		"accounts": [
+//			{"type": "fleta.SingleAccount", "address": "3CUsUpv9v", "name": "foundation", "balance": "1000000", "key_hash": "..."},
+//			{"type": "fleta.MultiSigAccount", "address": "...", "name": "treasury", "balance": "0", "required": 2, "key_hashes": ["...", "..."]},
+//			{"type": "fleta.LockedAccount", "address": "...", "name": "founders", "balance": "500000", "unlock_height": 1000000, "key_hash": "..."},
+//			{"type": "fleta.TokenAccount", "address": "...", "name": "sandboxtoken", "balance": "0", "token_coord": {"height": 0, "index": 0}, "key_hash": "..."}
+//		],
+//		"utxos": [
+//			{"public_hash": "...", "amount": "100"}
+//		]
+//	}
+//
+// An account has the fields of the JSON form of its type, and the type is given by the registered name instead of the number.
+// The UTXOs get the ids of the outputs at the genesis height 0 in the order of the file, whatever the coordinate of the chain is.
+// The policy is optional and its missing fields are the ones of the default policy of the chain package.
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/json_util"
+	"github.com/fletaio/extension/token_tx"
+)
+
+// MaxUTXOCount is the maximum number of the UTXOs because their ids are the outputs of the genesis
+const MaxUTXOCount = 65536
+
+type jsonAccount interface {
+	account.Account
+	json.Marshaler
+	json.Unmarshaler
+}
+
+// UTXO is the output that is created by the genesis
+type UTXO struct {
+	ID uint64
+	*transaction.TxOut
+}
+
+// Genesis is the accounts and the UTXOs that are created by the genesis of the chain
+type Genesis struct {
+	ChainCoord *common.Coordinate
+	Policy     *chain.Policy // nil when the file has no policy
+	Accounts   []account.Account
+	UTXOs      []*UTXO
+}
+
+type jsonPolicy struct {
+	DustAmount           json.RawMessage `json:"dust_amount"`
+	NameLength           *jsonRange      `json:"name_length"`
+	MultiSigKeyHashCount *jsonRange      `json:"multisig_key_hash_count"`
+	MaxTagLength         *int            `json:"max_tag_length"`
+	ReservedNames        []string        `json:"reserved_names"`
+}
+
+type jsonRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Load returns the genesis of the file
+func Load(act *data.Accounter, path string) (*Genesis, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(act, bs)
+}
+
+// Parse returns the genesis of the JSON form
+// The accounts are made by the types of the accounter and the chain coordinate of the file should be the one of the accounter
+func Parse(act *data.Accounter, bs []byte) (*Genesis, error) {
+	var v struct {
+		ChainCoord *json_util.Coordinate        `json:"chain_coord"`
+		Policy     *jsonPolicy                  `json:"policy"`
+		Accounts   []map[string]json.RawMessage `json:"accounts"`
+		UTXOs      []struct {
+			PublicHash string          `json:"public_hash"`
+			Amount     json.RawMessage `json:"amount"`
+		} `json:"utxos"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&v); err != nil {
+		return nil, errs.WrapField(ErrInvalidGenesis, "file", nil, err.Error())
+	}
+	if v.ChainCoord == nil {
+		return nil, errs.WrapField(ErrInvalidGenesis, "chain_coord", act.ChainCoord(), nil)
+	}
+	coord := common.NewCoordinate(v.ChainCoord.Height, v.ChainCoord.Index)
+	if !coord.Equal(act.ChainCoord()) {
+		return nil, errs.WrapField(ErrInvalidGenesis, "chain_coord", act.ChainCoord(), coord)
+	}
+	if len(v.UTXOs) > MaxUTXOCount {
+		return nil, errs.WrapField(ErrInvalidGenesis, "utxos", MaxUTXOCount, len(v.UTXOs))
+	}
+
+	g := &Genesis{
+		ChainCoord: coord,
+		Accounts:   make([]account.Account, 0, len(v.Accounts)),
+		UTXOs:      make([]*UTXO, 0, len(v.UTXOs)),
+	}
+	if v.Policy != nil {
+		policy, err := parsePolicy(v.Policy)
+		if err != nil {
+			return nil, err
+		}
+		g.Policy = policy
+	}
+	for i, fields := range v.Accounts {
+		acc, err := parseAccount(act, fmt.Sprintf("accounts[%d]", i), fields)
+		if err != nil {
+			return nil, err
+		}
+		g.Accounts = append(g.Accounts, acc)
+	}
+	for i, u := range v.UTXOs {
+		pubhash, err := common.ParsePublicHash(u.PublicHash)
+		if err != nil {
+			return nil, errs.WrapField(ErrInvalidGenesis, fmt.Sprintf("utxos[%d].public_hash", i), nil, u.PublicHash)
+		}
+		am, err := json_util.ParseAmount(u.Amount)
+		if err != nil {
+			return nil, errs.WrapField(ErrInvalidGenesis, fmt.Sprintf("utxos[%d].amount", i), nil, string(u.Amount))
+		}
+		g.UTXOs = append(g.UTXOs, &UTXO{
+			ID: transaction.MarshalID(0, 0, uint16(i)),
+			TxOut: &transaction.TxOut{
+				Amount:     am,
+				PublicHash: pubhash,
+			},
+		})
+	}
+	return g, nil
+}
+
+func parsePolicy(v *jsonPolicy) (*chain.Policy, error) {
+	policy := chain.DefaultPolicy()
+	if v.DustAmount != nil {
+		am, err := json_util.ParseAmount(v.DustAmount)
+		if err != nil {
+			return nil, errs.WrapField(ErrInvalidGenesis, "policy.dust_amount", nil, string(v.DustAmount))
+		}
+		policy.DustAmount = am
+	}
+	if v.NameLength != nil {
+		policy.NameLength = chain.Range{Min: v.NameLength.Min, Max: v.NameLength.Max}
+	}
+	if v.MultiSigKeyHashCount != nil {
+		policy.MultiSigKeyHashCount = chain.Range{Min: v.MultiSigKeyHashCount.Min, Max: v.MultiSigKeyHashCount.Max}
+	}
+	if v.MaxTagLength != nil {
+		policy.MaxTagLength = *v.MaxTagLength
+	}
+	policy.ReservedNames = v.ReservedNames
+	return policy, nil
+}
+
+func parseAccount(act *data.Accounter, field string, fields map[string]json.RawMessage) (account.Account, error) {
+	var name string
+	if err := json.Unmarshal(fields["type"], &name); err != nil || len(name) == 0 {
+		return nil, errs.WrapField(ErrInvalidGenesis, field+".type", "account name", string(fields["type"]))
+	}
+	a, err := act.NewByTypeName(name)
+	if err != nil {
+		return nil, errs.WrapField(ErrUnsupportedGenesisAccount, field+".type", nil, name)
+	}
+	acc, is := a.(jsonAccount)
+	if !is {
+		return nil, errs.WrapField(ErrUnsupportedGenesisAccount, field+".type", nil, name)
+	}
+	bs, err := acc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(bs, &obj); err != nil {
+		return nil, err
+	}
+	for k, v := range fields {
+		if k == "type" {
+			continue
+		}
+		if _, has := obj[k]; !has {
+			return nil, errs.WrapField(ErrInvalidGenesis, field+"."+k, nil, string(v))
+		}
+		obj[k] = v
+	}
+	bs, err = json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	if err := acc.UnmarshalJSON(bs); err != nil {
+		return nil, errs.WrapField(ErrInvalidGenesis, field, nil, err.Error())
+	}
+	return acc, nil
+}
+
+// PolicyOf returns the policy of the genesis or the registered policy of the chain when the file has no policy
+func (g *Genesis) PolicyOf() *chain.Policy {
+	if g.Policy != nil {
+		return g.Policy
+	}
+	return chain.PolicyOf(g.ChainCoord)
+}
+
+// Validate returns an error when the genesis breaks the policy of the chain
+func (g *Genesis) Validate() error {
+	if g.Policy != nil && !g.Policy.IsValid() {
+		return errs.WrapField(ErrInvalidGenesis, "policy", "valid policy", g.Policy)
+	}
+	policy := g.PolicyOf()
+
+	addrMap := map[common.Address]bool{}
+	nameMap := map[string]bool{}
+	for i, acc := range g.Accounts {
+		field := fmt.Sprintf("accounts[%d]", i)
+		if addrMap[acc.Address()] {
+			return errs.WrapField(ErrExistAddress, field+".address", nil, acc.Address())
+		}
+		addrMap[acc.Address()] = true
+		if !policy.IsValidNameLength(acc.Name()) || !account_name.IsCanonical(acc.Name()) {
+			return errs.WrapField(ErrInvalidAccountName, field+".name", policy.NameLength, acc.Name())
+		}
+		if nameMap[acc.Name()] {
+			return errs.WrapField(ErrExistAccountName, field+".name", nil, acc.Name())
+		}
+		nameMap[acc.Name()] = true
+		if acc.Balance().IsMinus() {
+			return errs.WrapField(ErrInvalidGenesis, field+".balance", ">= 0", acc.Balance())
+		}
+
+		switch acc := acc.(type) {
+		case *account_def.SingleAccount:
+			if acc.KeyHash == (common.PublicHash{}) {
+				return errs.WrapField(ErrInvalidGenesis, field+".key_hash", "public hash", nil)
+			}
+		case *account_def.LockedAccount:
+			if acc.KeyHash == (common.PublicHash{}) {
+				return errs.WrapField(ErrInvalidGenesis, field+".key_hash", "public hash", nil)
+			}
+		case *token_tx.TokenAccount:
+			if acc.KeyHash == (common.PublicHash{}) {
+				return errs.WrapField(ErrInvalidGenesis, field+".key_hash", "public hash", nil)
+			}
+		case *account_def.MultiSigAccount:
+			if !policy.MultiSigKeyHashCount.Contains(len(acc.KeyHashes)) {
+				return errs.WrapField(ErrInvalidMultiSigKeyHashCount, field+".key_hashes", policy.MultiSigKeyHashCount, len(acc.KeyHashes))
+			}
+			keyHashMap := map[common.PublicHash]bool{}
+			for _, v := range acc.KeyHashes {
+				keyHashMap[v] = true
+			}
+			if len(keyHashMap) != len(acc.KeyHashes) {
+				return errs.WrapField(ErrInvalidMultiSigKeyHashCount, field+".key_hashes", len(acc.KeyHashes), len(keyHashMap))
+			}
+			if acc.Required < 1 || int(acc.Required) > len(acc.KeyHashes) {
+				return errs.WrapField(ErrInvalidGenesis, field+".required", fmt.Sprintf("1 to %d", len(acc.KeyHashes)), acc.Required)
+			}
+		}
+	}
+
+	for i, u := range g.UTXOs {
+		field := fmt.Sprintf("utxos[%d]", i)
+		if u.PublicHash == (common.PublicHash{}) {
+			return errs.WrapField(ErrInvalidGenesis, field+".public_hash", "public hash", nil)
+		}
+		if policy.IsDust(u.Amount) {
+			return errs.WrapField(ErrDustAmount, field+".amount", policy.DustAmount, u.Amount)
+		}
+	}
+	return nil
+}
+
+// ContextData returns the context data that creates the accounts and the UTXOs of the genesis
+func (g *Genesis) ContextData(act *data.Accounter, tran *data.Transactor, evt *data.Eventer) (*data.ContextData, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	loader := data.NewEmptyLoader(act.ChainCoord(), act, tran, evt)
+	ctd := data.NewContextData(loader, nil)
+	for _, acc := range g.Accounts {
+		ctd.CreatedAccountMap[acc.Address()] = acc.Clone()
+	}
+	for _, u := range g.UTXOs {
+		ctd.CreatedUTXOMap[u.ID] = u.TxOut.Clone()
+	}
+	return ctd, nil
+}
+
+// Hash returns the hash of the genesis that does not depend on the order of the accounts in the file
+func (g *Genesis) Hash() hash.Hash256 {
+	return hash.DoubleHashByWriterTo(g)
+}
+
+// WriteTo is a serialization function
+// The accounts are written in the order of their addresses
+// The policy is written after the UTXOs only when the file has one, so the hash of a file without a policy is not changed
+func (g *Genesis) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := g.ChainCoord.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+
+	accs := make([]account.Account, len(g.Accounts))
+	copy(accs, g.Accounts)
+	sort.Slice(accs, func(i, j int) bool {
+		a, b := accs[i].Address(), accs[j].Address()
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	if n, err := util.WriteUint32(w, uint32(len(accs))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, acc := range accs {
+		if n, err := util.WriteUint8(w, uint8(acc.Type())); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := acc.WriteTo(w); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+
+	if n, err := util.WriteUint32(w, uint32(len(g.UTXOs))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, u := range g.UTXOs {
+		if n, err := util.WriteUint64(w, u.ID); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := u.TxOut.WriteTo(w); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+
+	if g.Policy != nil {
+		if n, err := writePolicy(w, g.Policy); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	return wrote, nil
+}
+
+func writePolicy(w io.Writer, p *chain.Policy) (int64, error) {
+	var wrote int64
+	if n, err := p.DustAmount.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, v := range []int{p.NameLength.Min, p.NameLength.Max, p.MultiSigKeyHashCount.Min, p.MultiSigKeyHashCount.Max, p.MaxTagLength} {
+		if n, err := util.WriteUint32(w, uint32(v)); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	if n, err := util.WriteUint32(w, uint32(len(p.ReservedNames))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, name := range p.ReservedNames {
+		if n, err := util.WriteString(w, name); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	return wrote, nil
+}
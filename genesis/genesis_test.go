@@ -0,0 +1,271 @@
+package genesis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/token_tx"
+)
+
+func testPublicHash(n byte) common.PublicHash {
+	var pubhash common.PublicHash
+	pubhash[0] = n
+	return pubhash
+}
+
+func testAddress(n uint16) common.Address {
+	return common.NewAddress(common.NewCoordinate(0, n), 0)
+}
+
+func testAccounts() []string {
+	return []string{
+		fmt.Sprintf(`{"type": "fleta.SingleAccount", "address": "%s", "name": "foundation", "balance": "1000", "key_hash": "%s"}`, testAddress(1), testPublicHash(1)),
+		fmt.Sprintf(`{"type": "fleta.MultiSigAccount", "address": "%s", "name": "treasury", "balance": "0", "required": 2, "key_hashes": ["%s", "%s"]}`, testAddress(2), testPublicHash(2), testPublicHash(3)),
+		fmt.Sprintf(`{"type": "fleta.LockedAccount", "address": "%s", "name": "founders", "balance": "500", "unlock_height": 1000, "key_hash": "%s"}`, testAddress(3), testPublicHash(4)),
+		fmt.Sprintf(`{"type": "fleta.TokenAccount", "address": "%s", "name": "sandboxtoken", "token_coord": {"height": 10, "index": 1}, "key_hash": "%s"}`, testAddress(4), testPublicHash(5)),
+	}
+}
+
+func testFile(accounts []string, utxos ...string) []byte {
+	return []byte(`{"chain_coord": {"height": 0, "index": 0}, "accounts": [` + strings.Join(accounts, ",") + `], "utxos": [` + strings.Join(utxos, ",") + `]}`)
+}
+
+func testUTXO(n byte, am string) string {
+	return fmt.Sprintf(`{"public_hash": "%s", "amount": "%s"}`, testPublicHash(n), am)
+}
+
+func testAccounter(t *testing.T) *data.Accounter {
+	act, err := NewAccounter(common.NewCoordinate(0, 0), DefaultAccountTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return act
+}
+
+func TestParse(t *testing.T) {
+	act := testAccounter(t)
+	g, err := Parse(act, testFile(testAccounts(), testUTXO(6, "10"), testUTXO(7, "20")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Accounts) != 4 || len(g.UTXOs) != 2 {
+		t.Fatalf("unexpected genesis %d accounts and %d utxos", len(g.Accounts), len(g.UTXOs))
+	}
+	if acc := g.Accounts[0].(*account_def.SingleAccount); acc.Name() != "foundation" || !acc.Balance().Equal(amount.NewCoinAmount(1000, 0)) || acc.KeyHash != testPublicHash(1) {
+		t.Errorf("unexpected single account %v %v", acc.Name(), acc.Balance())
+	}
+	if acc := g.Accounts[1].(*account_def.MultiSigAccount); acc.Required != 2 || len(acc.KeyHashes) != 2 {
+		t.Errorf("unexpected multisig account %v %v", acc.Required, acc.KeyHashes)
+	}
+	if acc := g.Accounts[2].(*account_def.LockedAccount); acc.UnlockHeight != 1000 {
+		t.Errorf("unexpected locked account %v", acc.UnlockHeight)
+	}
+	if acc := g.Accounts[3].(*token_tx.TokenAccount); acc.TokenCoord.Height != 10 || acc.TokenCoord.Index != 1 || !acc.Balance().IsZero() {
+		t.Errorf("unexpected token account %v %v", acc.TokenCoord, acc.Balance())
+	}
+	if g.UTXOs[1].ID != transaction.MarshalID(0, 0, 1) || !g.UTXOs[1].Amount.Equal(amount.NewCoinAmount(20, 0)) {
+		t.Errorf("unexpected utxo %d %v", g.UTXOs[1].ID, g.UTXOs[1].Amount)
+	}
+
+	ctd, err := g.ContextData(act, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ctd.CreatedAccountMap) != 4 || len(ctd.CreatedUTXOMap) != 2 {
+		t.Errorf("unexpected context data %d accounts and %d utxos", len(ctd.CreatedAccountMap), len(ctd.CreatedUTXOMap))
+	}
+}
+
+func TestUTXOID(t *testing.T) {
+	coord := common.NewCoordinate(3, 1)
+	act, err := NewAccounter(coord, DefaultAccountTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the ids are at the genesis height whatever the height of the chain coordinate is
+	g, err := Parse(act, []byte(`{"chain_coord": {"height": 3, "index": 1}, "utxos": [`+testUTXO(6, "10")+`,`+testUTXO(7, "20")+`]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, u := range g.UTXOs {
+		if u.ID != transaction.MarshalID(0, 0, uint16(i)) {
+			t.Errorf("unexpected id %d of the utxo %d", u.ID, i)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	act := testAccounter(t)
+	tests := []struct {
+		name  string
+		file  []byte
+		code  errs.Code
+		field string
+	}{
+		{"unknown file field", []byte(`{"chain_coord": {"height": 0, "index": 0}, "extra": 1}`), CodeInvalidGenesis, "file"},
+		{"no chain coord", []byte(`{"accounts": []}`), CodeInvalidGenesis, "chain_coord"},
+		{"other chain", []byte(`{"chain_coord": {"height": 3, "index": 1}}`), CodeInvalidGenesis, "chain_coord"},
+		{"no type", testFile([]string{`{"name": "foundation"}`}), CodeInvalidGenesis, "accounts[0].type"},
+		{"unknown type", testFile([]string{`{"type": "fleta.UnknownAccount"}`}), CodeUnsupportedGenesisAccount, "accounts[0].type"},
+		{"unknown account field", testFile([]string{`{"type": "fleta.SingleAccount", "unlock_height": 10}`}), CodeInvalidGenesis, "accounts[0].unlock_height"},
+		{"invalid key hash", testFile([]string{`{"type": "fleta.SingleAccount", "key_hash": "zz"}`}), CodeInvalidGenesis, "accounts[0]"},
+		{"invalid utxo amount", testFile(nil, `{"public_hash": "`+testPublicHash(1).String()+`", "amount": "x"}`), CodeInvalidGenesis, "utxos[0].amount"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(act, tt.file)
+		testContextError(t, tt.name, err, tt.code, tt.field)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	act := testAccounter(t)
+	tests := []struct {
+		name   string
+		modify func(accs []string) []string
+		utxo   string
+		code   errs.Code
+		field  string
+	}{
+		{"exist address", func(accs []string) []string {
+			return append(accs, fmt.Sprintf(`{"type": "fleta.SingleAccount", "address": "%s", "name": "otheraccount", "key_hash": "%s"}`, testAddress(1), testPublicHash(1)))
+		}, "", errs.CodeExistAddress, "accounts[4].address"},
+		{"exist name", func(accs []string) []string {
+			return append(accs, fmt.Sprintf(`{"type": "fleta.SingleAccount", "address": "%s", "name": "foundation", "key_hash": "%s"}`, testAddress(5), testPublicHash(1)))
+		}, "", errs.CodeExistAccountName, "accounts[4].name"},
+		{"invalid name", func(accs []string) []string {
+			return append(accs, fmt.Sprintf(`{"type": "fleta.SingleAccount", "address": "%s", "name": "Foundation2", "key_hash": "%s"}`, testAddress(5), testPublicHash(1)))
+		}, "", errs.CodeInvalidAccountName, "accounts[4].name"},
+		{"no key hash", func(accs []string) []string {
+			return append(accs, fmt.Sprintf(`{"type": "fleta.LockedAccount", "address": "%s", "name": "lockedaccount"}`, testAddress(5)))
+		}, "", CodeInvalidGenesis, "accounts[4].key_hash"},
+		{"multisig required", func(accs []string) []string {
+			accs[1] = strings.Replace(accs[1], `"required": 2`, `"required": 3`, 1)
+			return accs
+		}, "", CodeInvalidGenesis, "accounts[1].required"},
+		{"multisig key hashes", func(accs []string) []string {
+			accs[1] = strings.Replace(accs[1], testPublicHash(3).String(), testPublicHash(2).String(), 1)
+			return accs
+		}, "", errs.CodeInvalidMultiSigKeyHashCount, "accounts[1].key_hashes"},
+		{"dust utxo", func(accs []string) []string { return accs }, testUTXO(6, "0.01"), errs.CodeDustAmount, "utxos[0].amount"},
+	}
+	for _, tt := range tests {
+		var utxos []string
+		if len(tt.utxo) > 0 {
+			utxos = append(utxos, tt.utxo)
+		}
+		g, err := Parse(act, testFile(tt.modify(testAccounts()), utxos...))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		testContextError(t, tt.name, g.Validate(), tt.code, tt.field)
+		if _, err := g.ContextData(act, nil, nil); err == nil {
+			t.Errorf("%s: expected an error of the context data", tt.name)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	act := testAccounter(t)
+	accs := testAccounts()
+	g, err := Parse(act, testFile(accs, testUTXO(6, "10")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reversed := []string{accs[3], accs[2], accs[1], accs[0]}
+	g2, err := Parse(act, testFile(reversed, testUTXO(6, "10")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Hash() != g2.Hash() {
+		t.Error("the hash depends on the order of the accounts")
+	}
+	g3, err := Parse(act, testFile(accs, testUTXO(6, "11")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Hash() == g3.Hash() {
+		t.Error("the hash does not depend on the utxos")
+	}
+}
+
+func testContextError(t *testing.T, name string, err error, code errs.Code, field string) {
+	if errs.CodeOf(err) != code {
+		t.Errorf("%s: expected the code %d but %v", name, code, err)
+		return
+	}
+	var cerr *errs.ContextError
+	if !errors.As(err, &cerr) || cerr.Field != field {
+		t.Errorf("%s: expected the field %s but %v", name, field, err)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	act := testAccounter(t)
+	accs := []string{fmt.Sprintf(`{"type": "fleta.SingleAccount", "address": "%s", "name": "fleta", "balance": "1000", "key_hash": "%s"}`, testAddress(1), testPublicHash(1))}
+	file := func(policy string) []byte {
+		return []byte(`{"chain_coord": {"height": 0, "index": 0}, "policy": ` + policy + `, "accounts": [` + strings.Join(accs, ",") + `], "utxos": [` + testUTXO(6, "0.05") + `]}`)
+	}
+
+	g, err := Parse(act, file(`{"dust_amount": "0.01", "name_length": {"min": 4, "max": 16}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Policy == nil || !g.Policy.DustAmount.Equal(amount.COIN.DivC(100)) || g.Policy.NameLength.Min != 4 || g.Policy.MaxTagLength != chain.DefaultPolicy().MaxTagLength {
+		t.Fatalf("unexpected policy %+v", g.Policy)
+	}
+	// the short name and the small UTXO are valid by the policy of the file
+	if err := g.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	g.Policy = nil
+	if err := g.Validate(); errs.CodeOf(err) != errs.CodeInvalidAccountName {
+		t.Fatalf("expected the invalid account name by the default policy but %v", err)
+	}
+
+	g, err = Parse(act, file(`{"name_length": {"min": 4, "max": 16}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2, err := Parse(act, file(`{"name_length": {"min": 5, "max": 16}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Hash() == g2.Hash() {
+		t.Error("the hash does not depend on the policy")
+	}
+
+	g, err = Parse(act, file(`{"multisig_key_hash_count": {"min": 1, "max": 10}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testContextError(t, "invalid policy", g.Validate(), CodeInvalidGenesis, "policy")
+	g, err = Parse(act, file(`{"reserved_names": ["fletafoundation"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Policy.IsReservedName("fletafoundation") || g.Hash() == g2.Hash() {
+		t.Fatalf("unexpected reserved names %v", g.Policy.ReservedNames)
+	}
+	g, err = Parse(act, file(`{"reserved_names": ["Fleta Foundation"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testContextError(t, "invalid reserved name", g.Validate(), CodeInvalidGenesis, "policy")
+	_, err = Parse(act, file(`{"dust_amount": "x"}`))
+	testContextError(t, "invalid dust amount", err, CodeInvalidGenesis, "policy.dust_amount")
+	_, err = Parse(act, file(`{"unknown": 1}`))
+	testContextError(t, "unknown policy field", err, CodeInvalidGenesis, "file")
+}
@@ -0,0 +1,110 @@
+// Command genesistool validates the genesis file of a chain before the launch.
+//
+// The file is loaded by the account types of the chain given by -chain and checked against the policy of the file or the default policy:
+//
+//	genesistool -in genesis.json -chain 0,0
+//
+// It prints the genesis hash, the number of the accounts and the UTXOs and the total supply of the genesis,
+// and exits with the field and the reason of the first violation when the file is invalid.
+// The account types are the default types of the FLETA chains and can be overridden by a JSON file of the name and type pairs given by -types.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/genesis"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("genesistool: ")
+
+	types := flag.String("types", "", "JSON file of the account names and types")
+	in := flag.String("in", "", "genesis file")
+	chain := flag.String("chain", "0,0", "height,index of the chain coordinate")
+	flag.Parse()
+
+	if err := validate(*types, *in, *chain); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func validate(typesPath string, path string, chain string) error {
+	if len(path) == 0 {
+		return errors.New("-in is required")
+	}
+	coord, err := parseCoordinate(chain)
+	if err != nil {
+		return err
+	}
+	types := genesis.DefaultAccountTypes()
+	if len(typesPath) > 0 {
+		bs, err := ioutil.ReadFile(typesPath)
+		if err != nil {
+			return err
+		}
+		m := map[string]account.Type{}
+		if err := json.Unmarshal(bs, &m); err != nil {
+			return err
+		}
+		for name, t := range m {
+			types[name] = t
+		}
+	}
+	act, err := genesis.NewAccounter(coord, types)
+	if err != nil {
+		return err
+	}
+	g, err := genesis.Load(act, path)
+	if err != nil {
+		return err
+	}
+	if err := g.Validate(); err != nil {
+		return err
+	}
+
+	supply := amount.NewCoinAmount(0, 0)
+	for _, acc := range g.Accounts {
+		supply = supply.Add(acc.Balance())
+	}
+	for _, u := range g.UTXOs {
+		supply = supply.Add(u.Amount)
+	}
+	bs, err := json.MarshalIndent(map[string]interface{}{
+		"hash":     g.Hash(),
+		"accounts": len(g.Accounts),
+		"utxos":    len(g.UTXOs),
+		"supply":   supply,
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bs))
+	return nil
+}
+
+func parseCoordinate(str string) (*common.Coordinate, error) {
+	ls := strings.Split(str, ",")
+	if len(ls) != 2 {
+		return nil, errors.New("the chain should be height,index")
+	}
+	height, err := strconv.ParseUint(strings.TrimSpace(ls[0]), 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	index, err := strconv.ParseUint(strings.TrimSpace(ls[1]), 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	return common.NewCoordinate(uint32(height), uint16(index)), nil
+}
@@ -0,0 +1,24 @@
+package genesis
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/type_registry"
+)
+
+// DefaultAccountTypes are the account types of the extension accounts that can be declared in the genesis file
+func DefaultAccountTypes() map[string]account.Type {
+	return type_registry.Default().Accounts
+}
+
+// NewAccounter returns an accounter that has the types
+func NewAccounter(coord *common.Coordinate, types map[string]account.Type) (*data.Accounter, error) {
+	act := data.NewAccounter(coord)
+	for name, t := range types {
+		if err := act.RegisterType(name, t); err != nil {
+			return nil, err
+		}
+	}
+	return act, nil
+}
@@ -0,0 +1,169 @@
+package test_util
+
+import (
+	"errors"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+)
+
+// test loader errors
+var (
+	ErrNotExistAccount     = errors.New("not exist account")
+	ErrNotExistAccountName = errors.New("not exist account name")
+	ErrNotExistUTXO        = errors.New("not exist utxo")
+)
+
+// Loader is a data.Loader of the state that is set by the test
+// The account names are found by the names of the accounts
+type Loader struct {
+	Coord    *common.Coordinate
+	Act      *data.Accounter
+	Tran     *data.Transactor
+	Evt      *data.Eventer
+	Height   uint32 // the target height
+	Seqs     map[common.Address]uint64
+	Accounts map[common.Address]account.Account
+	Data     map[string][]byte
+	UTXOs    map[uint64]*transaction.UTXO
+}
+
+// NewLoader returns an empty Loader of the chain that has no type
+func NewLoader(coord *common.Coordinate) *Loader {
+	return &Loader{
+		Coord:    coord,
+		Act:      data.NewAccounter(coord),
+		Tran:     data.NewTransactor(coord),
+		Evt:      data.NewEventer(coord),
+		Height:   1,
+		Seqs:     map[common.Address]uint64{},
+		Accounts: map[common.Address]account.Account{},
+		Data:     map[string][]byte{},
+		UTXOs:    map[uint64]*transaction.UTXO{},
+	}
+}
+
+// ChainCoord returns the coordinate of the chain
+func (loader *Loader) ChainCoord() *common.Coordinate { return loader.Coord }
+
+// Accounter returns the accounter of the chain
+func (loader *Loader) Accounter() *data.Accounter { return loader.Act }
+
+// Transactor returns the transactor of the chain
+func (loader *Loader) Transactor() *data.Transactor { return loader.Tran }
+
+// Eventer returns the eventer of the chain
+func (loader *Loader) Eventer() *data.Eventer { return loader.Evt }
+
+// TargetHeight returns the height of the block that is made
+func (loader *Loader) TargetHeight() uint32 { return loader.Height }
+
+// Seq returns the sequence of the address
+func (loader *Loader) Seq(addr common.Address) uint64 { return loader.Seqs[addr] }
+
+// Account returns the account of the address
+func (loader *Loader) Account(addr common.Address) (account.Account, error) {
+	acc, has := loader.Accounts[addr]
+	if !has {
+		return nil, ErrNotExistAccount
+	}
+	return acc, nil
+}
+
+// IsExistAccount returns true when the account of the address exists
+func (loader *Loader) IsExistAccount(addr common.Address) (bool, error) {
+	_, has := loader.Accounts[addr]
+	return has, nil
+}
+
+// AddressByName returns the address of the account that has the name
+func (loader *Loader) AddressByName(Name string) (common.Address, error) {
+	for addr, acc := range loader.Accounts {
+		if acc.Name() == Name {
+			return addr, nil
+		}
+	}
+	return common.Address{}, ErrNotExistAccountName
+}
+
+// IsExistAccountName returns true when an account has the name
+func (loader *Loader) IsExistAccountName(Name string) (bool, error) {
+	_, err := loader.AddressByName(Name)
+	return err == nil, nil
+}
+
+// AccountData returns the data of the name of the address
+func (loader *Loader) AccountData(addr common.Address, name []byte) []byte {
+	return loader.Data[dataKey(addr, name)]
+}
+
+// SetAccountData sets the data of the name of the address
+func (loader *Loader) SetAccountData(addr common.Address, name []byte, value []byte) {
+	if len(value) == 0 {
+		delete(loader.Data, dataKey(addr, name))
+	} else {
+		loader.Data[dataKey(addr, name)] = value
+	}
+}
+
+// IsExistUTXO returns true when the UTXO of the id exists
+func (loader *Loader) IsExistUTXO(id uint64) (bool, error) {
+	_, has := loader.UTXOs[id]
+	return has, nil
+}
+
+// UTXO returns the UTXO of the id
+func (loader *Loader) UTXO(id uint64) (*transaction.UTXO, error) {
+	utxo, has := loader.UTXOs[id]
+	if !has {
+		return nil, ErrNotExistUTXO
+	}
+	return utxo, nil
+}
+
+// AddAccount sets the account with the sequence
+func (loader *Loader) AddAccount(acc account.Account, seq uint64) {
+	loader.Accounts[acc.Address()] = acc
+	loader.Seqs[acc.Address()] = seq
+}
+
+// AddUTXO sets the unspent output of the id
+func (loader *Loader) AddUTXO(id uint64, vout *transaction.TxOut) {
+	loader.UTXOs[id] = &transaction.UTXO{
+		TxIn:  transaction.NewTxIn(id),
+		TxOut: vout,
+	}
+}
+
+func dataKey(addr common.Address, name []byte) string {
+	return string(addr[:]) + string(name)
+}
+
+// Provider is a kernel.Provider of the blocks from the height 1
+type Provider struct {
+	Blocks []*block.Block
+}
+
+// Height returns the height of the last block
+func (p *Provider) Height() uint32 { return uint32(len(p.Blocks)) }
+
+// Hash returns the hash of the block of the height
+func (p *Provider) Hash(height uint32) (hash.Hash256, error) {
+	b, err := p.Block(height)
+	if err != nil {
+		return hash.Hash256{}, err
+	}
+	return b.Header.Hash(), nil
+}
+
+// Block returns the block of the height
+func (p *Provider) Block(height uint32) (*block.Block, error) {
+	if height == 0 || int(height) > len(p.Blocks) {
+		return nil, errors.New("not exist block")
+	}
+	return p.Blocks[height-1], nil
+}
@@ -0,0 +1,149 @@
+// Package test_util holds the helpers that are shared by the tests of the extension packages.
+//
+// It only depends on the core packages, so the tests of every extension package can import it without an import cycle.
+// The packages that embed their own transaction base compose it from TxBase.
+package test_util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/transaction"
+)
+
+// Timestamp is the timestamp of the transactions of the tests
+const Timestamp = 1546300800000000000
+
+// PublicHash returns the public hash that is filled from the byte
+func PublicHash(b byte) common.PublicHash {
+	var pubhash common.PublicHash
+	for i := range pubhash {
+		pubhash[i] = b + byte(i)
+	}
+	return pubhash
+}
+
+// Address returns the address of the n-th account of the main chain
+func Address(n uint16) common.Address {
+	return common.NewAddress(common.NewCoordinate(0, n), 0)
+}
+
+// Seq is the sequence of the account transactions of the tests
+const Seq = 7
+
+// From returns the sender of the account transactions of the tests
+func From() common.Address {
+	return common.NewAddress(common.NewCoordinate(1, 2), 0)
+}
+
+// TxBase returns the transaction base of the type
+func TxBase(t transaction.Type) transaction.Base {
+	return transaction.Base{
+		Type_:      t,
+		Timestamp_: Timestamp,
+	}
+}
+
+// Transactor returns the transactor of the main chain that has the types with the fee 0.1
+func Transactor(tb testing.TB, types map[string]transaction.Type) *data.Transactor {
+	tran := data.NewTransactor(common.NewCoordinate(0, 0))
+	for name, t := range types {
+		if err := tran.RegisterType(name, t, amount.COIN.DivC(10)); err != nil {
+			tb.Fatal(name, err)
+		}
+	}
+	return tran
+}
+
+// Accounter returns the accounter of the main chain that has the types
+func Accounter(tb testing.TB, types map[string]account.Type) *data.Accounter {
+	act := data.NewAccounter(common.NewCoordinate(0, 0))
+	for name, t := range types {
+		if err := act.RegisterType(name, t); err != nil {
+			tb.Fatal(name, err)
+		}
+	}
+	return act
+}
+
+// Eventer returns the eventer of the main chain that has the types
+func Eventer(tb testing.TB, types map[string]event.Type) *data.Eventer {
+	evt := data.NewEventer(common.NewCoordinate(0, 0))
+	for name, t := range types {
+		if err := evt.RegisterType(name, t); err != nil {
+			tb.Fatal(name, err)
+		}
+	}
+	return evt
+}
+
+// BinaryRoundTrip writes the source, reads it to the destination and checks that the destination writes the same bytes
+// It returns the written bytes
+func BinaryRoundTrip(tb testing.TB, src io.WriterTo, dst io.ReaderFrom) []byte {
+	var buffer bytes.Buffer
+	wrote, err := src.WriteTo(&buffer)
+	if err != nil {
+		tb.Fatalf("%T: %v", src, err)
+	}
+	if wrote != int64(buffer.Len()) {
+		tb.Errorf("%T: wrote %d bytes but reported %d", src, buffer.Len(), wrote)
+	}
+	bs := buffer.Bytes()
+	read, err := dst.ReadFrom(bytes.NewReader(bs))
+	if err != nil {
+		tb.Fatalf("%T: %v", src, err)
+	}
+	if read != wrote {
+		tb.Errorf("%T: read %d bytes but wrote %d", src, read, wrote)
+	}
+	var rebuffer bytes.Buffer
+	if _, err := dst.(io.WriterTo).WriteTo(&rebuffer); err != nil {
+		tb.Fatalf("%T: %v", src, err)
+	}
+	if !bytes.Equal(bs, rebuffer.Bytes()) {
+		tb.Errorf("%T: binary mismatch\n got %x\nwant %x", src, rebuffer.Bytes(), bs)
+	}
+	return bs
+}
+
+// JSONValue is a value that has both the binary and the JSON forms
+type JSONValue interface {
+	io.WriterTo
+	json.Marshaler
+	json.Unmarshaler
+}
+
+// JSONRoundTrip marshals the source, unmarshals it to the destination and checks that both forms of them are the same
+func JSONRoundTrip(tb testing.TB, src JSONValue, dst JSONValue) {
+	bs, err := json.Marshal(src)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := json.Unmarshal(bs, dst); err != nil {
+		tb.Fatalf("%T: %v: %s", src, err, bs)
+	}
+	rbs, err := json.Marshal(dst)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if !bytes.Equal(bs, rbs) {
+		tb.Errorf("%T: json mismatch\n got %s\nwant %s", src, rbs, bs)
+	}
+	var sw, dw bytes.Buffer
+	if _, err := src.WriteTo(&sw); err != nil {
+		tb.Fatal(err)
+	}
+	if _, err := dst.WriteTo(&dw); err != nil {
+		tb.Fatal(err)
+	}
+	if !bytes.Equal(sw.Bytes(), dw.Bytes()) {
+		tb.Errorf("%T: binary mismatch\n got %x\nwant %x", src, dw.Bytes(), sw.Bytes())
+	}
+}
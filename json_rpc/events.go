@@ -0,0 +1,253 @@
+package json_rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/message_def"
+	"github.com/fletaio/core/transaction"
+)
+
+// MaxPendingNotifications is the number of the notifications that wait to be written to a connection
+// The connection that cannot catch up with the events is closed
+const MaxPendingNotifications = 256
+
+type subscription struct {
+	id      uint64
+	client  *wsClient
+	nameMap map[string]bool // nil subscribes all events
+}
+
+type wsClient struct {
+	conn    *wsConn
+	queue   chan []byte
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+func (c *wsClient) send(bs []byte) bool {
+	select {
+	case c.queue <- bs:
+		return true
+	default:
+		c.close()
+		return false
+	}
+}
+
+func (c *wsClient) close() {
+	c.once.Do(func() {
+		close(c.closeCh)
+		c.conn.Close()
+	})
+}
+
+func (c *wsClient) run() {
+	for {
+		select {
+		case bs := <-c.queue:
+			if err := c.conn.WriteMessage(bs); err != nil {
+				c.close()
+				return
+			}
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+type hub struct {
+	sync.Mutex
+	nextID uint64
+	subMap map[uint64]*subscription
+}
+
+func newHub() *hub {
+	return &hub{
+		subMap: map[uint64]*subscription{},
+	}
+}
+
+func (h *hub) subscribe(c *wsClient, names []string) uint64 {
+	h.Lock()
+	defer h.Unlock()
+
+	h.nextID++
+	sub := &subscription{
+		id:     h.nextID,
+		client: c,
+	}
+	if len(names) > 0 {
+		sub.nameMap = map[string]bool{}
+		for _, name := range names {
+			sub.nameMap[name] = true
+		}
+	}
+	h.subMap[sub.id] = sub
+	return sub.id
+}
+
+func (h *hub) unsubscribe(c *wsClient, id uint64) bool {
+	h.Lock()
+	defer h.Unlock()
+
+	if sub, has := h.subMap[id]; has && sub.client == c {
+		delete(h.subMap, id)
+		return true
+	}
+	return false
+}
+
+func (h *hub) removeClient(c *wsClient) {
+	h.Lock()
+	defer h.Unlock()
+
+	for id, sub := range h.subMap {
+		if sub.client == c {
+			delete(h.subMap, id)
+		}
+	}
+}
+
+func (h *hub) subscriptions(name string) []*subscription {
+	h.Lock()
+	defer h.Unlock()
+
+	list := []*subscription{}
+	for _, sub := range h.subMap {
+		if sub.nameMap == nil || sub.nameMap[name] {
+			list = append(list, sub)
+		}
+	}
+	return list
+}
+
+// Publish sends the events to the subscriptions of their type names
+func (s *Server) Publish(events []event.Event) {
+	evt := s.backend.Loader().Eventer()
+	for _, e := range events {
+		name, err := evt.NameByType(e.Type())
+		if err != nil {
+			continue
+		}
+		subs := s.hub.subscriptions(name)
+		if len(subs) == 0 {
+			continue
+		}
+		bs, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		raw := json.RawMessage(bs)
+		for _, sub := range subs {
+			msg, err := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "event",
+				"params": map[string]interface{}{
+					"subscription": sub.id,
+					"type":         name,
+					"event":        &raw,
+				},
+			})
+			if err != nil {
+				continue
+			}
+			if !sub.client.send(msg) {
+				s.hub.removeClient(sub.client)
+			}
+		}
+	}
+}
+
+// serveWebSocket serves the calls of the connection and the subscribe and unsubscribe methods that are available only on it
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	checkOrigin := s.checkOrigin
+	readTimeout := s.readTimeout
+	s.Unlock()
+	if !checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	conn, err := upgrade(w, r, readTimeout)
+	if err != nil {
+		return
+	}
+	c := &wsClient{
+		conn:    conn,
+		queue:   make(chan []byte, MaxPendingNotifications),
+		closeCh: make(chan struct{}),
+	}
+	go c.run()
+	defer func() {
+		s.hub.removeClient(c)
+		c.close()
+	}()
+
+	extra := map[string]Handler{
+		"subscribe": func(params json.RawMessage) (interface{}, error) {
+			var v struct {
+				Events []string `json:"events"`
+			}
+			if len(params) > 0 {
+				if err := parseParams(params, &v); err != nil {
+					return nil, err
+				}
+			}
+			return s.hub.subscribe(c, v.Events), nil
+		},
+		"unsubscribe": func(params json.RawMessage) (interface{}, error) {
+			var v struct {
+				Subscription uint64 `json:"subscription"`
+			}
+			if err := parseParams(params, &v); err != nil {
+				return nil, err
+			}
+			return s.hub.unsubscribe(c, v.Subscription), nil
+		},
+	}
+	for {
+		msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if bs := s.handle(msg, extra); bs != nil {
+			if !c.send(bs) {
+				return
+			}
+		}
+	}
+}
+
+// AfterProcessBlock called when processed block to the chain
+func (s *Server) AfterProcessBlock(kn *kernel.Kernel, b *block.Block, ob *block.ObserverSigned, ctx *data.Context) {
+	s.Publish(ctx.Top().Events)
+}
+
+// OnProcessBlock called when processing a block to the chain (error prevent processing block)
+func (s *Server) OnProcessBlock(kn *kernel.Kernel, b *block.Block, ob *block.ObserverSigned, ctx *data.Context) error {
+	return nil
+}
+
+// OnPushTransaction called when pushing a transaction to the transaction pool (error prevent push transaction)
+func (s *Server) OnPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) error {
+	return nil
+}
+
+// AfterPushTransaction called when pushed a transaction to the transaction pool
+func (s *Server) AfterPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) {
+}
+
+// DoTransactionBroadcast called when a transaction need to be broadcast
+func (s *Server) DoTransactionBroadcast(kn *kernel.Kernel, msg *message_def.TransactionMessage) {
+}
+
+// DebugLog TEMP
+func (s *Server) DebugLog(kn *kernel.Kernel, args ...interface{}) {
+}
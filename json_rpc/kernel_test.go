@@ -0,0 +1,71 @@
+package json_rpc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/chainkit"
+)
+
+type testRewarder struct{}
+
+func (rd *testRewarder) ProcessReward(addr common.Address, ctx *data.Context) error {
+	return nil
+}
+
+func TestKernelBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "json_rpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	addr := common.NewAddress(common.NewCoordinate(0, 0), 0)
+	cfg := &chainkit.Config{
+		ChainCoord: common.NewCoordinate(0, 0),
+		Version:    1,
+		StoreRoot:  dir,
+		Rewarder:   &testRewarder{},
+		Transactions: []chainkit.TransactionType{
+			{Name: "fleta.Transfer", Type: 10, Fee: amount.COIN.DivC(10)},
+		},
+		Accounts: []chainkit.AccountType{
+			{Name: "fleta.SingleAccount", Type: 10},
+		},
+		GenesisAccounts: []chainkit.GenesisAccount{
+			{Type: "fleta.SingleAccount", Address: addr, Name: "genesisaccount", Balance: amount.NewCoinAmount(100, 0)},
+		},
+	}
+	kn, err := chainkit.NewKernel(cfg, dir, map[common.PublicHash]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kn.Close()
+	s := NewServer(kn)
+	kn.AddEventHandler(s)
+
+	res := call(t, s, `{"jsonrpc":"2.0","id":1,"method":"account","params":{"account":"`+addr.String()+`"}}`)
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+	var v struct {
+		Type    string `json:"type"`
+		Account struct {
+			Name string `json:"name"`
+		} `json:"account"`
+	}
+	if err := json.Unmarshal(res.Result, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Type != "fleta.SingleAccount" || v.Account.Name != "genesisaccount" {
+		t.Errorf("unexpected account %s", res.Result)
+	}
+	if res := call(t, s, `{"jsonrpc":"2.0","id":2,"method":"sequence","params":{"address":"`+addr.String()+`"}}`); res.Error != nil || string(res.Result) != "0" {
+		t.Errorf("unexpected sequence %s %v", res.Result, res.Error)
+	}
+}
@@ -0,0 +1,220 @@
+package json_rpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/token_tx"
+	"github.com/fletaio/extension/tx_builder"
+)
+
+// UTXO is the JSON form of an unspent output
+type UTXO struct {
+	ID         uint64            `json:"id"`
+	PublicHash common.PublicHash `json:"public_hash"`
+	Amount     *amount.Amount    `json:"amount"`
+}
+
+func (s *Server) registerMethods() {
+	s.handlerMap["account"] = s.account
+	s.handlerMap["sequence"] = s.sequence
+	s.handlerMap["utxo"] = s.utxo
+	s.handlerMap["utxos"] = s.utxos
+	s.handlerMap["token"] = s.token
+	s.handlerMap["buildTransaction"] = s.buildTransaction
+	s.handlerMap["submitTransaction"] = s.submitTransaction
+}
+
+// parseParams decodes the params object and rejects the unknown fields
+func parseParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return NewError(CodeInvalidParams, "params are required")
+	}
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return NewError(CodeInvalidParams, err.Error())
+	}
+	return nil
+}
+
+func (s *Server) loadAccount(params json.RawMessage) (account.Account, error) {
+	var v struct {
+		Account *account_name.Destination `json:"account"`
+	}
+	if err := parseParams(params, &v); err != nil {
+		return nil, err
+	}
+	if v.Account == nil {
+		return nil, NewError(CodeInvalidParams, "account is required")
+	}
+	loader := s.backend.Loader()
+	addr, err := v.Account.Resolve(loader)
+	if err != nil {
+		return nil, err
+	}
+	return loader.Account(addr)
+}
+
+// account returns the account of the address or the name with the JSON form of its type
+func (s *Server) account(params json.RawMessage) (interface{}, error) {
+	acc, err := s.loadAccount(params)
+	if err != nil {
+		return nil, err
+	}
+	name, err := s.backend.Loader().Accounter().NameByType(acc.Type())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"type":    name,
+		"account": acc,
+	}, nil
+}
+
+// sequence returns the sequence of the address that is used by the next transaction
+func (s *Server) sequence(params json.RawMessage) (interface{}, error) {
+	var v struct {
+		Address string `json:"address"`
+	}
+	if err := parseParams(params, &v); err != nil {
+		return nil, err
+	}
+	addr, err := common.ParseAddress(v.Address)
+	if err != nil {
+		return nil, NewError(CodeInvalidParams, err.Error())
+	}
+	return s.backend.Loader().Seq(addr), nil
+}
+
+// utxo returns the unspent output of the id
+func (s *Server) utxo(params json.RawMessage) (interface{}, error) {
+	var v struct {
+		ID uint64 `json:"id"`
+	}
+	if err := parseParams(params, &v); err != nil {
+		return nil, err
+	}
+	utxo, err := s.backend.Loader().UTXO(v.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &UTXO{
+		ID:         v.ID,
+		PublicHash: utxo.PublicHash,
+		Amount:     utxo.Amount,
+	}, nil
+}
+
+// utxos returns the unspent outputs of the public hash and the sum of them by the UTXO index
+func (s *Server) utxos(params json.RawMessage) (interface{}, error) {
+	var v struct {
+		PublicHash string `json:"public_hash"`
+	}
+	if err := parseParams(params, &v); err != nil {
+		return nil, err
+	}
+	pubhash, err := common.ParsePublicHash(v.PublicHash)
+	if err != nil {
+		return nil, NewError(CodeInvalidParams, err.Error())
+	}
+	s.Lock()
+	idx := s.utxoIndex
+	s.Unlock()
+	if idx == nil {
+		return nil, NewError(CodeServerError, "utxo index is not enabled")
+	}
+	list, err := idx.UTXOs(pubhash)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := idx.Balance(pubhash)
+	if err != nil {
+		return nil, err
+	}
+	utxos := make([]*UTXO, 0, len(list))
+	for _, utxo := range list {
+		utxos = append(utxos, &UTXO{
+			ID:         utxo.ID(),
+			PublicHash: utxo.PublicHash,
+			Amount:     utxo.Amount,
+		})
+	}
+	return map[string]interface{}{
+		"balance": balance,
+		"utxos":   utxos,
+	}, nil
+}
+
+// token returns the token account of the address or the name
+func (s *Server) token(params json.RawMessage) (interface{}, error) {
+	acc, err := s.loadAccount(params)
+	if err != nil {
+		return nil, err
+	}
+	tacc, is := acc.(*token_tx.TokenAccount)
+	if !is {
+		return nil, NewError(CodeInvalidParams, "not a token account")
+	}
+	return tacc, nil
+}
+
+// buildTransaction returns the unsigned transaction of the type name and the fields of its JSON form
+// The hex is the signed transaction form without signatures, so it can be signed by txtool or a wallet
+func (s *Server) buildTransaction(params json.RawMessage) (interface{}, error) {
+	var v struct {
+		Type   string                     `json:"type"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := parseParams(params, &v); err != nil {
+		return nil, err
+	}
+	tx, err := tx_builder.Build(s.backend.Loader().Transactor(), v.Type, v.Fields)
+	if err != nil {
+		return nil, err
+	}
+	signed := &tx_builder.Signed{
+		Tx:   tx,
+		Sigs: []common.Signature{},
+	}
+	bs, err := signed.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"hash": tx.Hash(),
+		"hex":  hex.EncodeToString(bs),
+		"tx":   tx,
+	}, nil
+}
+
+// submitTransaction adds the signed transaction of the hex to the transaction pool of the kernel
+func (s *Server) submitTransaction(params json.RawMessage) (interface{}, error) {
+	var v struct {
+		Hex string `json:"hex"`
+	}
+	if err := parseParams(params, &v); err != nil {
+		return nil, err
+	}
+	bs, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(v.Hex), "0x"))
+	if err != nil {
+		return nil, NewError(CodeInvalidParams, err.Error())
+	}
+	signed, err := tx_builder.DecodeSigned(s.backend.Loader().Transactor(), bs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.backend.AddTransaction(signed.Tx, signed.Sigs); err != nil {
+		return nil, err
+	}
+	return map[string]hash.Hash256{
+		"hash": signed.Tx.Hash(),
+	}, nil
+}
@@ -0,0 +1,287 @@
+// Package json_rpc serves the extension queries and the transaction submission of a kernel by JSON-RPC 2.0 over HTTP.
+//
+// A request is posted to the server as a single call or a batch of calls:
+//
+//	{"jsonrpc": "2.0", "id": 1, "method": "account", "params": {"account": "3CUsUpv9v"}}
+//
+// The calls can be sent over a WebSocket connection as text messages too, and the events of the processed blocks are pushed to the connection
+// after it subscribes them by the subscribe method. The server is added to the kernel as an event handler to receive the events.
+// The server talks to the kernel through the Backend interface, so it can be served by an in-process kernel or a test backend.
+package json_rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/tx_builder"
+	"github.com/fletaio/extension/utxo_index"
+)
+
+// MaxRequestSize is the maximum byte size of a request body or a WebSocket message
+const MaxRequestSize = 1 << 20
+
+// DefaultWebSocketReadTimeout is the default time that a WebSocket connection waits for the next frame of the client
+const DefaultWebSocketReadTimeout = time.Minute
+
+// JSON-RPC 2.0 error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeServerError    = -32000 // the data has the violation of the extension error
+)
+
+// Backend is the kernel that is served
+// *kernel.Kernel implements it
+type Backend interface {
+	ChainCoord() *common.Coordinate
+	Loader() data.Loader
+	AddTransaction(tx transaction.Transaction, sigs []common.Signature) error
+}
+
+var _ Backend = (*kernel.Kernel)(nil)
+
+// Handler returns the result of the params of a call
+type Handler func(params json.RawMessage) (interface{}, error)
+
+// Error is the error object of a response
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Error returns the message of the error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// NewError returns an error of the code
+func NewError(code int, msg string) *Error {
+	return &Error{
+		Code:    code,
+		Message: msg,
+	}
+}
+
+// toError converts the error of a handler to the error object
+// An error of the extension has its violation as the data so the client can find the failed field
+func toError(err error) *Error {
+	if e, is := err.(*Error); is {
+		return e
+	}
+	if errs.CodeOf(err) != errs.CodeUnknown {
+		return &Error{
+			Code:    CodeServerError,
+			Message: err.Error(),
+			Data:    tx_builder.NewViolation(err),
+		}
+	}
+	return NewError(CodeInternalError, err.Error())
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      json.RawMessage  `json:"id"`
+	Result  *json.RawMessage `json:"result,omitempty"`
+	Error   *Error           `json:"error,omitempty"`
+}
+
+// Server is the JSON-RPC server of a backend
+type Server struct {
+	sync.Mutex
+	backend     Backend
+	utxoIndex   *utxo_index.Index
+	handlerMap  map[string]Handler
+	hub         *hub
+	checkOrigin func(r *http.Request) bool
+	readTimeout time.Duration
+}
+
+// NewServer returns a Server of the backend that has the extension methods
+func NewServer(backend Backend) *Server {
+	s := &Server{
+		backend:     backend,
+		handlerMap:  map[string]Handler{},
+		hub:         newHub(),
+		checkOrigin: isSameOrigin,
+		readTimeout: DefaultWebSocketReadTimeout,
+	}
+	s.registerMethods()
+	return s
+}
+
+// SetUTXOIndex sets the UTXO index that is used by the utxos method
+func (s *Server) SetUTXOIndex(idx *utxo_index.Index) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.utxoIndex = idx
+}
+
+// SetCheckOrigin sets the function that accepts the Origin of a WebSocket request
+// The default accepts a request without the Origin and a request from the same host, so a page of another site cannot open a connection
+func (s *Server) SetCheckOrigin(fn func(r *http.Request) bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.checkOrigin = fn
+}
+
+// SetWebSocketReadTimeout sets the time that a WebSocket connection waits for the next frame of the client
+// The connection is closed when the client sends nothing in time, so a client that only listens to the notifications should send pings
+func (s *Server) SetWebSocketReadTimeout(d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.readTimeout = d
+}
+
+// Register adds the method or replaces the existing one
+func (s *Server) Register(method string, h Handler) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.handlerMap[method] = h
+}
+
+func (s *Server) handler(method string) (Handler, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	h, has := s.handlerMap[method]
+	return h, has
+}
+
+// ServeHTTP serves the posted calls and upgrades the WebSocket requests
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if isWebSocketRequest(r) {
+		s.serveWebSocket(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	bs := s.Handle(body)
+	if bs == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs)
+}
+
+// Handle returns the response of the request body that is a call or a batch of calls
+// It returns nil when all calls are notifications that have no response
+func (s *Server) Handle(body []byte) []byte {
+	return s.handle(body, nil)
+}
+
+// handle serves the calls with the extra methods of the connection
+func (s *Server) handle(body []byte, extra map[string]Handler) []byte {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []json.RawMessage
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			return marshalResponse(errorResponse(nil, NewError(CodeParseError, err.Error())))
+		}
+		if len(reqs) == 0 {
+			return marshalResponse(errorResponse(nil, NewError(CodeInvalidRequest, "empty batch")))
+		}
+		list := []*response{}
+		for _, raw := range reqs {
+			if res := s.call(raw, extra); res != nil {
+				list = append(list, res)
+			}
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		bs, _ := json.Marshal(list)
+		return bs
+	}
+	if res := s.call(body, extra); res != nil {
+		return marshalResponse(res)
+	}
+	return nil
+}
+
+func (s *Server) call(raw json.RawMessage, extra map[string]Handler) *response {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		if _, is := err.(*json.SyntaxError); is {
+			return errorResponse(nil, NewError(CodeParseError, err.Error()))
+		}
+		return errorResponse(nil, NewError(CodeInvalidRequest, err.Error()))
+	}
+	if req.JSONRPC != "2.0" || len(req.Method) == 0 {
+		return errorResponse(req.ID, NewError(CodeInvalidRequest, "invalid request"))
+	}
+	h, has := extra[req.Method]
+	if !has {
+		h, has = s.handler(req.Method)
+	}
+	if !has {
+		if req.ID == nil {
+			return nil
+		}
+		return errorResponse(req.ID, NewError(CodeMethodNotFound, "method not found: "+req.Method))
+	}
+	v, err := h(req.Params)
+	if req.ID == nil {
+		return nil
+	}
+	if err != nil {
+		return errorResponse(req.ID, toError(err))
+	}
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return errorResponse(req.ID, NewError(CodeInternalError, err.Error()))
+	}
+	result := json.RawMessage(bs)
+	return &response{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  &result,
+	}
+}
+
+func errorResponse(id json.RawMessage, e *Error) *response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   e,
+	}
+}
+
+func marshalResponse(res *response) []byte {
+	bs, _ := json.Marshal(res)
+	return bs
+}
@@ -0,0 +1,399 @@
+package json_rpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/internal/test_util"
+	"github.com/fletaio/extension/type_registry"
+)
+
+type testBackend struct {
+	loader *test_util.Loader
+	txs    []transaction.Transaction
+	addErr error
+}
+
+func newTestBackend(t *testing.T) *testBackend {
+	b := &testBackend{
+		loader: test_util.NewLoader(common.NewCoordinate(0, 0)),
+	}
+	if err := type_registry.Register(b.loader.Act, b.loader.Tran, b.loader.Evt); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func (b *testBackend) ChainCoord() *common.Coordinate { return b.loader.Coord }
+func (b *testBackend) Loader() data.Loader            { return b.loader }
+func (b *testBackend) AddTransaction(tx transaction.Transaction, sigs []common.Signature) error {
+	if b.addErr != nil {
+		return b.addErr
+	}
+	b.txs = append(b.txs, tx)
+	return nil
+}
+
+func (b *testBackend) addAccount(t *testing.T, n uint16) common.Address {
+	a, err := b.loader.Act.NewByTypeName("fleta.SingleAccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := a.(*account_def.SingleAccount)
+	acc.Address_ = common.NewAddress(common.NewCoordinate(0, n), 0)
+	acc.Name_ = "testaccount"
+	acc.Balance_ = amount.NewCoinAmount(10, 0)
+	b.loader.AddAccount(acc, 3)
+	return acc.Address_
+}
+
+type testResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+func call(t *testing.T, s *Server, body string) *testResponse {
+	bs := s.Handle([]byte(body))
+	if bs == nil {
+		t.Fatalf("no response of %s", body)
+	}
+	var res testResponse
+	if err := json.Unmarshal(bs, &res); err != nil {
+		t.Fatal(err)
+	}
+	return &res
+}
+
+func TestHandle(t *testing.T) {
+	b := newTestBackend(t)
+	addr := b.addAccount(t, 1)
+	s := NewServer(b)
+
+	res := call(t, s, `{"jsonrpc":"2.0","id":1,"method":"account","params":{"account":"`+addr.String()+`"}}`)
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+	var v struct {
+		Type    string `json:"type"`
+		Account struct {
+			Name string `json:"name"`
+		} `json:"account"`
+	}
+	if err := json.Unmarshal(res.Result, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Type != "fleta.SingleAccount" || v.Account.Name != "testaccount" {
+		t.Errorf("unexpected account %s", res.Result)
+	}
+
+	res = call(t, s, `{"jsonrpc":"2.0","id":"a","method":"sequence","params":{"address":"`+addr.String()+`"}}`)
+	if res.Error != nil || string(res.Result) != "3" || string(res.ID) != `"a"` {
+		t.Errorf("unexpected sequence %s %s %v", res.ID, res.Result, res.Error)
+	}
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"account"`, CodeParseError},
+		{`{"jsonrpc":"1.0","id":1,"method":"account"}`, CodeInvalidRequest},
+		{`{"jsonrpc":"2.0","id":1,"method":"unknown"}`, CodeMethodNotFound},
+		{`{"jsonrpc":"2.0","id":1,"method":"sequence","params":{"addr":"x"}}`, CodeInvalidParams},
+		{`{"jsonrpc":"2.0","id":1,"method":"utxos","params":{"public_hash":"` + common.PublicHash{}.String() + `"}}`, CodeServerError},
+		{`{"jsonrpc":"2.0","id":1,"method":"subscribe"}`, CodeMethodNotFound},
+		{`[]`, CodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		if res := call(t, s, tt.body); res.Error == nil || res.Error.Code != tt.code {
+			t.Errorf("expected the code %d of %s but %v", tt.code, tt.body, res.Error)
+		}
+	}
+
+	bs := s.Handle([]byte(`[{"jsonrpc":"2.0","method":"sequence","params":{"address":"` + addr.String() + `"}},{"jsonrpc":"2.0","id":2,"method":"unknown"}]`))
+	var list []*testResponse
+	if err := json.Unmarshal(bs, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || string(list[0].ID) != "2" {
+		t.Errorf("unexpected batch response %s", bs)
+	}
+	if bs := s.Handle([]byte(`{"jsonrpc":"2.0","method":"sequence","params":{"address":"` + addr.String() + `"}}`)); bs != nil {
+		t.Errorf("the notification should not have a response but %s", bs)
+	}
+}
+
+func TestBuildAndSubmit(t *testing.T) {
+	b := newTestBackend(t)
+	from := b.addAccount(t, 1)
+	to := common.NewAddress(common.NewCoordinate(0, 2), 0)
+	s := NewServer(b)
+
+	res := call(t, s, `{"jsonrpc":"2.0","id":1,"method":"buildTransaction","params":{"type":"fleta.Transfer","fields":{"seq":4,"from":"`+from.String()+`","to":"`+to.String()+`","amount":"1.5"}}}`)
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+	var built struct {
+		Hash string `json:"hash"`
+		Hex  string `json:"hex"`
+	}
+	if err := json.Unmarshal(res.Result, &built); err != nil {
+		t.Fatal(err)
+	}
+
+	res = call(t, s, `{"jsonrpc":"2.0","id":2,"method":"submitTransaction","params":{"hex":"`+built.Hex+`"}}`)
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+	if len(b.txs) != 1 || !strings.Contains(string(res.Result), built.Hash) || b.txs[0].Hash().String() != built.Hash {
+		t.Errorf("unexpected submission %s", res.Result)
+	}
+
+	b.addErr = errs.WrapField(errs.ErrInvalidSequence, "seq", 4, 5)
+	res = call(t, s, `{"jsonrpc":"2.0","id":3,"method":"submitTransaction","params":{"hex":"`+built.Hex+`"}}`)
+	if res.Error == nil || res.Error.Code != CodeServerError {
+		t.Fatalf("expected the server error but %v", res.Error)
+	}
+	var violation struct {
+		Code  errs.Code `json:"code"`
+		Field string    `json:"field"`
+	}
+	bs, _ := json.Marshal(res.Error.Data)
+	if err := json.Unmarshal(bs, &violation); err != nil {
+		t.Fatal(err)
+	}
+	if violation.Code != errs.CodeInvalidSequence || violation.Field != "seq" {
+		t.Errorf("unexpected violation %s", bs)
+	}
+}
+
+type testWebSocket struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+// handshake sends the opening handshake from the origin that is not sent when it is empty
+func handshake(t *testing.T, url string, origin string) (*testWebSocket, *http.Response) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	req := "GET / HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\nSec-WebSocket-Version: 13\r\n"
+	if len(origin) > 0 {
+		req += "Origin: " + origin + "\r\n"
+	}
+	if _, err := conn.Write([]byte(req + "\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testWebSocket{conn: conn, br: br}, res
+}
+
+func dialWebSocket(t *testing.T, url string) *testWebSocket {
+	ws, res := handshake(t, url, "")
+	if res.StatusCode != http.StatusSwitchingProtocols || res.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("unexpected handshake %d %v", res.StatusCode, res.Header)
+	}
+	return ws
+}
+
+func (ws *testWebSocket) write(t *testing.T, msg string) {
+	ws.writeFrame(t, 0x81, []byte(msg))
+}
+
+// writeFrame writes the masked frame of the first byte that has the FIN bit and the opcode
+func (ws *testWebSocket) writeFrame(t *testing.T, first byte, payload []byte) {
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{first, 0x80 | byte(len(payload))}
+	if len(payload) >= 126 {
+		frame = []byte{first, 0x80 | 126, 0, 0}
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	}
+	frame = append(frame, mask...)
+	for i := 0; i < len(payload); i++ {
+		frame = append(frame, payload[i]^mask[i%4])
+	}
+	if _, err := ws.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (ws *testWebSocket) read(t *testing.T) []byte {
+	_, payload := ws.readFrame(t)
+	return payload
+}
+
+// readFrame returns the opcode and the payload of the next frame
+func (ws *testWebSocket) readFrame(t *testing.T) (byte, []byte) {
+	ws.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	head := make([]byte, 2)
+	if _, err := io.ReadFull(ws.br, head); err != nil {
+		t.Fatal(err)
+	}
+	size := int(head[1] & 0x7F)
+	if size == 126 {
+		bs := make([]byte, 2)
+		if _, err := io.ReadFull(ws.br, bs); err != nil {
+			t.Fatal(err)
+		}
+		size = int(binary.BigEndian.Uint16(bs))
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(ws.br, payload); err != nil {
+		t.Fatal(err)
+	}
+	return head[0] & 0x0F, payload
+}
+
+func TestWebSocket(t *testing.T) {
+	b := newTestBackend(t)
+	addr := b.addAccount(t, 1)
+	s := NewServer(b)
+	hs := httptest.NewServer(s)
+	defer hs.Close()
+
+	ws := dialWebSocket(t, hs.URL)
+	defer ws.conn.Close()
+
+	ws.write(t, `{"jsonrpc":"2.0","id":1,"method":"sequence","params":{"address":"`+addr.String()+`"}}`)
+	var res testResponse
+	if err := json.Unmarshal(ws.read(t), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Error != nil || string(res.Result) != "3" {
+		t.Errorf("unexpected sequence %s %v", res.Result, res.Error)
+	}
+
+	ws.write(t, `{"jsonrpc":"2.0","id":2,"method":"subscribe","params":{}}`)
+	if err := json.Unmarshal(ws.read(t), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Error != nil || string(res.Result) != "1" {
+		t.Fatalf("unexpected subscription %s %v", res.Result, res.Error)
+	}
+
+	e, err := b.loader.Evt.NewByTypeName("fleta.TransferEvent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := e.(*event_def.TransferEvent)
+	ev.Coord_ = common.NewCoordinate(1, 0)
+	ev.From = addr
+	ev.Amount = amount.NewCoinAmount(1, 0)
+	s.Publish([]event.Event{ev})
+	var notification struct {
+		Method string `json:"method"`
+		Params struct {
+			Subscription uint64          `json:"subscription"`
+			Event        json.RawMessage `json:"event"`
+		} `json:"params"`
+	}
+	bs := ws.read(t)
+	if err := json.Unmarshal(bs, &notification); err != nil {
+		t.Fatal(err)
+	}
+	if notification.Method != "event" || notification.Params.Subscription != 1 || !bytes.Contains(notification.Params.Event, []byte(addr.String())) {
+		t.Errorf("unexpected notification %s", bs)
+	}
+
+	ws.write(t, `{"jsonrpc":"2.0","id":3,"method":"unsubscribe","params":{"subscription":1}}`)
+	if err := json.Unmarshal(ws.read(t), &res); err != nil {
+		t.Fatal(err)
+	}
+	if string(res.Result) != "true" || len(s.hub.subscriptions("")) != 0 {
+		t.Errorf("unexpected unsubscription %s", res.Result)
+	}
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	s := NewServer(newTestBackend(t))
+	hs := httptest.NewServer(s)
+	defer hs.Close()
+
+	for origin, status := range map[string]int{
+		"http://test":         http.StatusSwitchingProtocols,
+		"https://TEST":        http.StatusSwitchingProtocols,
+		"http://evil.example": http.StatusForbidden,
+		"http://test.example": http.StatusForbidden,
+	} {
+		ws, res := handshake(t, hs.URL, origin)
+		ws.conn.Close()
+		if res.StatusCode != status {
+			t.Errorf("expected the status %d of %s but %d", status, origin, res.StatusCode)
+		}
+	}
+
+	s.SetCheckOrigin(func(r *http.Request) bool { return true })
+	ws, res := handshake(t, hs.URL, "http://evil.example")
+	ws.conn.Close()
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("expected the accepted origin but %d", res.StatusCode)
+	}
+}
+
+func TestWebSocketFragments(t *testing.T) {
+	b := newTestBackend(t)
+	addr := b.addAccount(t, 1)
+	hs := httptest.NewServer(NewServer(b))
+	defer hs.Close()
+
+	ws := dialWebSocket(t, hs.URL)
+	defer ws.conn.Close()
+
+	// the ping between the fragments is answered and does not end the message
+	msg := []byte(`{"jsonrpc":"2.0","id":1,"method":"sequence","params":{"address":"` + addr.String() + `"}}`)
+	ws.writeFrame(t, 0x01, msg[:10])
+	ws.writeFrame(t, 0x89, []byte("ping"))
+	ws.writeFrame(t, 0x00, msg[10:20])
+	ws.writeFrame(t, 0x8A, nil)
+	ws.writeFrame(t, 0x80, msg[20:])
+	if op, payload := ws.readFrame(t); op != opPong || string(payload) != "ping" {
+		t.Fatalf("expected the pong but %d %s", op, payload)
+	}
+	var res testResponse
+	if err := json.Unmarshal(ws.read(t), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Error != nil || string(res.Result) != "3" {
+		t.Errorf("unexpected sequence %s %v", res.Result, res.Error)
+	}
+}
+
+func TestWebSocketReadTimeout(t *testing.T) {
+	s := NewServer(newTestBackend(t))
+	s.SetWebSocketReadTimeout(100 * time.Millisecond)
+	hs := httptest.NewServer(s)
+	defer hs.Close()
+
+	ws := dialWebSocket(t, hs.URL)
+	defer ws.conn.Close()
+
+	// the idle connection is closed by the server
+	ws.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := ws.br.ReadByte(); err != io.EOF {
+		t.Fatalf("expected the closed connection but %v", err)
+	}
+}
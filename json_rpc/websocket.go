@@ -0,0 +1,236 @@
+package json_rpc
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocket errors
+var (
+	errInvalidWebSocketFrame = errors.New("invalid websocket frame")
+	errTooLargeMessage       = errors.New("too large message")
+)
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// websocket opcodes
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+func isWebSocketRequest(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, v := range strings.Split(r.Header.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(v), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// isSameOrigin accepts the request that has no Origin like a non-browser client or has the Origin of the requested host
+func isSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// wsConn is the server side of a WebSocket connection
+// It supports the text and binary messages that can be fragmented and answers the ping and the close
+type wsConn struct {
+	sync.Mutex
+	conn        net.Conn
+	br          *bufio.Reader
+	readTimeout time.Duration
+}
+
+// upgrade completes the opening handshake of the request
+// The hijacked connection has no deadline of the http server, so the reads of the connection wait for the next frame at most readTimeout
+func upgrade(w http.ResponseWriter, r *http.Request, readTimeout time.Duration) (*wsConn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, errInvalidWebSocketFrame
+	}
+	key := r.Header.Get("Sec-Websocket-Key")
+	if len(key) == 0 || r.Header.Get("Sec-Websocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusBadRequest)
+		return nil, errInvalidWebSocketFrame
+	}
+	hj, is := w.(http.Hijacker)
+	if !is {
+		http.Error(w, "websocket is not supported", http.StatusInternalServerError)
+		return nil, errInvalidWebSocketFrame
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	res := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(res)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{
+		conn:        conn,
+		br:          rw.Reader,
+		readTimeout: readTimeout,
+	}, nil
+}
+
+// ReadMessage returns the payload of the next data message
+// The control frames can be interleaved with the fragments of the message, so the message ends at the data frame that has the FIN bit
+// It returns io.EOF when the peer closes the connection and a timeout error when the peer sends no frame in the read timeout
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	isStarted := false
+	for {
+		if err := c.conn.SetReadDeadline(time.Now().Add(c.readTimeout)); err != nil {
+			return nil, err
+		}
+		fin, op, payload, err := c.readFrame(MaxRequestSize - len(message))
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opClose:
+			c.writeFrame(opClose, payload)
+			return nil, io.EOF
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+		case opPong:
+		case opText, opBinary:
+			if isStarted {
+				return nil, errInvalidWebSocketFrame
+			}
+			isStarted = true
+			message = payload
+			if fin {
+				return message, nil
+			}
+		case opContinuation:
+			if !isStarted {
+				return nil, errInvalidWebSocketFrame
+			}
+			message = append(message, payload...)
+			if fin {
+				return message, nil
+			}
+		default:
+			return nil, errInvalidWebSocketFrame
+		}
+	}
+}
+
+func (c *wsConn) readFrame(limit int) (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := head[0]&0x80 != 0
+	op := head[0] & 0x0F
+	if head[0]&0x70 != 0 || head[1]&0x80 == 0 {
+		// no extension is negotiated and the client frames should be masked
+		return false, 0, nil, errInvalidWebSocketFrame
+	}
+	size := uint64(head[1] & 0x7F)
+	switch size {
+	case 126:
+		var bs [2]byte
+		if _, err := io.ReadFull(c.br, bs[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(bs[:]))
+	case 127:
+		var bs [8]byte
+		if _, err := io.ReadFull(c.br, bs[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(bs[:])
+	}
+	if op >= opClose && (!fin || size > 125) {
+		return false, 0, nil, errInvalidWebSocketFrame
+	}
+	if size > uint64(limit) {
+		return false, 0, nil, errTooLargeMessage
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// WriteMessage writes the text message
+func (c *wsConn) WriteMessage(bs []byte) error {
+	return c.writeFrame(opText, bs)
+}
+
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	c.Lock()
+	defer c.Unlock()
+
+	head := make([]byte, 2, 10)
+	head[0] = 0x80 | op
+	switch {
+	case len(payload) < 126:
+		head[1] = byte(len(payload))
+	case len(payload) < 65536:
+		head[1] = 126
+		head = head[:4]
+		binary.BigEndian.PutUint16(head[2:], uint16(len(payload)))
+	default:
+		head[1] = 127
+		head = head[:10]
+		binary.BigEndian.PutUint64(head[2:], uint64(len(payload)))
+	}
+	if _, err := c.conn.Write(append(head, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Close closes the connection
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,106 @@
+package json_util
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+)
+
+// ParseAmount returns the amount of the JSON value that is written by Amount.MarshalJSON
+func ParseAmount(raw json.RawMessage) (*amount.Amount, error) {
+	str := strings.TrimSpace(string(raw))
+	if len(str) == 0 || str == `null` {
+		return amount.NewCoinAmount(0, 0), nil
+	}
+	if strings.HasPrefix(str, `"`) {
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return nil, err
+		}
+	}
+	return amount.ParseAmount(str)
+}
+
+// ParseTag returns the tag of the hex JSON string or nil when it is null
+// It returns the LimitError of the field when the tag is longer than max bytes like the ReadFrom of the field
+func ParseTag(str *string, field string, max uint64) ([]byte, error) {
+	if str == nil || len(*str) == 0 {
+		return nil, nil
+	}
+	if err := codec.CheckByteLength(field, uint64(len(*str)/2), max); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(*str)
+}
+
+// ParsePublicHashes returns public hashes of the JSON strings
+func ParsePublicHashes(strs []string) ([]common.PublicHash, error) {
+	list := make([]common.PublicHash, 0, len(strs))
+	for _, str := range strs {
+		pubhash, err := common.ParsePublicHash(str)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, pubhash)
+	}
+	return list, nil
+}
+
+// ParseAddresses returns addresses of the JSON strings
+func ParseAddresses(strs []string) ([]common.Address, error) {
+	list := make([]common.Address, 0, len(strs))
+	for _, str := range strs {
+		addr, err := common.ParseAddress(str)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, addr)
+	}
+	return list, nil
+}
+
+// ParseVin returns the vin of the JSON ids
+func ParseVin(ids []uint64) []*transaction.TxIn {
+	vins := make([]*transaction.TxIn, 0, len(ids))
+	for _, id := range ids {
+		vins = append(vins, transaction.NewTxIn(id))
+	}
+	return vins
+}
+
+// ParseVout returns the vout of the JSON values that are written by TxOut.MarshalJSON
+func ParseVout(raws []json.RawMessage) ([]*transaction.TxOut, error) {
+	vouts := make([]*transaction.TxOut, 0, len(raws))
+	for _, raw := range raws {
+		var v struct {
+			Amount     json.RawMessage `json:"amount"`
+			PublicHash string          `json:"public_hash"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		vout := transaction.NewTxOut()
+		if am, err := ParseAmount(v.Amount); err != nil {
+			return nil, err
+		} else {
+			vout.Amount = am
+		}
+		if pubhash, err := common.ParsePublicHash(v.PublicHash); err != nil {
+			return nil, err
+		} else {
+			vout.PublicHash = pubhash
+		}
+		vouts = append(vouts, vout)
+	}
+	return vouts, nil
+}
+
+// Coordinate is a JSON form of the coordinate
+type Coordinate struct {
+	Height uint32 `json:"height"`
+	Index  uint16 `json:"index"`
+}
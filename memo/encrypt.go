@@ -0,0 +1,113 @@
+package memo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	ecrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/fletaio/common"
+)
+
+// encrypted memo layout : ephemeral public key(33) + nonce(12) + sealed text(len(text) + 16)
+const (
+	ephemeralKeySize = 33
+	nonceSize        = 12
+	overheadSize     = ephemeralKeySize + nonceSize + 16
+)
+
+// MaxEncryptedTextLength is the maximum byte length of the text that can be encrypted to the tag
+const MaxEncryptedTextLength = MaxTagLength - 1 - overheadSize
+
+// EncryptTo returns the tag that has the text encrypted to the public key of the recipient
+// The text is sealed by AES-GCM using the key that is derived by ECDH of an ephemeral key and the recipient key
+func EncryptTo(pubkey common.PublicKey, text []byte) ([]byte, error) {
+	ephemeral, err := ecrypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return encryptWith(ephemeral, nonce, pubkey, text)
+}
+
+func encryptWith(ephemeral *ecdsa.PrivateKey, nonce []byte, pubkey common.PublicKey, text []byte) ([]byte, error) {
+	if len(text) > MaxEncryptedTextLength {
+		return nil, ErrTooLongMemo
+	}
+	pub, err := ecrypto.DecompressPubkey(pubkey[:])
+	if err != nil {
+		return nil, err
+	}
+	ephemeralPub := ecrypto.CompressPubkey(&ephemeral.PublicKey)
+	aead, err := sharedAEAD(ephemeral, pub, ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+	Payload := make([]byte, 0, overheadSize+len(text))
+	Payload = append(Payload, ephemeralPub...)
+	Payload = append(Payload, nonce...)
+	Payload = aead.Seal(Payload, nonce, text, ephemeralPub)
+	return NewEncrypted(Payload).Bytes()
+}
+
+// Decrypt returns the text of the encrypted memo in the tag using the private key of the recipient
+func Decrypt(priv *ecdsa.PrivateKey, tag []byte) ([]byte, error) {
+	m, err := Parse(tag)
+	if err != nil {
+		return nil, err
+	}
+	if m.Type != EncryptedType {
+		return nil, ErrInvalidMemoType
+	}
+	if len(m.Payload) < overheadSize {
+		return nil, ErrInvalidMemoPayload
+	}
+	ephemeralPub := m.Payload[:ephemeralKeySize]
+	nonce := m.Payload[ephemeralKeySize : ephemeralKeySize+nonceSize]
+	sealed := m.Payload[ephemeralKeySize+nonceSize:]
+
+	pub, err := ecrypto.DecompressPubkey(ephemeralPub)
+	if err != nil {
+		return nil, ErrInvalidMemoPayload
+	}
+	aead, err := sharedAEAD(priv, pub, ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+	text, err := aead.Open(nil, nonce, sealed, ephemeralPub)
+	if err != nil {
+		return nil, ErrInvalidMemoPayload
+	}
+	return text, nil
+}
+
+// DecryptByKeyBytes returns the text of the encrypted memo in the tag using the private key bytes of the recipient
+func DecryptByKeyBytes(pk []byte, tag []byte) ([]byte, error) {
+	priv, err := ecrypto.ToECDSA(pk)
+	if err != nil {
+		return nil, err
+	}
+	return Decrypt(priv, tag)
+}
+
+func sharedAEAD(priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey, ephemeralPub []byte) (cipher.AEAD, error) {
+	x, _ := ecrypto.S256().ScalarMult(pub.X, pub.Y, priv.D.Bytes())
+	shared := make([]byte, 32)
+	xbs := x.Bytes()
+	copy(shared[32-len(xbs):], xbs)
+
+	h := sha256.New()
+	h.Write(shared)
+	h.Write(ephemeralPub)
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
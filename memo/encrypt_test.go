@@ -0,0 +1,110 @@
+package memo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	ecrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/fletaio/common"
+)
+
+var encryptVectors = []struct {
+	recipient string
+	ephemeral string
+	nonce     string
+	text      string
+	tag       string
+}{
+	{
+		recipient: "5e0dc680d12a728f60a708dcdbfb8d2c2aaea3ee5748d12bd9358f1015e3d18b",
+		ephemeral: "116034cda48d0704426ae2141a6ea8d9a4193862cebfc9a25aab53063916caac",
+		nonce:     "000102030405060708090a0b",
+		text:      "invoice-2019-0001",
+		tag:       "03030e01b991d14c237e9760fb3ca38391153fd65d6d40c207b1339bb0a8944d2265000102030405060708090a0b21fa3f169a145bbc25457dbba7ca0ad3e65ea98a498f61c8d7d3c814b52659704a",
+	},
+	{
+		recipient: "0ee9aff86e07b44d9adb40246c2ecbf511000d96f22b5a0232fff27e3df8c88d",
+		ephemeral: "246fd0687a0cc717a5af8ae2067fcdd05e9227f27b37e3a5387081037b340b7a",
+		nonce:     "0b0a09080706050403020100",
+		text:      "",
+		tag:       "0302cfab919a0c94a3a132daf7a18092f27643e7d3bbded13d80604fc40a57f495510b0a09080706050403020100ed034d2e16144b06d0af23b64d82d208",
+	},
+}
+
+func mustDecodeHex(t *testing.T, str string) []byte {
+	bs, err := hex.DecodeString(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
+func publicKeyOf(t *testing.T, pk []byte) common.PublicKey {
+	priv, err := ecrypto.ToECDSA(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pubkey common.PublicKey
+	copy(pubkey[:], ecrypto.CompressPubkey(&priv.PublicKey))
+	return pubkey
+}
+
+func TestEncryptVectors(t *testing.T) {
+	for i, v := range encryptVectors {
+		recipient := mustDecodeHex(t, v.recipient)
+		ephemeral, err := ecrypto.ToECDSA(mustDecodeHex(t, v.ephemeral))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag, err := encryptWith(ephemeral, mustDecodeHex(t, v.nonce), publicKeyOf(t, recipient), []byte(v.text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(tag) != v.tag {
+			t.Errorf("vector %d: tag mismatch\n got %x\nwant %s", i, tag, v.tag)
+		}
+		text, err := DecryptByKeyBytes(recipient, mustDecodeHex(t, v.tag))
+		if err != nil {
+			t.Fatalf("vector %d: %v", i, err)
+		}
+		if string(text) != v.text {
+			t.Errorf("vector %d: text mismatch: got %q want %q", i, text, v.text)
+		}
+	}
+}
+
+func TestEncryptTo(t *testing.T) {
+	recipient := mustDecodeHex(t, encryptVectors[0].recipient)
+	text := []byte("order #42")
+	tag, err := EncryptTo(publicKeyOf(t, recipient), text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tag) > MaxTagLength {
+		t.Fatalf("tag is too long: %d", len(tag))
+	}
+	if decrypted, err := DecryptByKeyBytes(recipient, tag); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(decrypted, text) {
+		t.Fatalf("got %q want %q", decrypted, text)
+	}
+
+	other := mustDecodeHex(t, encryptVectors[1].recipient)
+	if _, err := DecryptByKeyBytes(other, tag); err != ErrInvalidMemoPayload {
+		t.Fatalf("decrypt by other key: got %v want %v", err, ErrInvalidMemoPayload)
+	}
+
+	tag[len(tag)-1] ^= 0x01
+	if _, err := DecryptByKeyBytes(recipient, tag); err != ErrInvalidMemoPayload {
+		t.Fatalf("decrypt tampered tag: got %v want %v", err, ErrInvalidMemoPayload)
+	}
+}
+
+func TestEncryptTooLongText(t *testing.T) {
+	recipient := mustDecodeHex(t, encryptVectors[0].recipient)
+	text := make([]byte, MaxEncryptedTextLength+1)
+	if _, err := EncryptTo(publicKeyOf(t, recipient), text); err != ErrTooLongMemo {
+		t.Fatalf("got %v want %v", err, ErrTooLongMemo)
+	}
+}
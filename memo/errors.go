@@ -0,0 +1,12 @@
+package memo
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// memo errors
+var (
+	ErrInvalidMemoType    = errs.ErrInvalidMemoType
+	ErrInvalidMemoPayload = errs.ErrInvalidMemoPayload
+	ErrTooLongMemo        = errs.ErrTooLongMemo
+)
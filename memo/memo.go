@@ -0,0 +1,176 @@
+package memo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// MaxTagLength is the maximum byte length of the tag of transactions
+const MaxTagLength = 256
+
+// Type is the type of the memo that is stored in the first byte of the tag
+type Type uint8
+
+// memo types
+const (
+	TextType           = Type(1)
+	DestinationTagType = Type(2)
+	EncryptedType      = Type(3)
+)
+
+// String returns the name of the memo type
+func (t Type) String() string {
+	switch t {
+	case TextType:
+		return "text"
+	case DestinationTagType:
+		return "destination_tag"
+	case EncryptedType:
+		return "encrypted"
+	default:
+		return "unknown"
+	}
+}
+
+// Memo is a structured content of the tag
+type Memo struct {
+	Type           Type
+	Text           string
+	DestinationTag uint64
+	Payload        []byte
+}
+
+// NewText returns a plain text memo
+func NewText(Text string) *Memo {
+	return &Memo{
+		Type: TextType,
+		Text: Text,
+	}
+}
+
+// NewDestinationTag returns a numeric destination tag memo
+func NewDestinationTag(DestinationTag uint64) *Memo {
+	return &Memo{
+		Type:           DestinationTagType,
+		DestinationTag: DestinationTag,
+	}
+}
+
+// NewEncrypted returns a memo that has the encrypted payload
+func NewEncrypted(Payload []byte) *Memo {
+	return &Memo{
+		Type:    EncryptedType,
+		Payload: Payload,
+	}
+}
+
+// Bytes returns the tag that is encoded from the memo
+func (m *Memo) Bytes() ([]byte, error) {
+	var body []byte
+	switch m.Type {
+	case TextType:
+		if !utf8.ValidString(m.Text) {
+			return nil, ErrInvalidMemoPayload
+		}
+		body = []byte(m.Text)
+	case DestinationTagType:
+		body = make([]byte, 8)
+		binary.LittleEndian.PutUint64(body, m.DestinationTag)
+	case EncryptedType:
+		if len(m.Payload) == 0 {
+			return nil, ErrInvalidMemoPayload
+		}
+		body = m.Payload
+	default:
+		return nil, ErrInvalidMemoType
+	}
+	if 1+len(body) > MaxTagLength {
+		return nil, ErrTooLongMemo
+	}
+	bs := make([]byte, 0, 1+len(body))
+	bs = append(bs, byte(m.Type))
+	bs = append(bs, body...)
+	return bs, nil
+}
+
+// Parse returns the memo that is decoded from the tag
+func Parse(tag []byte) (*Memo, error) {
+	if len(tag) == 0 {
+		return nil, ErrInvalidMemoType
+	}
+	if len(tag) > MaxTagLength {
+		return nil, ErrTooLongMemo
+	}
+	body := tag[1:]
+	switch Type(tag[0]) {
+	case TextType:
+		if !utf8.Valid(body) {
+			return nil, ErrInvalidMemoPayload
+		}
+		return NewText(string(body)), nil
+	case DestinationTagType:
+		if len(body) != 8 {
+			return nil, ErrInvalidMemoPayload
+		}
+		return NewDestinationTag(binary.LittleEndian.Uint64(body)), nil
+	case EncryptedType:
+		if len(body) == 0 {
+			return nil, ErrInvalidMemoPayload
+		}
+		Payload := make([]byte, len(body))
+		copy(Payload, body)
+		return NewEncrypted(Payload), nil
+	default:
+		return nil, ErrInvalidMemoType
+	}
+}
+
+// MarshalJSON is a marshaler function
+func (m *Memo) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(m.Type.String()); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	switch m.Type {
+	case TextType:
+		buffer.WriteString(`,`)
+		buffer.WriteString(`"text":`)
+		if bs, err := json.Marshal(m.Text); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	case DestinationTagType:
+		buffer.WriteString(`,`)
+		buffer.WriteString(`"destination_tag":`)
+		if bs, err := json.Marshal(m.DestinationTag); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	case EncryptedType:
+		buffer.WriteString(`,`)
+		buffer.WriteString(`"payload":`)
+		buffer.WriteString(`"`)
+		buffer.WriteString(hex.EncodeToString(m.Payload))
+		buffer.WriteString(`"`)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// MarshalTagJSON returns the JSON of the memo in the tag or null when the tag is not a memo
+func MarshalTagJSON(tag []byte) ([]byte, error) {
+	m, err := Parse(tag)
+	if err != nil {
+		return []byte(`null`), nil
+	}
+	return m.MarshalJSON()
+}
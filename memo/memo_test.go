@@ -0,0 +1,123 @@
+package memo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTextMemo(t *testing.T) {
+	bs, err := NewText("invoice-2019-0001").Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bs, append([]byte{byte(TextType)}, "invoice-2019-0001"...)) {
+		t.Fatalf("unexpected tag %x", bs)
+	}
+	m, err := Parse(bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != TextType || m.Text != "invoice-2019-0001" {
+		t.Fatalf("unexpected memo %+v", m)
+	}
+	js, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(js) != `{"type":"text","text":"invoice-2019-0001"}` {
+		t.Fatalf("unexpected json %s", js)
+	}
+
+	// an empty text is a valid memo
+	bs, err = NewText("").Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, err := Parse(bs); err != nil || m.Text != "" {
+		t.Fatalf("unexpected memo %+v, %v", m, err)
+	}
+}
+
+func TestDestinationTagMemo(t *testing.T) {
+	bs, err := NewDestinationTag(0x0102030405060708).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bs, []byte{byte(DestinationTagType), 8, 7, 6, 5, 4, 3, 2, 1}) {
+		t.Fatalf("unexpected tag %x", bs)
+	}
+	m, err := Parse(bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Type != DestinationTagType || m.DestinationTag != 0x0102030405060708 {
+		t.Fatalf("unexpected memo %+v", m)
+	}
+	js, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(js) != `{"type":"destination_tag","destination_tag":72623859790382856}` {
+		t.Fatalf("unexpected json %s", js)
+	}
+}
+
+func TestMalformedMemo(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  []byte
+		err  error
+	}{
+		{"empty", nil, ErrInvalidMemoType},
+		{"unknown type", []byte{0, 'a'}, ErrInvalidMemoType},
+		{"invalid utf8 text", []byte{byte(TextType), 0xff, 0xfe}, ErrInvalidMemoPayload},
+		{"short destination tag", []byte{byte(DestinationTagType), 1, 2, 3}, ErrInvalidMemoPayload},
+		{"long destination tag", []byte{byte(DestinationTagType), 1, 2, 3, 4, 5, 6, 7, 8, 9}, ErrInvalidMemoPayload},
+		{"empty encrypted", []byte{byte(EncryptedType)}, ErrInvalidMemoPayload},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.tag); err != tt.err {
+			t.Errorf("%s: expected %v but %v", tt.name, tt.err, err)
+		}
+		// a tag that is not a memo is written as null
+		if js, err := MarshalTagJSON(tt.tag); err != nil || string(js) != `null` {
+			t.Errorf("%s: unexpected json %s, %v", tt.name, js, err)
+		}
+	}
+
+	if _, err := NewText(string([]byte{0xff})).Bytes(); err != ErrInvalidMemoPayload {
+		t.Errorf("expected %v but %v", ErrInvalidMemoPayload, err)
+	}
+	if _, err := NewEncrypted(nil).Bytes(); err != ErrInvalidMemoPayload {
+		t.Errorf("expected %v but %v", ErrInvalidMemoPayload, err)
+	}
+	if _, err := (&Memo{Type: Type(9)}).Bytes(); err != ErrInvalidMemoType {
+		t.Errorf("expected %v but %v", ErrInvalidMemoType, err)
+	}
+}
+
+func TestMemoLengthCap(t *testing.T) {
+	// the type byte and the text fill the tag
+	text := strings.Repeat("a", MaxTagLength-1)
+	bs, err := NewText(text).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bs) != MaxTagLength {
+		t.Fatalf("expected %d bytes but %d", MaxTagLength, len(bs))
+	}
+	if m, err := Parse(bs); err != nil || m.Text != text {
+		t.Fatalf("unexpected memo %+v, %v", m, err)
+	}
+
+	if _, err := NewText(text + "a").Bytes(); err != ErrTooLongMemo {
+		t.Fatalf("expected %v but %v", ErrTooLongMemo, err)
+	}
+	if _, err := NewEncrypted(make([]byte, MaxTagLength)).Bytes(); err != ErrTooLongMemo {
+		t.Fatalf("expected %v but %v", ErrTooLongMemo, err)
+	}
+	if _, err := Parse(append([]byte{byte(TextType)}, text+"a"...)); err != ErrTooLongMemo {
+		t.Fatalf("expected %v but %v", ErrTooLongMemo, err)
+	}
+}
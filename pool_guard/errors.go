@@ -0,0 +1,23 @@
+package pool_guard
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// pool guard error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeExceedSequenceWindow errs.Code = 2501
+	CodePendingSequence      errs.Code = 2502
+	CodePendingUTXO          errs.Code = 2503
+)
+
+// pool guard errors
+var (
+	ErrExceedSequenceWindow = errs.New(CodeExceedSequenceWindow, "exceed sequence window")
+	ErrPendingSequence      = errs.New(CodePendingSequence, "pending sequence")
+	ErrPendingUTXO          = errs.New(CodePendingUTXO, "pending utxo")
+	ErrInvalidSequence      = errs.ErrInvalidSequence
+	ErrInsufficientBalance  = errs.ErrInsufficientBalance
+	ErrInvalidOutputAmount  = errs.ErrInvalidOutputAmount
+)
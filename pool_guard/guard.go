@@ -0,0 +1,298 @@
+// Package pool_guard rejects the extension transactions that cannot be executed before they enter the transaction pool.
+//
+// The registered validator of a transaction only sees the state of the chain, so a sender can fill the pool with transactions
+// that will fail one after another when their block is made. The guard runs the stateless checks of the transactions and
+// keeps the pending transactions of each sender to check them against the transactions that are pushed later:
+//
+//	the sequence should be in the window after the sequence of the chain and not used by a pending transaction
+//	the balance of the fee payer should cover the fee and the amount of it with the spends of the pending transactions
+//	the UTXOs should not be spent by a pending transaction and their amount should cover the outputs and the fee
+//
+// The guard is added to the kernel as an event handler, and the pending transactions are released when they are included
+// in a processed block, when their sequence is passed by the chain or when they are not included in PendingTimeout blocks.
+package pool_guard
+
+import (
+	"sync"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/message_def"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/fee"
+	"github.com/fletaio/extension/tx_builder"
+)
+
+// DefaultSequenceWindow is the number of the sequences after the sequence of the chain that a sender can use in the pool
+const DefaultSequenceWindow = 64
+
+// PendingTimeout is the number of the blocks that a pending transaction is kept when it is not included
+const PendingTimeout = 100
+
+type pendingTx struct {
+	hash   hash.Hash256
+	from   common.Address
+	seq    uint64
+	spend  *amount.Amount // the fee and the amount, nil for a UTXO transaction
+	vins   []uint64
+	height uint32
+}
+
+// Guard checks the transactions that are pushed to the transaction pool against the pending transactions
+type Guard struct {
+	sync.Mutex
+	window     uint64
+	hashMap    map[hash.Hash256]*pendingTx
+	accountMap map[common.Address]map[uint64]*pendingTx
+	vinMap     map[uint64]*pendingTx
+	err        error
+}
+
+// NewGuard returns a Guard that allows the sequences in the window after the sequence of the chain
+func NewGuard(window uint64) *Guard {
+	if window == 0 {
+		window = DefaultSequenceWindow
+	}
+	return &Guard{
+		window:     window,
+		hashMap:    map[hash.Hash256]*pendingTx{},
+		accountMap: map[common.Address]map[uint64]*pendingTx{},
+		vinMap:     map[uint64]*pendingTx{},
+	}
+}
+
+// Len returns the number of the pending transactions
+func (g *Guard) Len() int {
+	g.Lock()
+	defer g.Unlock()
+
+	return len(g.hashMap)
+}
+
+// Err returns the failure of adding the last pushed transaction to the pending transactions
+// A transaction that is not added is not counted by the later checks
+func (g *Guard) Err() error {
+	g.Lock()
+	defer g.Unlock()
+
+	return g.err
+}
+
+// Check validates the transaction with the state of the loader and the pending transactions
+func (g *Guard) Check(loader data.Loader, tx transaction.Transaction, sigs []common.Signature) error {
+	signed := &tx_builder.Signed{
+		Tx:   tx,
+		Sigs: sigs,
+	}
+	signers, err := signed.Signers()
+	if err != nil {
+		return err
+	}
+	if sc, is := tx.(tx_builder.StatelessChecker); is {
+		if err := sc.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
+		}
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	if _, err := g.pendingOf(loader, tx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Add keeps the transaction as a pending one
+// The transaction is not kept when it is not valid with the pending transactions that are added after it is checked
+func (g *Guard) Add(loader data.Loader, tx transaction.Transaction) error {
+	g.Lock()
+	defer g.Unlock()
+
+	p, err := g.pendingOf(loader, tx)
+	if err != nil {
+		return err
+	}
+	g.hashMap[p.hash] = p
+	if p.spend != nil {
+		seqMap, has := g.accountMap[p.from]
+		if !has {
+			seqMap = map[uint64]*pendingTx{}
+			g.accountMap[p.from] = seqMap
+		}
+		seqMap[p.seq] = p
+	}
+	for _, id := range p.vins {
+		g.vinMap[id] = p
+	}
+	return nil
+}
+
+// Release removes the pending transactions that are included in the block or cannot be included after it
+// The loader should have the state after the block
+func (g *Guard) Release(loader data.Loader, b *block.Block) {
+	g.Lock()
+	defer g.Unlock()
+
+	for _, tx := range b.Body.Transactions {
+		if p, has := g.hashMap[tx.Hash()]; has {
+			g.remove(p)
+		}
+	}
+	height := b.Header.Height()
+	for addr, seqMap := range g.accountMap {
+		seq := loader.Seq(addr)
+		for s, p := range seqMap {
+			if s <= seq {
+				g.remove(p)
+			}
+		}
+	}
+	for _, p := range g.hashMap {
+		if p.height+PendingTimeout <= height {
+			g.remove(p)
+		}
+	}
+}
+
+func (g *Guard) remove(p *pendingTx) {
+	delete(g.hashMap, p.hash)
+	if p.spend != nil {
+		if seqMap, has := g.accountMap[p.from]; has {
+			delete(seqMap, p.seq)
+			if len(seqMap) == 0 {
+				delete(g.accountMap, p.from)
+			}
+		}
+	}
+	for _, id := range p.vins {
+		if g.vinMap[id] == p {
+			delete(g.vinMap, id)
+		}
+	}
+}
+
+// pendingOf returns the pending form of the transaction when it is valid with the pending transactions
+func (g *Guard) pendingOf(loader data.Loader, tx transaction.Transaction) (*pendingTx, error) {
+	p := &pendingTx{
+		hash:   tx.Hash(),
+		height: loader.TargetHeight(),
+	}
+	if tx.IsUTXO() {
+		if utx, is := tx.(utxoTransaction); is {
+			insum := amount.NewCoinAmount(0, 0)
+			for _, id := range utx.VinIDs() {
+				if _, has := g.vinMap[id]; has {
+					return nil, errs.WrapValue(ErrPendingUTXO, tx.Type(), "vin", nil, id)
+				}
+				utxo, err := loader.UTXO(id)
+				if err != nil {
+					return nil, err
+				}
+				insum = insum.Add(utxo.Amount)
+			}
+			if out, has := Output(tx); has {
+				Fee, err := requiredFee(loader, tx)
+				if err != nil {
+					return nil, err
+				}
+				if outsum := Fee.Add(out); !insum.Equal(outsum) {
+					return nil, errs.WrapValue(ErrInvalidOutputAmount, tx.Type(), "vout", insum, outsum)
+				}
+			}
+			p.vins = utx.VinIDs()
+		}
+		return p, nil
+	}
+	atx, is := tx.(accountTransaction)
+	if !is {
+		return p, nil
+	}
+	p.from = atx.From()
+	p.seq = atx.Seq()
+
+	seq := loader.Seq(p.from)
+	if p.seq <= seq {
+		return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", seq+1, p.seq)
+	}
+	if p.seq > seq+g.window {
+		return nil, errs.WrapValue(ErrExceedSequenceWindow, tx.Type(), "seq", seq+g.window, p.seq)
+	}
+	seqMap := g.accountMap[p.from]
+	if _, has := seqMap[p.seq]; has {
+		return nil, errs.WrapValue(ErrPendingSequence, tx.Type(), "seq", nil, p.seq)
+	}
+
+	Fee, err := requiredFee(loader, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := loader.Account(p.from)
+	if err != nil {
+		return nil, err
+	}
+	available := acc.Balance()
+	for s, pp := range seqMap {
+		if s > seq {
+			available = available.Sub(pp.spend)
+		}
+	}
+	spend := Fee.Add(Spend(tx, Fee))
+	if _, is := tx.(*account_tx.CloseAccount); is && !available.Less(spend) {
+		// the remaining balance is moved, so no spend of the sender can follow it
+		spend = available
+	}
+	if available.Less(spend) {
+		return nil, errs.WrapValue(ErrInsufficientBalance, tx.Type(), "amount", available, spend)
+	}
+	p.spend = spend
+	return p, nil
+}
+
+// requiredFee returns the fee that the executor of the transaction requires
+func requiredFee(loader data.Loader, tx transaction.Transaction) (*amount.Amount, error) {
+	Fee, err := loader.Transactor().Fee(tx.Type())
+	if err != nil {
+		return nil, err
+	}
+	return fee.Required(loader.ChainCoord(), Fee, tx)
+}
+
+// AfterProcessBlock called when processed block to the chain
+func (g *Guard) AfterProcessBlock(kn *kernel.Kernel, b *block.Block, s *block.ObserverSigned, ctx *data.Context) {
+	g.Release(ctx, b)
+}
+
+// OnProcessBlock called when processing a block to the chain (error prevent processing block)
+func (g *Guard) OnProcessBlock(kn *kernel.Kernel, b *block.Block, s *block.ObserverSigned, ctx *data.Context) error {
+	return nil
+}
+
+// OnPushTransaction called when pushing a transaction to the transaction pool (error prevent push transaction)
+func (g *Guard) OnPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) error {
+	return g.Check(kn.Loader(), tx, sigs)
+}
+
+// AfterPushTransaction called when pushed a transaction to the transaction pool
+func (g *Guard) AfterPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) {
+	err := g.Add(kn.Loader(), tx)
+
+	g.Lock()
+	defer g.Unlock()
+	g.err = err
+}
+
+// DoTransactionBroadcast called when a transaction need to be broadcast
+func (g *Guard) DoTransactionBroadcast(kn *kernel.Kernel, msg *message_def.TransactionMessage) {
+}
+
+// DebugLog TEMP
+func (g *Guard) DebugLog(kn *kernel.Kernel, args ...interface{}) {
+}
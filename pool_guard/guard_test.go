@@ -0,0 +1,366 @@
+package pool_guard
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/key"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/chainkit"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/fee"
+	"github.com/fletaio/extension/internal/test_util"
+	"github.com/fletaio/extension/tx_builder"
+	"github.com/fletaio/extension/type_registry"
+	"github.com/fletaio/extension/utxo_tx"
+)
+
+func newTestLoader(t *testing.T) *test_util.Loader {
+	loader := test_util.NewLoader(common.NewCoordinate(0, 0))
+	if err := type_registry.Register(loader.Act, loader.Tran, loader.Evt); err != nil {
+		t.Fatal(err)
+	}
+	return loader
+}
+
+func addAccount(t *testing.T, loader *test_util.Loader, n uint16, balance *amount.Amount) common.Address {
+	a, err := loader.Act.NewByTypeName("fleta.SingleAccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := a.(*account_def.SingleAccount)
+	acc.Address_ = test_util.Address(n)
+	acc.Name_ = "testaccount"
+	acc.Balance_ = balance
+	loader.AddAccount(acc, 0)
+	return acc.Address_
+}
+
+func newTransfer(t *testing.T, loader *test_util.Loader, from common.Address, to common.Address, seq uint64, am *amount.Amount) *account_tx.Transfer {
+	tx, err := loader.Tran.NewByTypeName("fleta.Transfer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tx.(*account_tx.Transfer)
+	tr.From_ = from
+	tr.Seq_ = seq
+	tr.Amount = am
+	tr.To = account_name.Destination{Address: to}
+	return tr
+}
+
+func TestCheckSequence(t *testing.T) {
+	loader := newTestLoader(t)
+	from := addAccount(t, loader, 1, amount.NewCoinAmount(1000, 0))
+	to := addAccount(t, loader, 2, amount.NewCoinAmount(0, 0))
+	loader.Seqs[from] = 3
+	g := NewGuard(2)
+
+	if err := g.Check(loader, newTransfer(t, loader, from, to, 3, amount.NewCoinAmount(1, 0)), nil); errs.CodeOf(err) != errs.CodeInvalidSequence {
+		t.Fatalf("expected invalid sequence, got %v", err)
+	}
+	if err := g.Check(loader, newTransfer(t, loader, from, to, 6, amount.NewCoinAmount(1, 0)), nil); errs.CodeOf(err) != CodeExceedSequenceWindow {
+		t.Fatalf("expected exceed sequence window, got %v", err)
+	}
+	tx := newTransfer(t, loader, from, to, 4, amount.NewCoinAmount(1, 0))
+	if err := g.Check(loader, tx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add(loader, tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Check(loader, newTransfer(t, loader, from, to, 4, amount.NewCoinAmount(2, 0)), nil); errs.CodeOf(err) != CodePendingSequence {
+		t.Fatalf("expected pending sequence, got %v", err)
+	}
+	if err := g.Check(loader, newTransfer(t, loader, from, to, 5, amount.NewCoinAmount(2, 0)), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckStateless(t *testing.T) {
+	loader := newTestLoader(t)
+	from := addAccount(t, loader, 1, amount.NewCoinAmount(1000, 0))
+	to := addAccount(t, loader, 2, amount.NewCoinAmount(0, 0))
+	g := NewGuard(0)
+
+	tx := newTransfer(t, loader, from, to, 1, amount.NewCoinAmount(0, 0))
+	if err := g.Check(loader, tx, nil); errs.CodeOf(err) != errs.CodeDustAmount {
+		t.Fatalf("expected dust amount, got %v", err)
+	}
+}
+
+func TestCheckBalance(t *testing.T) {
+	loader := newTestLoader(t)
+	to := addAccount(t, loader, 2, amount.NewCoinAmount(0, 0))
+	Fee, err := loader.Tran.Fee(newTransfer(t, loader, to, to, 1, amount.NewCoinAmount(1, 0)).Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// enough for two transfers of 4 coins
+	from := addAccount(t, loader, 1, amount.NewCoinAmount(8, 0).Add(Fee.MulC(2)))
+	g := NewGuard(0)
+
+	for seq := uint64(1); seq <= 2; seq++ {
+		tx := newTransfer(t, loader, from, to, seq, amount.NewCoinAmount(4, 0))
+		if err := g.Check(loader, tx, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Add(loader, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tx := newTransfer(t, loader, from, to, 3, amount.NewCoinAmount(4, 0))
+	err = g.Check(loader, tx, nil)
+	if errs.CodeOf(err) != errs.CodeInsufficientBalance {
+		t.Fatalf("expected insufficient balance, got %v", err)
+	}
+	var ce *errs.ContextError
+	if !errors.As(err, &ce) || ce.Field != "amount" {
+		t.Fatalf("expected the amount field, got %v", err)
+	}
+
+	// the first transfer is included and the balance is reduced by it
+	loader.Seqs[from] = 1
+	loader.Accounts[from].SubBalance(amount.NewCoinAmount(4, 0).Add(Fee))
+	g.Release(loader, &block.Block{
+		Header: block.Header{Height_: 2},
+		Body: block.Body{
+			Transactions: []transaction.Transaction{newTransfer(t, loader, from, to, 1, amount.NewCoinAmount(4, 0))},
+		},
+	})
+	if g.Len() != 1 {
+		t.Fatalf("expected 1 pending transaction, got %d", g.Len())
+	}
+	if err := g.Check(loader, tx, nil); errs.CodeOf(err) != errs.CodeInsufficientBalance {
+		t.Fatalf("expected insufficient balance, got %v", err)
+	}
+
+	// the pending transaction is forgotten after the timeout
+	g.Release(loader, &block.Block{
+		Header: block.Header{Height_: 1 + PendingTimeout},
+	})
+	if g.Len() != 0 {
+		t.Fatalf("expected no pending transaction, got %d", g.Len())
+	}
+	if err := g.Check(loader, newTransfer(t, loader, from, to, 2, amount.NewCoinAmount(4, 0)), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckCloseAccount(t *testing.T) {
+	loader := newTestLoader(t)
+	from := addAccount(t, loader, 1, amount.NewCoinAmount(1000, 0))
+	to := addAccount(t, loader, 2, amount.NewCoinAmount(0, 0))
+	g := NewGuard(0)
+
+	tx, err := loader.Tran.NewByTypeName("fleta.CloseAccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := tx.(*account_tx.CloseAccount)
+	ca.From_ = from
+	ca.Seq_ = 1
+	ca.To = to
+	if err := g.Check(loader, ca, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add(loader, ca); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Check(loader, newTransfer(t, loader, from, to, 2, amount.NewCoinAmount(1, 0)), nil); errs.CodeOf(err) != errs.CodeInsufficientBalance {
+		t.Fatalf("expected insufficient balance, got %v", err)
+	}
+}
+
+func newWithdraw(t *testing.T, loader *test_util.Loader, from common.Address, seq uint64, am *amount.Amount) *account_tx.Withdraw {
+	tx, err := loader.Tran.NewByTypeName("fleta.Withdraw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd := tx.(*account_tx.Withdraw)
+	wd.From_ = from
+	wd.Seq_ = seq
+	wd.Vout = []*transaction.TxOut{{Amount: am, PublicHash: test_util.PublicHash(1)}}
+	return wd
+}
+
+func TestCheckWithdraw(t *testing.T) {
+	loader := newTestLoader(t)
+	tx := newWithdraw(t, loader, test_util.Address(1), 1, amount.NewCoinAmount(3, 0))
+	Fee, err := loader.Tran.Fee(tx.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Fee, err = fee.Required(loader.Coord, Fee, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the executor subtracts the fee and the outputs with the fee
+	charge := Fee.Add(Spend(tx, Fee))
+	if !charge.Equal(amount.NewCoinAmount(3, 0).Add(Fee.MulC(2))) {
+		t.Fatalf("unexpected spend %v", charge)
+	}
+	from := addAccount(t, loader, 1, charge)
+	short := addAccount(t, loader, 2, charge.Sub(amount.NewCoinAmount(0, 1)))
+	g := NewGuard(0)
+
+	if err := g.Check(loader, newWithdraw(t, loader, short, 1, amount.NewCoinAmount(3, 0)), nil); errs.CodeOf(err) != errs.CodeInsufficientBalance {
+		t.Fatalf("expected insufficient balance, got %v", err)
+	}
+	if err := g.Check(loader, tx, nil); err != nil {
+		t.Fatal(err)
+	}
+	ctx := data.NewContext(loader)
+	if _, err := loader.Tran.Execute(ctx, tx, common.NewCoordinate(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	acc, err := ctx.Account(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acc.Balance().IsZero() {
+		t.Errorf("expected the whole balance to be spent but %v remains", acc.Balance())
+	}
+}
+
+func TestCheckUTXO(t *testing.T) {
+	loader := newTestLoader(t)
+	k, err := key.NewMemoryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := common.NewPublicHash(k.PublicKey())
+	newTx := func(name string, vins []uint64, vouts ...*amount.Amount) transaction.Transaction {
+		tx, err := loader.Tran.NewByTypeName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ins := []*transaction.TxIn{}
+		for _, id := range vins {
+			ins = append(ins, transaction.NewTxIn(id))
+		}
+		outs := []*transaction.TxOut{}
+		for _, am := range vouts {
+			outs = append(outs, &transaction.TxOut{Amount: am, PublicHash: signer})
+		}
+		switch tx := tx.(type) {
+		case *utxo_tx.Assign:
+			tx.Vin = ins
+			tx.Vout = outs
+		case *utxo_tx.Deposit:
+			tx.Vin = ins
+			tx.Vout = outs
+			tx.Amount = amount.NewCoinAmount(2, 0)
+		}
+		return tx
+	}
+	check := func(g *Guard, tx transaction.Transaction) error {
+		s, err := tx_builder.Sign(tx, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g.Check(loader, tx, s.Sigs)
+	}
+	Fee, err := requiredFee(loader, newTx("fleta.Assign", []uint64{1}, amount.NewCoinAmount(3, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader.AddUTXO(1, &transaction.TxOut{Amount: amount.NewCoinAmount(3, 0).Add(Fee), PublicHash: signer})
+	loader.AddUTXO(2, &transaction.TxOut{Amount: amount.NewCoinAmount(1, 0), PublicHash: signer})
+	g := NewGuard(0)
+
+	if err := check(g, newTx("fleta.Assign", []uint64{1}, amount.NewCoinAmount(4, 0))); !errors.Is(err, ErrInvalidOutputAmount) {
+		t.Errorf("expected %v but %v", ErrInvalidOutputAmount, err)
+	}
+	if err := check(g, newTx("fleta.Assign", []uint64{1}, amount.NewCoinAmount(1, 0))); !errors.Is(err, ErrInvalidOutputAmount) {
+		t.Errorf("expected %v but %v", ErrInvalidOutputAmount, err)
+	}
+	if err := check(g, newTx("fleta.Assign", []uint64{3}, amount.NewCoinAmount(1, 0))); err == nil {
+		t.Error("expected the unknown utxo is rejected")
+	}
+	tx := newTx("fleta.Assign", []uint64{1}, amount.NewCoinAmount(3, 0))
+	if err := check(g, tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the amount of the deposit should also be covered
+	if err := check(g, newTx("fleta.Deposit", []uint64{1}, amount.NewCoinAmount(3, 0))); !errors.Is(err, ErrInvalidOutputAmount) {
+		t.Errorf("expected %v but %v", ErrInvalidOutputAmount, err)
+	}
+
+	if err := g.Add(loader, tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := check(g, newTx("fleta.Assign", []uint64{1}, amount.NewCoinAmount(3, 0))); !errors.Is(err, ErrPendingUTXO) {
+		t.Errorf("expected %v but %v", ErrPendingUTXO, err)
+	}
+}
+
+type testRewarder struct{}
+
+func (rd *testRewarder) ProcessReward(addr common.Address, ctx *data.Context) error {
+	return nil
+}
+
+func TestAfterPushTransaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pool_guard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	from := test_util.Address(1)
+	kn, err := chainkit.NewKernel(&chainkit.Config{
+		ChainCoord: common.NewCoordinate(0, 0),
+		Version:    1,
+		StoreRoot:  dir,
+		Rewarder:   &testRewarder{},
+		Transactions: []chainkit.TransactionType{
+			{Name: "fleta.Transfer", Type: 10, Fee: amount.COIN.DivC(10)},
+		},
+		Accounts: []chainkit.AccountType{
+			{Name: "fleta.SingleAccount", Type: 10},
+		},
+		GenesisAccounts: []chainkit.GenesisAccount{
+			{Type: "fleta.SingleAccount", Address: from, Name: "genesisaccount", Balance: amount.NewCoinAmount(100, 0)},
+		},
+	}, dir, map[common.PublicHash]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kn.Close()
+	g := NewGuard(0)
+
+	newTx := func(seq uint64) transaction.Transaction {
+		tx, err := kn.Loader().Transactor().NewByTypeName("fleta.Transfer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr := tx.(*account_tx.Transfer)
+		tr.From_ = from
+		tr.Seq_ = seq
+		tr.Amount = amount.NewCoinAmount(1, 0)
+		tr.To = account_name.Destination{Address: test_util.Address(2)}
+		return tr
+	}
+	g.AfterPushTransaction(kn, newTx(0), nil)
+	if errs.CodeOf(g.Err()) != errs.CodeInvalidSequence {
+		t.Fatalf("expected invalid sequence, got %v", g.Err())
+	}
+	g.AfterPushTransaction(kn, newTx(1), nil)
+	if err := g.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if g.Len() != 1 {
+		t.Fatalf("expected 1 pending transaction, got %d", g.Len())
+	}
+}
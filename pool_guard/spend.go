@@ -0,0 +1,60 @@
+package pool_guard
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/token_tx"
+	"github.com/fletaio/extension/utxo_tx"
+)
+
+type accountTransaction interface {
+	From() common.Address
+	Seq() uint64
+}
+
+type utxoTransaction interface {
+	VinIDs() []uint64
+}
+
+// Spend returns the amount that is subtracted from the balance of the sender by the transaction except the fee
+// It returns zero for the transactions that only pay the fee
+// A fleta.Withdraw subtracts the fee again with its outputs, so its spend is the charge of the executor that includes the fee
+// The whole balance that is moved by a fleta.CloseAccount is not known before the execution, so it is handled by the guard
+func Spend(tx transaction.Transaction, Fee *amount.Amount) *amount.Amount {
+	switch tx := tx.(type) {
+	case *account_tx.Transfer:
+		return tx.Amount.Clone()
+	case *account_tx.Burn:
+		return tx.Amount.Clone()
+	case *account_tx.Withdraw:
+		return tx.Charge(Fee)
+	case *token_tx.TokenIssue:
+		return tx.Amount.Clone()
+	default:
+		return amount.NewCoinAmount(0, 0)
+	}
+}
+
+// Output returns the amount that should be covered by the UTXOs of the transaction except the fee
+// It returns false for the transactions that are not known to spend UTXOs
+func Output(tx transaction.Transaction) (*amount.Amount, bool) {
+	switch tx := tx.(type) {
+	case *utxo_tx.Assign:
+		return sumOf(tx.Vout, amount.NewCoinAmount(0, 0)), true
+	case *utxo_tx.Deposit:
+		return sumOf(tx.Vout, tx.Amount.Clone()), true
+	case *utxo_tx.OpenAccount:
+		return sumOf(tx.Vout, amount.NewCoinAmount(0, 0)), true
+	default:
+		return nil, false
+	}
+}
+
+func sumOf(vouts []*transaction.TxOut, sum *amount.Amount) *amount.Amount {
+	for _, vout := range vouts {
+		sum = sum.Add(vout.Amount)
+	}
+	return sum
+}
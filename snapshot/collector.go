@@ -0,0 +1,143 @@
+package snapshot
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/message_def"
+	"github.com/fletaio/core/transaction"
+)
+
+// Collector tracks the addresses of the accounts, the keys of the account data and the ids of the UTXOs that exist after the processed blocks
+// It is added to the kernel as an event handler and its lists are given to Export
+type Collector struct {
+	sync.Mutex
+	addrMap map[common.Address]bool
+	keyMap  map[string]bool
+	idMap   map[uint64]bool
+}
+
+// NewCollector returns a Collector
+func NewCollector() *Collector {
+	return &Collector{
+		addrMap: map[common.Address]bool{},
+		keyMap:  map[string]bool{},
+		idMap:   map[uint64]bool{},
+	}
+}
+
+// AddContextData applies the accounts and the UTXOs that are created or deleted by the context data
+// It is used to apply the genesis context data that is not processed as a block
+func (c *Collector) AddContextData(ctd *data.ContextData) {
+	c.Lock()
+	defer c.Unlock()
+
+	for addr := range ctd.AccountMap {
+		c.addrMap[addr] = true
+	}
+	for addr := range ctd.CreatedAccountMap {
+		c.addrMap[addr] = true
+	}
+	for addr := range ctd.DeletedAccountMap {
+		delete(c.addrMap, addr)
+	}
+	for key := range ctd.AccountDataMap {
+		c.keyMap[key] = true
+	}
+	for key := range ctd.DeletedAccountDataMap {
+		delete(c.keyMap, key)
+	}
+	for id := range ctd.CreatedUTXOMap {
+		c.idMap[id] = true
+	}
+	for id := range ctd.DeletedUTXOMap {
+		delete(c.idMap, id)
+	}
+}
+
+// Addresses returns the addresses of the accounts in the order of the addresses
+func (c *Collector) Addresses() []common.Address {
+	c.Lock()
+	defer c.Unlock()
+
+	addrs := make([]common.Address, 0, len(c.addrMap))
+	for addr := range c.addrMap {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	return addrs
+}
+
+// DataKeys returns the keys of the account data in the order of the addresses and the names
+func (c *Collector) DataKeys() []DataKey {
+	c.Lock()
+	defer c.Unlock()
+
+	strs := make([]string, 0, len(c.keyMap))
+	for key := range c.keyMap {
+		strs = append(strs, key)
+	}
+	sort.Strings(strs)
+	keys := make([]DataKey, 0, len(strs))
+	for _, str := range strs {
+		var k DataKey
+		copy(k.Address[:], str)
+		k.Name = []byte(str[len(k.Address):])
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// UTXOs returns the ids of the UTXOs in the order of the ids
+func (c *Collector) UTXOs() []uint64 {
+	c.Lock()
+	defer c.Unlock()
+
+	ids := make([]uint64, 0, len(c.idMap))
+	for id := range c.idMap {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// Export returns the snapshot of the collected accounts, account data and UTXOs
+func (c *Collector) Export(loader data.Loader) (*Snapshot, error) {
+	return Export(loader, c.Addresses(), c.DataKeys(), c.UTXOs())
+}
+
+// AfterProcessBlock called when processed block to the chain
+func (c *Collector) AfterProcessBlock(kn *kernel.Kernel, b *block.Block, s *block.ObserverSigned, ctx *data.Context) {
+	c.AddContextData(ctx.Top())
+}
+
+// OnProcessBlock called when processing a block to the chain (error prevent processing block)
+func (c *Collector) OnProcessBlock(kn *kernel.Kernel, b *block.Block, s *block.ObserverSigned, ctx *data.Context) error {
+	return nil
+}
+
+// OnPushTransaction called when pushing a transaction to the transaction pool (error prevent push transaction)
+func (c *Collector) OnPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) error {
+	return nil
+}
+
+// AfterPushTransaction called when pushed a transaction to the transaction pool
+func (c *Collector) AfterPushTransaction(kn *kernel.Kernel, tx transaction.Transaction, sigs []common.Signature) {
+}
+
+// DoTransactionBroadcast called when a transaction need to be broadcast
+func (c *Collector) DoTransactionBroadcast(kn *kernel.Kernel, msg *message_def.TransactionMessage) {
+}
+
+// DebugLog TEMP
+func (c *Collector) DebugLog(kn *kernel.Kernel, args ...interface{}) {
+}
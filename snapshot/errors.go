@@ -0,0 +1,16 @@
+package snapshot
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// snapshot error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeInvalidSnapshot errs.Code = 2201
+)
+
+// snapshot errors
+var (
+	ErrInvalidSnapshot = errs.New(CodeInvalidSnapshot, "invalid snapshot")
+)
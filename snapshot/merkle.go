@@ -0,0 +1,31 @@
+package snapshot
+
+import (
+	"github.com/fletaio/common/hash"
+)
+
+// MerkleRoot returns the root of the binary merkle tree of the leaves
+// The last node of a level is paired with itself when the level has an odd number of nodes and the root of no leaf is the zero hash
+func MerkleRoot(leaves []hash.Hash256) hash.Hash256 {
+	if len(leaves) == 0 {
+		return hash.Hash256{}
+	}
+	level := make([]hash.Hash256, len(leaves))
+	copy(level, leaves)
+	for len(level) > 1 {
+		next := make([]hash.Hash256, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			left := level[i]
+			right := left
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			bs := make([]byte, 0, len(left)+len(right))
+			bs = append(bs, left[:]...)
+			bs = append(bs, right[:]...)
+			next = append(next, hash.DoubleHash(bs))
+		}
+		level = next
+	}
+	return level[0]
+}
@@ -0,0 +1,586 @@
+// Package snapshot exports the extension accounts, account names, sequences, account data and UTXOs of a chain to a portable file and imports it as a new genesis.
+//
+// The loader cannot list the state, so the addresses, the UTXO ids and the keys of the account data are given by the caller or tracked by a Collector from the processed blocks.
+// The account data keeps the spending policies of the accounts, and the name registry and the standing orders that are kept on the zero address.
+// The UTXOs get new ids at the genesis height when they are imported, so the ids of the source chain are mapped by UTXOIDMap.
+// A snapshot has the merkle root of its entries, and the root is checked when it is read so a modified or truncated file is rejected.
+package snapshot
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/errs"
+)
+
+// Version is the version of the snapshot file format
+const Version = 2
+
+// AccountEntry is the account, its sequence and the names transferred to it
+type AccountEntry struct {
+	TypeName string // the registered name of the account type so the snapshot does not depend on the type ids
+	Seq      uint64
+	Names    []string
+	Account  account.Account
+}
+
+// WriteTo is a serialization function
+func (e *AccountEntry) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteString(w, e.TypeName); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint64(w, e.Seq); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, uint32(len(e.Names))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, name := range e.Names {
+		if n, err := util.WriteString(w, name); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	if n, err := e.Account.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// UTXOEntry is the unspent output of the id
+type UTXOEntry struct {
+	ID uint64
+	*transaction.TxOut
+}
+
+// WriteTo is a serialization function
+func (e *UTXOEntry) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint64(w, e.ID); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := e.TxOut.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// DataKey is the address and the name of the account data
+type DataKey struct {
+	Address common.Address
+	Name    []byte
+}
+
+// DataEntry is the account data of the address and the name
+type DataEntry struct {
+	DataKey
+	Value []byte
+}
+
+// WriteTo is a serialization function
+func (e *DataEntry) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := e.Address.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteBytes(w, e.Name); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteBytes(w, e.Value); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// Snapshot is the state of the extension accounts, account data and UTXOs of the chain at the height
+type Snapshot struct {
+	ChainCoord *common.Coordinate
+	Height     uint32
+	Accounts   []*AccountEntry
+	Data       []*DataEntry
+	UTXOs      []*UTXOEntry
+}
+
+// Export returns the snapshot of the accounts of the addresses, the account data of the keys and the UTXOs of the ids
+// The addresses of deleted accounts, the empty account data and the ids of spent UTXOs are skipped
+// The account data of the deleted accounts are skipped too, but the zero address is not an account and its data are exported
+func Export(loader data.Loader, addrs []common.Address, keys []DataKey, ids []uint64) (*Snapshot, error) {
+	s := &Snapshot{
+		ChainCoord: loader.ChainCoord(),
+		Height:     loader.TargetHeight(),
+		Accounts:   []*AccountEntry{},
+		Data:       []*DataEntry{},
+		UTXOs:      []*UTXOEntry{},
+	}
+
+	addrMap := map[common.Address]bool{}
+	for _, addr := range addrs {
+		if addrMap[addr] {
+			continue
+		}
+		addrMap[addr] = true
+		if is, err := loader.IsExistAccount(addr); err != nil {
+			return nil, err
+		} else if !is {
+			continue
+		}
+		acc, err := loader.Account(addr)
+		if err != nil {
+			return nil, err
+		}
+		TypeName, err := loader.Accounter().NameByType(acc.Type())
+		if err != nil {
+			return nil, err
+		}
+		names, err := account_name.TransferredNames(loader, addr)
+		if err != nil {
+			return nil, err
+		}
+		s.Accounts = append(s.Accounts, &AccountEntry{
+			TypeName: TypeName,
+			Seq:      loader.Seq(addr),
+			Names:    names,
+			Account:  acc.Clone(),
+		})
+	}
+
+	keyMap := map[string]bool{}
+	for _, k := range keys {
+		key := toAccountDataKey(k.Address, k.Name)
+		if keyMap[key] {
+			continue
+		}
+		keyMap[key] = true
+		if k.Address != (common.Address{}) {
+			if is, err := loader.IsExistAccount(k.Address); err != nil {
+				return nil, err
+			} else if !is {
+				continue
+			}
+		}
+		value := loader.AccountData(k.Address, k.Name)
+		if len(value) == 0 {
+			continue
+		}
+		s.Data = append(s.Data, &DataEntry{
+			DataKey: DataKey{
+				Address: k.Address,
+				Name:    append([]byte{}, k.Name...),
+			},
+			Value: append([]byte{}, value...),
+		})
+	}
+
+	idMap := map[uint64]bool{}
+	for _, id := range ids {
+		if idMap[id] {
+			continue
+		}
+		idMap[id] = true
+		if is, err := loader.IsExistUTXO(id); err != nil {
+			return nil, err
+		} else if !is {
+			continue
+		}
+		utxo, err := loader.UTXO(id)
+		if err != nil {
+			return nil, err
+		}
+		s.UTXOs = append(s.UTXOs, &UTXOEntry{
+			ID:    id,
+			TxOut: utxo.TxOut.Clone(),
+		})
+	}
+	s.sort()
+	return s, nil
+}
+
+func (s *Snapshot) sort() {
+	sort.Slice(s.Accounts, func(i, j int) bool {
+		a, b := s.Accounts[i].Account.Address(), s.Accounts[j].Account.Address()
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	sort.Slice(s.Data, func(i, j int) bool {
+		a, b := s.Data[i], s.Data[j]
+		if c := bytes.Compare(a.Address[:], b.Address[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(a.Name, b.Name) < 0
+	})
+	sort.Slice(s.UTXOs, func(i, j int) bool {
+		return s.UTXOs[i].ID < s.UTXOs[j].ID
+	})
+}
+
+// Root returns the merkle root of the account entries followed by the data entries and the UTXO entries
+func (s *Snapshot) Root() hash.Hash256 {
+	leaves := make([]hash.Hash256, 0, len(s.Accounts)+len(s.Data)+len(s.UTXOs))
+	for _, e := range s.Accounts {
+		leaves = append(leaves, hash.DoubleHashByWriterTo(e))
+	}
+	for _, e := range s.Data {
+		leaves = append(leaves, hash.DoubleHashByWriterTo(e))
+	}
+	for _, e := range s.UTXOs {
+		leaves = append(leaves, hash.DoubleHashByWriterTo(e))
+	}
+	return MerkleRoot(leaves)
+}
+
+// WriteTo is a serialization function
+func (s *Snapshot) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint8(w, Version); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := s.ChainCoord.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, s.Height); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, uint32(len(s.Accounts))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, e := range s.Accounts {
+		if n, err := e.WriteTo(w); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	if n, err := util.WriteUint32(w, uint32(len(s.Data))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, e := range s.Data {
+		if n, err := e.WriteTo(w); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	if n, err := util.WriteUint32(w, uint32(len(s.UTXOs))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, e := range s.UTXOs {
+		if n, err := e.WriteTo(w); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	root := s.Root()
+	if n, err := root.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadSnapshot returns the snapshot that is written by WriteTo
+// The accounts are made by the types of the accounter and the merkle root is checked
+// The accounts keep the type ids of the source chain until ContextData gives them the type ids of the accounter
+func ReadSnapshot(act *data.Accounter, r io.Reader) (*Snapshot, int64, error) {
+	var read int64
+	s := &Snapshot{
+		ChainCoord: &common.Coordinate{},
+	}
+	if v, n, err := util.ReadUint8(r); err != nil {
+		return nil, read, err
+	} else if v != Version {
+		return nil, read, errs.WrapField(ErrInvalidSnapshot, "version", Version, v)
+	} else {
+		read += n
+	}
+	if n, err := s.ChainCoord.ReadFrom(r); err != nil {
+		return nil, read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return nil, read, err
+	} else {
+		read += n
+		s.Height = v
+	}
+
+	AccountCount, n, err := util.ReadUint32(r)
+	if err != nil {
+		return nil, read, err
+	}
+	read += n
+	s.Accounts = make([]*AccountEntry, 0, minCount(AccountCount))
+	for i := uint32(0); i < AccountCount; i++ {
+		e, n, err := readAccountEntry(act, r)
+		if err != nil {
+			return nil, read, err
+		}
+		read += n
+		s.Accounts = append(s.Accounts, e)
+	}
+
+	DataCount, n, err := util.ReadUint32(r)
+	if err != nil {
+		return nil, read, err
+	}
+	read += n
+	s.Data = make([]*DataEntry, 0, minCount(DataCount))
+	for i := uint32(0); i < DataCount; i++ {
+		e := &DataEntry{}
+		if n, err := e.Address.ReadFrom(r); err != nil {
+			return nil, read, err
+		} else {
+			read += n
+		}
+		if v, n, err := util.ReadBytes(r); err != nil {
+			return nil, read, err
+		} else {
+			read += n
+			e.Name = v
+		}
+		if v, n, err := util.ReadBytes(r); err != nil {
+			return nil, read, err
+		} else {
+			read += n
+			e.Value = v
+		}
+		s.Data = append(s.Data, e)
+	}
+
+	UTXOCount, n, err := util.ReadUint32(r)
+	if err != nil {
+		return nil, read, err
+	}
+	read += n
+	s.UTXOs = make([]*UTXOEntry, 0, minCount(UTXOCount))
+	for i := uint32(0); i < UTXOCount; i++ {
+		e := &UTXOEntry{
+			TxOut: transaction.NewTxOut(),
+		}
+		if v, n, err := util.ReadUint64(r); err != nil {
+			return nil, read, err
+		} else {
+			read += n
+			e.ID = v
+		}
+		if n, err := e.TxOut.ReadFrom(r); err != nil {
+			return nil, read, err
+		} else {
+			read += n
+		}
+		s.UTXOs = append(s.UTXOs, e)
+	}
+
+	var root hash.Hash256
+	if n, err := root.ReadFrom(r); err != nil {
+		return nil, read, err
+	} else {
+		read += n
+	}
+	if actual := s.Root(); root != actual {
+		return nil, read, errs.WrapField(ErrInvalidSnapshot, "root", root, actual)
+	}
+	return s, read, nil
+}
+
+func readAccountEntry(act *data.Accounter, r io.Reader) (*AccountEntry, int64, error) {
+	var read int64
+	e := &AccountEntry{}
+	if v, n, err := util.ReadString(r); err != nil {
+		return nil, read, err
+	} else {
+		read += n
+		e.TypeName = v
+	}
+	if v, n, err := util.ReadUint64(r); err != nil {
+		return nil, read, err
+	} else {
+		read += n
+		e.Seq = v
+	}
+	NameCount, n, err := util.ReadUint32(r)
+	if err != nil {
+		return nil, read, err
+	}
+	read += n
+	e.Names = make([]string, 0, minCount(NameCount))
+	for i := uint32(0); i < NameCount; i++ {
+		if v, n, err := util.ReadString(r); err != nil {
+			return nil, read, err
+		} else {
+			read += n
+			e.Names = append(e.Names, v)
+		}
+	}
+	acc, err := act.NewByTypeName(e.TypeName)
+	if err != nil {
+		return nil, read, errs.WrapField(ErrInvalidSnapshot, "type_name", nil, e.TypeName)
+	}
+	if n, err := acc.ReadFrom(r); err != nil {
+		return nil, read, err
+	} else {
+		read += n
+	}
+	e.Account = acc
+	return e, read, nil
+}
+
+// minCount limits the capacity that is allocated by the count of the file before the entries are read
+func minCount(count uint32) uint32 {
+	if count > 1024 {
+		return 1024
+	}
+	return count
+}
+
+// WriteFile writes the snapshot to the file
+func (s *Snapshot) WriteFile(path string) error {
+	var buffer bytes.Buffer
+	if _, err := s.WriteTo(&buffer); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+// ReadFile returns the snapshot of the file
+// All bytes of the file should be the snapshot
+func ReadFile(act *data.Accounter, path string) (*Snapshot, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, n, err := ReadSnapshot(act, bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+	if n != int64(len(bs)) {
+		return nil, errs.WrapField(ErrInvalidSnapshot, "length", n, len(bs))
+	}
+	return s, nil
+}
+
+// GenesisUTXOID returns the id of the UTXO at the position in the order of the ids when it is imported as a new genesis
+// The genesis has no transaction, so the position is spread over the transaction indexes and the outputs of the genesis height
+func GenesisUTXOID(i int) uint64 {
+	return transaction.MarshalID(0, uint16(i>>16), uint16(i))
+}
+
+// UTXOIDMap returns the ids of the UTXOs in the new genesis by their ids in the source chain
+func (s *Snapshot) UTXOIDMap() map[uint64]uint64 {
+	m := make(map[uint64]uint64, len(s.UTXOs))
+	for i, e := range s.UTXOs {
+		m[e.ID] = GenesisUTXOID(i)
+	}
+	return m
+}
+
+// ContextData returns the context data that creates the state of the snapshot as a new genesis
+// The accounts get the type ids of the accounter by their type names and the UTXOs get the ids of GenesisUTXOID
+func (s *Snapshot) ContextData(act *data.Accounter, tran *data.Transactor, evt *data.Eventer) (*data.ContextData, error) {
+	loader := data.NewEmptyLoader(act.ChainCoord(), act, tran, evt)
+	ctd := data.NewContextData(loader, nil)
+	for _, e := range s.Accounts {
+		acc, err := retype(act, e.TypeName, e.Account)
+		if err != nil {
+			return nil, err
+		}
+		addr := acc.Address()
+		ctd.CreatedAccountMap[addr] = acc
+		if e.Seq > 0 {
+			ctd.SeqMap[addr] = e.Seq
+		}
+		registry, m, err := account_name.RegistryData(addr, e.Names)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range m {
+			ctd.AccountDataMap[toAccountDataKey(registry, []byte(name))] = value
+		}
+	}
+	for _, e := range s.Data {
+		ctd.AccountDataMap[toAccountDataKey(e.Address, e.Name)] = append([]byte{}, e.Value...)
+	}
+	for i, e := range s.UTXOs {
+		ctd.CreatedUTXOMap[GenesisUTXOID(i)] = e.TxOut.Clone()
+	}
+	return ctd, nil
+}
+
+// retype returns the copy of the account that has the type id of the type name in the accounter
+// The type id is the first byte of the serialized account, so the account is read again after the byte is replaced
+func retype(act *data.Accounter, TypeName string, acc account.Account) (account.Account, error) {
+	t, err := act.TypeByName(TypeName)
+	if err != nil {
+		return nil, errs.WrapField(ErrInvalidSnapshot, "type_name", nil, TypeName)
+	}
+	if acc.Type() == t {
+		return acc.Clone(), nil
+	}
+	var buffer bytes.Buffer
+	if _, err := acc.WriteTo(&buffer); err != nil {
+		return nil, err
+	}
+	bs := buffer.Bytes()
+	if len(bs) == 0 || bs[0] != byte(acc.Type()) {
+		return nil, errs.WrapField(ErrInvalidSnapshot, "type", acc.Type(), bs)
+	}
+	bs[0] = byte(t)
+	c, err := act.NewByTypeName(TypeName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.ReadFrom(bytes.NewReader(bs)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// toAccountDataKey returns the key of the account data in the context data
+func toAccountDataKey(addr common.Address, name []byte) string {
+	bs := make([]byte, len(addr)+len(name))
+	copy(bs, addr[:])
+	copy(bs[len(addr):], name)
+	return string(bs)
+}
@@ -0,0 +1,287 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/internal/test_util"
+	"github.com/fletaio/extension/type_registry"
+)
+
+func newTestLoader(t *testing.T) *test_util.Loader {
+	loader := test_util.NewLoader(common.NewCoordinate(0, 0))
+	loader.Height = 100
+	if err := type_registry.Register(loader.Act, loader.Tran, loader.Evt); err != nil {
+		t.Fatal(err)
+	}
+	return loader
+}
+
+func addAccount(t *testing.T, loader *test_util.Loader, n uint16, name string, balance uint64, seq uint64) common.Address {
+	a, err := loader.Act.NewByTypeName("fleta.SingleAccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := a.(*account_def.SingleAccount)
+	acc.Address_ = common.NewAddress(common.NewCoordinate(0, n), 0)
+	acc.Name_ = name
+	acc.Balance_ = amount.NewCoinAmount(balance, 0)
+	acc.KeyHash[0] = byte(n)
+	loader.AddAccount(acc, seq)
+	return acc.Address_
+}
+
+func addUTXO(loader *test_util.Loader, id uint64, am uint64) {
+	var pubhash common.PublicHash
+	pubhash[0] = byte(id)
+	loader.AddUTXO(id, &transaction.TxOut{
+		Amount:     amount.NewCoinAmount(am, 0),
+		PublicHash: pubhash,
+	})
+}
+
+func testSnapshot(t *testing.T) (*test_util.Loader, *Snapshot, []common.Address) {
+	loader := newTestLoader(t)
+	alice := addAccount(t, loader, 2, "aliceaccount", 100, 3)
+	bob := addAccount(t, loader, 1, "bobaccount", 50, 0)
+	registry, m, err := account_name.RegistryData(alice, []string{"transferred"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range m {
+		loader.SetAccountData(registry, []byte(name), value)
+	}
+	addUTXO(loader, transaction.MarshalID(10, 1, 0), 7)
+	addUTXO(loader, transaction.MarshalID(5, 0, 1), 9)
+
+	deleted := common.NewAddress(common.NewCoordinate(0, 9), 0)
+	keys := []DataKey{
+		{Address: alice, Name: []byte("fleta.Policy")},
+		{Address: alice, Name: []byte("fleta.PendingPolicy")},
+		{Address: bob, Name: []byte("fleta.PolicySpent")},
+		{Address: common.Address{}, Name: []byte("standing_order.order.1")},
+		{Address: deleted, Name: []byte("fleta.Policy")},
+		{Address: bob, Name: []byte("empty")},
+	}
+	for _, k := range keys[:5] {
+		loader.SetAccountData(k.Address, k.Name, append([]byte("value of "), k.Name...))
+	}
+	for name := range m {
+		keys = append(keys, DataKey{Address: registry, Name: []byte(name)})
+	}
+	s, err := Export(loader, []common.Address{alice, bob, deleted, alice}, keys, []uint64{transaction.MarshalID(10, 1, 0), transaction.MarshalID(5, 0, 1), transaction.MarshalID(1, 1, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loader, s, []common.Address{alice, bob}
+}
+
+func TestExport(t *testing.T) {
+	_, s, addrs := testSnapshot(t)
+	if s.Height != 100 || len(s.Accounts) != 2 || len(s.UTXOs) != 2 {
+		t.Fatalf("unexpected snapshot %d %d %d", s.Height, len(s.Accounts), len(s.UTXOs))
+	}
+	if s.Accounts[0].Account.Address() != addrs[1] || s.Accounts[1].Account.Address() != addrs[0] {
+		t.Error("the accounts are not sorted by their addresses")
+	}
+	if e := s.Accounts[1]; e.TypeName != "fleta.SingleAccount" || e.Seq != 3 || len(e.Names) != 1 || e.Names[0] != "transferred" {
+		t.Errorf("unexpected account entry %+v", e)
+	}
+	if s.UTXOs[0].ID != transaction.MarshalID(5, 0, 1) || !s.UTXOs[0].Amount.Equal(amount.NewCoinAmount(9, 0)) {
+		t.Errorf("unexpected utxo entry %d %v", s.UTXOs[0].ID, s.UTXOs[0].Amount)
+	}
+	// the data of the deleted account and the empty data are skipped
+	if len(s.Data) != 6 {
+		t.Fatalf("expected 6 data entries but %d", len(s.Data))
+	}
+	// the data of the zero address that keeps the registry and the standing orders are followed by the ones of the accounts
+	if e := s.Data[2]; e.Address != (common.Address{}) || string(e.Name) != "standing_order.order.1" {
+		t.Errorf("unexpected data entry %v %s", e.Address, e.Name)
+	}
+	if e := s.Data[3]; e.Address != addrs[1] || string(e.Value) != "value of fleta.PolicySpent" {
+		t.Errorf("unexpected data entry %v %s", e.Address, e.Name)
+	}
+	if e := s.Data[4]; e.Address != addrs[0] || string(e.Name) != "fleta.PendingPolicy" {
+		t.Errorf("the data entries are not sorted by their addresses and names %v %s", e.Address, e.Name)
+	}
+}
+
+func TestReadSnapshot(t *testing.T) {
+	loader, s, _ := testSnapshot(t)
+	var buffer bytes.Buffer
+	if _, err := s.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	bs := buffer.Bytes()
+
+	s2, n, err := ReadSnapshot(loader.Act, bytes.NewReader(bs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(bs)) {
+		t.Errorf("expected %d bytes but %d", len(bs), n)
+	}
+	if s2.Root() != s.Root() || !s2.ChainCoord.Equal(s.ChainCoord) || s2.Height != s.Height {
+		t.Error("the snapshot is changed by the serialization")
+	}
+	if s2.Accounts[1].Account.(*account_def.SingleAccount).KeyHash != s.Accounts[1].Account.(*account_def.SingleAccount).KeyHash {
+		t.Error("the account is changed by the serialization")
+	}
+
+	tampered := make([]byte, len(bs))
+	copy(tampered, bs)
+	tampered[len(tampered)-40] ^= 1
+	if _, _, err := ReadSnapshot(loader.Act, bytes.NewReader(tampered)); errs.CodeOf(err) != CodeInvalidSnapshot {
+		t.Errorf("expected the invalid snapshot but %v", err)
+	}
+
+	if len(s2.Data) != len(s.Data) || !bytes.Equal(s2.Data[0].Value, s.Data[0].Value) {
+		t.Error("the account data are changed by the serialization")
+	}
+
+	// the accounts get the type ids of the other chain by their type names
+	other := data.NewAccounter(loader.Coord)
+	if err := other.RegisterType("fleta.SingleAccount", 15); err != nil {
+		t.Fatal(err)
+	}
+	s3, _, err := ReadSnapshot(other, bytes.NewReader(bs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctd, err := s3.ContextData(other, loader.Tran, loader.Evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range s.Accounts {
+		acc := ctd.CreatedAccountMap[e.Account.Address()].(*account_def.SingleAccount)
+		if acc.Type() != 15 || acc.KeyHash != e.Account.(*account_def.SingleAccount).KeyHash || acc.Name() != e.Account.Name() || !acc.Balance().Equal(e.Account.Balance()) {
+			t.Errorf("unexpected account of the other chain %+v", acc)
+		}
+	}
+	unknown := data.NewAccounter(loader.Coord)
+	s4, _, err := ReadSnapshot(loader.Act, bytes.NewReader(bs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s4.ContextData(unknown, loader.Tran, loader.Evt)
+	var cerr *errs.ContextError
+	if !errors.As(err, &cerr) || cerr.Field != "type_name" {
+		t.Errorf("expected the unknown type name but %v", err)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	loader, s, _ := testSnapshot(t)
+	path := filepath.Join(t.TempDir(), "snapshot")
+	if err := s.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm()&0133 != 0 {
+		t.Errorf("unexpected mode %v", info.Mode())
+	}
+	s2, err := ReadFile(loader.Act, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s2.Root() != s.Root() {
+		t.Error("the snapshot is changed by the file")
+	}
+}
+
+func TestContextData(t *testing.T) {
+	loader, s, addrs := testSnapshot(t)
+	ctd, err := s.ContextData(loader.Act, loader.Tran, loader.Evt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ctd.CreatedAccountMap) != 2 || len(ctd.CreatedUTXOMap) != 2 {
+		t.Fatalf("unexpected context data %d %d", len(ctd.CreatedAccountMap), len(ctd.CreatedUTXOMap))
+	}
+	// the UTXOs get the ids of the genesis height in the order of their ids
+	m := s.UTXOIDMap()
+	if m[transaction.MarshalID(5, 0, 1)] != transaction.MarshalID(0, 0, 0) || m[transaction.MarshalID(10, 1, 0)] != transaction.MarshalID(0, 0, 1) {
+		t.Errorf("unexpected utxo id map %v", m)
+	}
+	for old, id := range m {
+		if vout, has := ctd.CreatedUTXOMap[id]; !has || !vout.Amount.Equal(loader.UTXOs[old].Amount) {
+			t.Errorf("unexpected utxo %d of %d", id, old)
+		}
+	}
+	if GenesisUTXOID(65537) != transaction.MarshalID(0, 1, 1) {
+		t.Errorf("unexpected genesis utxo id %d", GenesisUTXOID(65537))
+	}
+	if ctd.SeqMap[addrs[0]] != 3 {
+		t.Errorf("expected the sequence 3 but %d", ctd.SeqMap[addrs[0]])
+	}
+	for k, v := range ctd.AccountDataMap {
+		if !bytes.Equal(loader.Data[k], v) {
+			t.Errorf("unexpected account data of %x", k)
+		}
+	}
+	// the data of the deleted account is not imported
+	if len(ctd.AccountDataMap) != len(loader.Data)-1 {
+		t.Errorf("expected %d account data but %d", len(loader.Data)-1, len(ctd.AccountDataMap))
+	}
+}
+
+func TestMerkleRoot(t *testing.T) {
+	if MerkleRoot(nil) != (hash.Hash256{}) {
+		t.Error("the root of no leaf should be the zero hash")
+	}
+	a, b, c := hash.Hash([]byte("a")), hash.Hash([]byte("b")), hash.Hash([]byte("c"))
+	if MerkleRoot([]hash.Hash256{a}) != a {
+		t.Error("the root of a leaf should be the leaf")
+	}
+	pair := func(l hash.Hash256, r hash.Hash256) hash.Hash256 {
+		return hash.DoubleHash(append(append([]byte{}, l[:]...), r[:]...))
+	}
+	if MerkleRoot([]hash.Hash256{a, b, c}) != pair(pair(a, b), pair(c, c)) {
+		t.Error("unexpected root of three leaves")
+	}
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	addr1 := common.NewAddress(common.NewCoordinate(0, 1), 0)
+	addr2 := common.NewAddress(common.NewCoordinate(0, 2), 0)
+
+	ctd := data.NewContextData(nil, nil)
+	ctd.CreatedAccountMap[addr2] = &account_def.SingleAccount{}
+	ctd.CreatedAccountMap[addr1] = &account_def.SingleAccount{}
+	ctd.CreatedUTXOMap[2] = &transaction.TxOut{}
+	ctd.CreatedUTXOMap[1] = &transaction.TxOut{}
+	ctd.SetAccountData(addr2, []byte("policy"), []byte{1})
+	ctd.SetAccountData(common.Address{}, []byte("order"), []byte{1})
+	c.AddContextData(ctd)
+
+	ctd = data.NewContextData(nil, nil)
+	ctd.DeletedAccountMap[addr2] = &account_def.SingleAccount{}
+	ctd.CreatedUTXOMap[3] = &transaction.TxOut{}
+	ctd.DeletedUTXOMap[3] = true
+	ctd.DeletedUTXOMap[2] = true
+	ctd.SetAccountData(common.Address{}, []byte("order"), nil)
+	c.AddContextData(ctd)
+
+	if addrs := c.Addresses(); len(addrs) != 1 || addrs[0] != addr1 {
+		t.Errorf("unexpected addresses %v", addrs)
+	}
+	if ids := c.UTXOs(); len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("unexpected utxos %v", ids)
+	}
+	if keys := c.DataKeys(); len(keys) != 1 || keys[0].Address != addr2 || string(keys[0].Name) != "policy" {
+		t.Errorf("unexpected data keys %v", keys)
+	}
+}
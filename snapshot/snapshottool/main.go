@@ -0,0 +1,95 @@
+// Command snapshottool verifies the snapshot file that is exported from a chain.
+//
+// It reads the file by the account types, checks the merkle root of the entries and prints the summary of the snapshot:
+//
+//	snapshottool -in state.snapshot
+//
+// The account types are the default types of the FLETA chains and can be overridden by a JSON file of the name and type pairs given by -types.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/json_util"
+	"github.com/fletaio/extension/snapshot"
+	"github.com/fletaio/extension/type_registry"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("snapshottool: ")
+
+	types := flag.String("types", "", "JSON file of the account names and types")
+	in := flag.String("in", "", "snapshot file")
+	flag.Parse()
+
+	if err := verify(*types, *in); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func verify(typesPath string, path string) error {
+	if len(path) == 0 {
+		return errors.New("-in is required")
+	}
+	types := type_registry.Default().Accounts
+	if len(typesPath) > 0 {
+		bs, err := ioutil.ReadFile(typesPath)
+		if err != nil {
+			return err
+		}
+		m := map[string]account.Type{}
+		if err := json.Unmarshal(bs, &m); err != nil {
+			return err
+		}
+		for name, t := range m {
+			types[name] = t
+		}
+	}
+	act := data.NewAccounter(common.NewCoordinate(0, 0))
+	for name, t := range types {
+		if err := act.RegisterType(name, t); err != nil {
+			return err
+		}
+	}
+	s, err := snapshot.ReadFile(act, path)
+	if err != nil {
+		return err
+	}
+
+	balance := amount.NewCoinAmount(0, 0)
+	for _, e := range s.Accounts {
+		balance = balance.Add(e.Account.Balance())
+	}
+	utxoAmount := amount.NewCoinAmount(0, 0)
+	for _, e := range s.UTXOs {
+		utxoAmount = utxoAmount.Add(e.Amount)
+	}
+	bs, err := json.MarshalIndent(map[string]interface{}{
+		"chain_coord": &json_util.Coordinate{
+			Height: s.ChainCoord.Height,
+			Index:  s.ChainCoord.Index,
+		},
+		"height":      s.Height,
+		"root":        s.Root(),
+		"accounts":    len(s.Accounts),
+		"balance":     balance,
+		"data":        len(s.Data),
+		"utxos":       len(s.UTXOs),
+		"utxo_amount": utxoAmount,
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bs))
+	return nil
+}
@@ -0,0 +1,17 @@
+package standing_order
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// standing_order errors
+var (
+	ErrInvalidSequence      = errs.ErrInvalidSequence
+	ErrDustAmount           = errs.ErrDustAmount
+	ErrInvalidInterval      = errs.ErrInvalidInterval
+	ErrInvalidSchedule      = errs.ErrInvalidSchedule
+	ErrNotExistOrder        = errs.ErrNotExistOrder
+	ErrNotOrderOwner        = errs.ErrNotOrderOwner
+	ErrSelfStandingOrder    = errs.ErrSelfStandingOrder
+	ErrInvalidStandingOrder = errs.ErrInvalidStandingOrder
+)
@@ -0,0 +1,3 @@
+package standing_order
+
+//go:generate go run ../codec/codecgen -type=StandingOrder,RegisterStandingOrder,CancelStandingOrder
@@ -0,0 +1,25 @@
+package standing_order
+
+import (
+	"testing"
+
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func TestTransactionJSON(t *testing.T) {
+	tran := test_util.Transactor(t, testTransactionTypes)
+	for _, src := range testTransactions() {
+		dst, err := tran.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.JSONRoundTrip(t, src.(test_util.JSONValue), dst.(test_util.JSONValue))
+		if src.Hash() != dst.Hash() {
+			t.Errorf("%T: hash mismatch after json round trip", src)
+		}
+	}
+}
+
+func TestStandingOrderJSON(t *testing.T) {
+	test_util.JSONRoundTrip(t, testStandingOrder(), &StandingOrder{})
+}
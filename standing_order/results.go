@@ -0,0 +1,17 @@
+package standing_order
+
+import (
+	"github.com/fletaio/common"
+)
+
+// RegisterStandingOrderResult is the execution result of the RegisterStandingOrder
+type RegisterStandingOrderResult struct {
+	OrderID    uint64         `json:"order_id"`
+	To         common.Address `json:"to"`
+	NextHeight uint32         `json:"next_height"`
+}
+
+// CancelStandingOrderResult is the execution result of the CancelStandingOrder
+type CancelStandingOrderResult struct {
+	OrderID uint64 `json:"order_id"`
+}
@@ -0,0 +1,107 @@
+package standing_order
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+var testTransactionTypes = map[string]transaction.Type{
+	"fleta.RegisterStandingOrder": 22,
+	"fleta.CancelStandingOrder":   23,
+}
+
+func testBase(t transaction.Type) account_tx.Base {
+	return account_tx.Base{
+		Base:  test_util.TxBase(t),
+		Seq_:  test_util.Seq,
+		From_: test_util.From(),
+	}
+}
+
+func testStandingOrder() *StandingOrder {
+	return &StandingOrder{
+		ID:               transaction.MarshalID(10, 1, 0),
+		From:             common.NewAddress(common.NewCoordinate(1, 2), 0),
+		To:               common.NewAddress(common.NewCoordinate(3, 4), 0),
+		Amount:           amount.NewCoinAmount(2, 0),
+		Interval:         100,
+		Remain:           10,
+		EndHeight:        2000,
+		NextHeight:       310,
+		Executed:         2,
+		Failed:           1,
+		LastFailedHeight: 210,
+	}
+}
+
+func testTransactions() []transaction.Transaction {
+	return []transaction.Transaction{
+		&RegisterStandingOrder{Base: testBase(22), To: common.NewAddress(common.NewCoordinate(3, 4), 0), Amount: amount.NewCoinAmount(2, 0), Interval: 100, Count: 12, EndHeight: 2000},
+		&RegisterStandingOrder{Base: testBase(22), To: common.NewAddress(common.NewCoordinate(3, 4), 0), Amount: amount.NewCoinAmount(0, 100000000000000000), Interval: 1},
+		&CancelStandingOrder{Base: testBase(23), OrderID: transaction.MarshalID(10, 1, 0)},
+	}
+}
+
+func TestTransactionBinary(t *testing.T) {
+	tran := test_util.Transactor(t, testTransactionTypes)
+	for _, src := range testTransactions() {
+		dst, err := tran.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.BinaryRoundTrip(t, src, dst)
+		if src.Hash() != dst.Hash() {
+			t.Errorf("%T: hash mismatch after binary round trip", src)
+		}
+	}
+}
+
+func FuzzTransactionReadFrom(f *testing.F) {
+	tran := test_util.Transactor(f, testTransactionTypes)
+	for _, src := range testTransactions() {
+		var buffer bytes.Buffer
+		if _, err := src.WriteTo(&buffer); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(uint8(src.Type()), buffer.Bytes())
+	}
+	f.Fuzz(func(t *testing.T, Type uint8, bs []byte) {
+		tx, err := tran.NewByType(transaction.Type(Type))
+		if err != nil {
+			return
+		}
+		if _, err := tx.ReadFrom(bytes.NewReader(bs)); err != nil {
+			return
+		}
+		dst, err := tran.NewByType(transaction.Type(Type))
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.BinaryRoundTrip(t, tx, dst)
+	})
+}
+
+func TestStandingOrderBinary(t *testing.T) {
+	test_util.BinaryRoundTrip(t, testStandingOrder(), &StandingOrder{})
+}
+
+func FuzzStandingOrderReadFrom(f *testing.F) {
+	var buffer bytes.Buffer
+	if _, err := testStandingOrder().WriteTo(&buffer); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buffer.Bytes())
+	f.Fuzz(func(t *testing.T, bs []byte) {
+		order := &StandingOrder{}
+		if _, err := order.ReadFrom(bytes.NewReader(bs)); err != nil {
+			return
+		}
+		test_util.BinaryRoundTrip(t, order, &StandingOrder{})
+	})
+}
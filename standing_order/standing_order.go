@@ -0,0 +1,244 @@
+package standing_order
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+)
+
+// scheduleAddress is the address that keeps standing orders and their due height index as account data
+var scheduleAddress = common.Address{}
+
+var (
+	tagOrder = []byte("standing_order.order.")
+	tagDue   = []byte("standing_order.due.")
+)
+
+// StandingOrder is a transfer that is executed repeatedly at every interval of blocks
+type StandingOrder struct {
+	ID               uint64         `codec:"id"`
+	From             common.Address `codec:"from"`
+	To               common.Address `codec:"to"`
+	Amount           *amount.Amount `codec:"amount"`
+	Interval         uint32         `codec:"interval"`
+	Remain           uint32         `codec:"remain"`     // 0 means unlimited until the end height
+	EndHeight        uint32         `codec:"end_height"` // 0 means no end height
+	NextHeight       uint32         `codec:"next_height"`
+	Executed         uint32         `codec:"executed"`
+	Failed           uint32         `codec:"failed"`
+	LastFailedHeight uint32         `codec:"last_failed_height"`
+}
+
+// isFinished returns true when the next height is over the end height of the order
+func (so *StandingOrder) isFinished(next uint32) bool {
+	if so.EndHeight > 0 && next > so.EndHeight {
+		return true
+	}
+	return false
+}
+
+func toOrderKey(id uint64) []byte {
+	bs := make([]byte, len(tagOrder)+8)
+	copy(bs, tagOrder)
+	binary.LittleEndian.PutUint64(bs[len(tagOrder):], id)
+	return bs
+}
+
+func toDueKey(height uint32) []byte {
+	bs := make([]byte, len(tagDue)+4)
+	copy(bs, tagDue)
+	binary.LittleEndian.PutUint32(bs[len(tagDue):], height)
+	return bs
+}
+
+// OrderByID returns the standing order of the id
+func OrderByID(loader data.Loader, id uint64) (*StandingOrder, error) {
+	bs := loader.AccountData(scheduleAddress, toOrderKey(id))
+	if len(bs) == 0 {
+		return nil, errs.WrapField(ErrNotExistOrder, "id", nil, id)
+	}
+	so := &StandingOrder{}
+	if _, err := so.ReadFrom(bytes.NewReader(bs)); err != nil {
+		return nil, err
+	}
+	return so, nil
+}
+
+// DueOrderIDs returns ids of the standing orders that are due at the height
+func DueOrderIDs(loader data.Loader, height uint32) []uint64 {
+	bs := loader.AccountData(scheduleAddress, toDueKey(height))
+	ids := make([]uint64, 0, len(bs)/8)
+	for i := 0; i+8 <= len(bs); i += 8 {
+		ids = append(ids, binary.LittleEndian.Uint64(bs[i:]))
+	}
+	return ids
+}
+
+func saveOrder(ctx *data.Context, so *StandingOrder) error {
+	var buffer bytes.Buffer
+	if _, err := so.WriteTo(&buffer); err != nil {
+		return err
+	}
+	ctx.SetAccountData(scheduleAddress, toOrderKey(so.ID), buffer.Bytes())
+	return nil
+}
+
+func deleteOrder(ctx *data.Context, so *StandingOrder) {
+	ctx.SetAccountData(scheduleAddress, toOrderKey(so.ID), nil)
+}
+
+func scheduleOrder(ctx *data.Context, height uint32, id uint64) {
+	key := toDueKey(height)
+	bs := ctx.AccountData(scheduleAddress, key)
+	nbs := make([]byte, len(bs)+8)
+	copy(nbs, bs)
+	binary.LittleEndian.PutUint64(nbs[len(bs):], id)
+	ctx.SetAccountData(scheduleAddress, key, nbs)
+}
+
+func unscheduleOrder(ctx *data.Context, height uint32, id uint64) {
+	key := toDueKey(height)
+	bs := ctx.AccountData(scheduleAddress, key)
+	nbs := make([]byte, 0, len(bs))
+	for i := 0; i+8 <= len(bs); i += 8 {
+		if binary.LittleEndian.Uint64(bs[i:]) != id {
+			nbs = append(nbs, bs[i:i+8]...)
+		}
+	}
+	if len(nbs) == 0 {
+		nbs = nil
+	}
+	ctx.SetAccountData(scheduleAddress, key, nbs)
+}
+
+// ProcessDue executes the standing orders that are due at the target height of the context
+// The payments are made in the order of the registration of the orders that are due at the same height
+// A payment that cannot be made by the balance or the spending policy of the payer is skipped and recorded as a failure,
+// and the failure is counted as one of the remaining payments of the order so a failing order is not rescheduled forever
+// The orders of the closed payers or recipients and the orders whose next height is over the range of the height are deleted
+func ProcessDue(ctx *data.Context) error {
+	height := ctx.TargetHeight()
+	ids := DueOrderIDs(ctx, height)
+	if len(ids) == 0 {
+		return nil
+	}
+	ctx.SetAccountData(scheduleAddress, toDueKey(height), nil)
+
+	for _, id := range ids {
+		so, err := OrderByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrNotExistOrder) {
+				continue
+			}
+			return err
+		}
+		if is, err := isExistParties(ctx, so); err != nil {
+			return err
+		} else if !is {
+			deleteOrder(ctx, so)
+			continue
+		}
+		if err := pay(ctx, so); err != nil {
+			so.Failed++
+			so.LastFailedHeight = height
+		} else {
+			so.Executed++
+		}
+		if so.Remain > 0 {
+			so.Remain--
+			if so.Remain == 0 {
+				deleteOrder(ctx, so)
+				continue
+			}
+		}
+		next := height + so.Interval
+		if next < height || so.isFinished(next) {
+			deleteOrder(ctx, so)
+			continue
+		}
+		so.NextHeight = next
+		if err := saveOrder(ctx, so); err != nil {
+			return err
+		}
+		scheduleOrder(ctx, next, so.ID)
+	}
+	return nil
+}
+
+// isExistParties returns false when the payer or the recipient of the order is closed
+func isExistParties(loader data.Loader, so *StandingOrder) (bool, error) {
+	if is, err := loader.IsExistAccount(so.From); err != nil || !is {
+		return false, err
+	}
+	return loader.IsExistAccount(so.To)
+}
+
+// pay transfers the amount of the order in the same way as a fleta.Transfer of the payer
+// The spending policy of the payer is applied as the registered transaction type and
+// the TransferEvent has the coordinate of the transaction that registered the order
+func pay(ctx *data.Context, so *StandingOrder) error {
+	t, err := ctx.Transactor().TypeByName("fleta.RegisterStandingOrder")
+	if err != nil {
+		return err
+	}
+	height, index, _ := transaction.UnmarshalID(so.ID)
+
+	sn := ctx.Snapshot()
+	defer ctx.Revert(sn)
+	em := event_def.NewEmitter(ctx, common.NewCoordinate(height, index))
+
+	fromAcc, err := ctx.Account(so.From)
+	if err != nil {
+		return err
+	}
+	if err := fromAcc.SubBalance(so.Amount); err != nil {
+		return err
+	}
+	if err := account_def.ApplyPolicy(ctx, so.From, t, []common.Address{so.To}, so.Amount); err != nil {
+		return err
+	}
+	toAcc, err := ctx.Account(so.To)
+	if err != nil {
+		return err
+	}
+	toAcc.AddBalance(so.Amount)
+	if err := em.Transfer(so.From, so.To, so.Amount); err != nil {
+		return err
+	}
+	ctx.Commit(sn)
+	return nil
+}
+
+// RewardProcessor processes the reward of the block generator
+type RewardProcessor interface {
+	ProcessReward(addr common.Address, ctx *data.Context) error
+}
+
+// Rewarder executes due standing orders before processing the reward of the wrapped rewarder
+// It is called when a block is generated and processed, so every node of the chain should use it
+type Rewarder struct {
+	rd RewardProcessor
+}
+
+// NewRewarder returns a Rewarder
+func NewRewarder(rd RewardProcessor) *Rewarder {
+	return &Rewarder{
+		rd: rd,
+	}
+}
+
+// ProcessReward executes due standing orders and gives a reward to the block generator address
+func (rd *Rewarder) ProcessReward(addr common.Address, ctx *data.Context) error {
+	if err := ProcessDue(ctx); err != nil {
+		return err
+	}
+	return rd.rd.ProcessReward(addr, ctx)
+}
@@ -0,0 +1,273 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package standing_order
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/json_util"
+)
+
+// WriteTo is a serialization function
+func (so *StandingOrder) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint64(w, so.ID); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := so.From.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := so.To.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := so.Amount.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, so.Interval); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, so.Remain); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, so.EndHeight); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, so.NextHeight); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, so.Executed); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, so.Failed); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, so.LastFailedHeight); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (so *StandingOrder) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if v, n, err := util.ReadUint64(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		so.ID = v
+	}
+	if n, err := so.From.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := so.To.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	so.Amount = amount.NewCoinAmount(0, 0)
+	if n, err := so.Amount.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		so.Interval = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		so.Remain = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		so.EndHeight = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		so.NextHeight = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		so.Executed = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		so.Failed = v
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		so.LastFailedHeight = v
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (so *StandingOrder) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"id":`)
+	if bs, err := json.Marshal(so.ID); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := so.From.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"to":`)
+	if bs, err := so.To.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := so.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"interval":`)
+	if bs, err := json.Marshal(so.Interval); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"remain":`)
+	if bs, err := json.Marshal(so.Remain); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"end_height":`)
+	if bs, err := json.Marshal(so.EndHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"next_height":`)
+	if bs, err := json.Marshal(so.NextHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"executed":`)
+	if bs, err := json.Marshal(so.Executed); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"failed":`)
+	if bs, err := json.Marshal(so.Failed); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"last_failed_height":`)
+	if bs, err := json.Marshal(so.LastFailedHeight); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (so *StandingOrder) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		ID               uint64          `json:"id"`
+		From             string          `json:"from"`
+		To               string          `json:"to"`
+		Amount           json.RawMessage `json:"amount"`
+		Interval         uint32          `json:"interval"`
+		Remain           uint32          `json:"remain"`
+		EndHeight        uint32          `json:"end_height"`
+		NextHeight       uint32          `json:"next_height"`
+		Executed         uint32          `json:"executed"`
+		Failed           uint32          `json:"failed"`
+		LastFailedHeight uint32          `json:"last_failed_height"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	so.ID = v.ID
+	if addr, err := common.ParseAddress(v.From); err != nil {
+		return err
+	} else {
+		so.From = addr
+	}
+	if addr, err := common.ParseAddress(v.To); err != nil {
+		return err
+	} else {
+		so.To = addr
+	}
+	if am, err := json_util.ParseAmount(v.Amount); err != nil {
+		return err
+	} else {
+		so.Amount = am
+	}
+	so.Interval = v.Interval
+	so.Remain = v.Remain
+	so.EndHeight = v.EndHeight
+	so.NextHeight = v.NextHeight
+	so.Executed = v.Executed
+	so.Failed = v.Failed
+	so.LastFailedHeight = v.LastFailedHeight
+	return nil
+}
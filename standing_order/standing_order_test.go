@@ -0,0 +1,238 @@
+package standing_order
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func newTestLoader(t *testing.T) *test_util.Loader {
+	loader := test_util.NewLoader(common.NewCoordinate(0, 0))
+	loader.Act = test_util.Accounter(t, map[string]account.Type{"fleta.SingleAccount": 1})
+	loader.Tran = test_util.Transactor(t, testTransactionTypes)
+	loader.Evt = test_util.Eventer(t, map[string]event.Type{"fleta.TransferEvent": 1})
+	return loader
+}
+
+func addAccount(t *testing.T, loader *test_util.Loader, n uint16, balance uint64) common.Address {
+	a, err := loader.Act.NewByTypeName("fleta.SingleAccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := a.(*account_def.SingleAccount)
+	acc.Address_ = test_util.Address(n)
+	acc.Balance_ = amount.NewCoinAmount(balance, 0)
+	loader.AddAccount(acc, 0)
+	return acc.Address_
+}
+
+func addOrder(t *testing.T, ctx *data.Context, so *StandingOrder) {
+	if err := saveOrder(ctx, so); err != nil {
+		t.Fatal(err)
+	}
+	scheduleOrder(ctx, so.NextHeight, so.ID)
+}
+
+func balanceOf(t *testing.T, ctx *data.Context, addr common.Address) *amount.Amount {
+	acc, err := ctx.Account(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return acc.Balance()
+}
+
+func transferEvents(ctx *data.Context) []*event_def.TransferEvent {
+	list := []*event_def.TransferEvent{}
+	for _, e := range ctx.Top().Events {
+		if ev, is := e.(*event_def.TransferEvent); is {
+			list = append(list, ev)
+		}
+	}
+	return list
+}
+
+func TestProcessDue(t *testing.T) {
+	loader := newTestLoader(t)
+	a := addAccount(t, loader, 1, 10)
+	b := addAccount(t, loader, 2, 0)
+	c := addAccount(t, loader, 3, 0)
+	ctx := data.NewContext(loader)
+
+	first := &StandingOrder{ID: transaction.MarshalID(1, 0, 0), From: a, To: b, Amount: amount.NewCoinAmount(6, 0), Interval: 10, Remain: 2, NextHeight: 10}
+	second := &StandingOrder{ID: transaction.MarshalID(1, 1, 0), From: a, To: c, Amount: amount.NewCoinAmount(6, 0), Interval: 10, Remain: 2, NextHeight: 10}
+	addOrder(t, ctx, first)
+	addOrder(t, ctx, second)
+
+	// the first order is paid and the second one fails because the balance is spent by the first one
+	// the failure is counted as one of the remaining payments
+	loader.Height = 10
+	if err := ProcessDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !balanceOf(t, ctx, a).Equal(amount.NewCoinAmount(4, 0)) || !balanceOf(t, ctx, b).Equal(amount.NewCoinAmount(6, 0)) {
+		t.Fatalf("unexpected balances %v, %v", balanceOf(t, ctx, a), balanceOf(t, ctx, b))
+	}
+	evs := transferEvents(ctx)
+	if len(evs) != 1 || evs[0].From != a || evs[0].To != b || !evs[0].Amount.Equal(first.Amount) || !evs[0].Coord().Equal(common.NewCoordinate(1, 0)) {
+		t.Fatalf("unexpected transfer events %+v", evs)
+	}
+	so, err := OrderByID(ctx, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if so.Executed != 1 || so.Remain != 1 || so.NextHeight != 20 {
+		t.Errorf("unexpected paid order %+v", so)
+	}
+	so, err = OrderByID(ctx, second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if so.Failed != 1 || so.LastFailedHeight != 10 || so.Executed != 0 || so.Remain != 1 || so.NextHeight != 20 {
+		t.Errorf("unexpected failed order %+v", so)
+	}
+	if ids := DueOrderIDs(ctx, 10); len(ids) != 0 {
+		t.Errorf("expected no due order at 10 but %v", ids)
+	}
+	if ids := DueOrderIDs(ctx, 20); len(ids) != 2 || ids[0] != first.ID || ids[1] != second.ID {
+		t.Errorf("unexpected due orders %v", ids)
+	}
+
+	// both orders are paid and deleted after their last payments
+	acc, err := ctx.Account(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc.AddBalance(amount.NewCoinAmount(10, 0))
+	loader.Height = 20
+	if err := ProcessDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !balanceOf(t, ctx, a).Equal(amount.NewCoinAmount(2, 0)) || !balanceOf(t, ctx, b).Equal(amount.NewCoinAmount(12, 0)) || !balanceOf(t, ctx, c).Equal(amount.NewCoinAmount(6, 0)) {
+		t.Fatalf("unexpected balances %v, %v, %v", balanceOf(t, ctx, a), balanceOf(t, ctx, b), balanceOf(t, ctx, c))
+	}
+	if evs := transferEvents(ctx); len(evs) != 3 || evs[2].To != c {
+		t.Fatalf("unexpected transfer events %+v", evs)
+	}
+	for _, id := range []uint64{first.ID, second.ID} {
+		if _, err := OrderByID(ctx, id); !errors.Is(err, ErrNotExistOrder) {
+			t.Errorf("expected the deleted order but %v", err)
+		}
+	}
+	if ids := DueOrderIDs(ctx, 30); len(ids) != 0 {
+		t.Errorf("expected no due order at 30 but %v", ids)
+	}
+}
+
+func TestProcessDueExpiry(t *testing.T) {
+	loader := newTestLoader(t)
+	a := addAccount(t, loader, 1, 10)
+	b := addAccount(t, loader, 2, 0)
+	ctx := data.NewContext(loader)
+
+	ended := &StandingOrder{ID: transaction.MarshalID(1, 0, 0), From: a, To: b, Amount: amount.NewCoinAmount(1, 0), Interval: 10, EndHeight: 15, NextHeight: 10}
+	closed := &StandingOrder{ID: transaction.MarshalID(1, 1, 0), From: test_util.Address(9), To: b, Amount: amount.NewCoinAmount(1, 0), Interval: 10, EndHeight: 100, NextHeight: 10}
+	failed := &StandingOrder{ID: transaction.MarshalID(1, 2, 0), From: a, To: b, Amount: amount.NewCoinAmount(100, 0), Interval: 10, EndHeight: 15, NextHeight: 10}
+	addOrder(t, ctx, ended)
+	addOrder(t, ctx, closed)
+	addOrder(t, ctx, failed)
+
+	loader.Height = 10
+	if err := ProcessDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !balanceOf(t, ctx, b).Equal(amount.NewCoinAmount(1, 0)) {
+		t.Fatalf("unexpected balance %v", balanceOf(t, ctx, b))
+	}
+	// the orders after the end height and of the closed payer are deleted whether they are paid or not
+	for _, so := range []*StandingOrder{ended, closed, failed} {
+		if _, err := OrderByID(ctx, so.ID); !errors.Is(err, ErrNotExistOrder) {
+			t.Errorf("expected the deleted order %d but %v", so.ID, err)
+		}
+	}
+	if ids := DueOrderIDs(ctx, 20); len(ids) != 0 {
+		t.Errorf("expected no due order at 20 but %v", ids)
+	}
+}
+
+func TestProcessDueBounded(t *testing.T) {
+	loader := newTestLoader(t)
+	a := addAccount(t, loader, 1, 0)
+	b := addAccount(t, loader, 2, 0)
+	ctx := data.NewContext(loader)
+
+	failing := &StandingOrder{ID: transaction.MarshalID(1, 0, 0), From: a, To: b, Amount: amount.NewCoinAmount(1, 0), Interval: 1, Remain: 3, NextHeight: 10}
+	closed := &StandingOrder{ID: transaction.MarshalID(1, 1, 0), From: a, To: test_util.Address(9), Amount: amount.NewCoinAmount(1, 0), Interval: 1, EndHeight: 100, NextHeight: 10}
+	addOrder(t, ctx, failing)
+	addOrder(t, ctx, closed)
+
+	// the failures of the empty payer use up the remaining payments
+	for h := uint32(10); h < 13; h++ {
+		loader.Height = h
+		if err := ProcessDue(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the order of the closed recipient is deleted at its first due height
+	for _, so := range []*StandingOrder{failing, closed} {
+		if _, err := OrderByID(ctx, so.ID); !errors.Is(err, ErrNotExistOrder) {
+			t.Errorf("expected the deleted order %d but %v", so.ID, err)
+		}
+	}
+	if ids := DueOrderIDs(ctx, 13); len(ids) != 0 {
+		t.Errorf("expected no due order at 13 but %v", ids)
+	}
+
+	// the order whose next height overflows is deleted
+	overflow := &StandingOrder{ID: transaction.MarshalID(1, 2, 0), From: a, To: b, Amount: amount.NewCoinAmount(1, 0), Interval: 0x20, Remain: 5, NextHeight: 0xFFFFFFF0}
+	addOrder(t, ctx, overflow)
+	loader.Height = 0xFFFFFFF0
+	if err := ProcessDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OrderByID(ctx, overflow.ID); !errors.Is(err, ErrNotExistOrder) {
+		t.Errorf("expected the deleted order but %v", err)
+	}
+	if ids := DueOrderIDs(ctx, 0x10); len(ids) != 0 {
+		t.Errorf("expected no wrapped due order but %v", ids)
+	}
+}
+
+type testRewarder struct {
+	addr   common.Address
+	called bool
+}
+
+func (rd *testRewarder) ProcessReward(addr common.Address, ctx *data.Context) error {
+	rd.addr = addr
+	rd.called = true
+	return nil
+}
+
+func TestRewarder(t *testing.T) {
+	loader := newTestLoader(t)
+	a := addAccount(t, loader, 1, 10)
+	b := addAccount(t, loader, 2, 0)
+	ctx := data.NewContext(loader)
+	addOrder(t, ctx, &StandingOrder{ID: transaction.MarshalID(1, 0, 0), From: a, To: b, Amount: amount.NewCoinAmount(3, 0), Interval: 10, Remain: 5, NextHeight: 10})
+
+	inner := &testRewarder{}
+	loader.Height = 10
+	if err := NewRewarder(inner).ProcessReward(test_util.Address(7), ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !inner.called || inner.addr != test_util.Address(7) {
+		t.Errorf("expected the wrapped rewarder to be called with the generator address")
+	}
+	if !balanceOf(t, ctx, b).Equal(amount.NewCoinAmount(3, 0)) {
+		t.Errorf("expected the due order to be paid but %v", balanceOf(t, ctx, b))
+	}
+}
@@ -0,0 +1,119 @@
+package standing_order
+
+import (
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/fee"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+)
+
+func init() {
+	data.RegisterTransaction("fleta.CancelStandingOrder", func(t transaction.Type) transaction.Transaction {
+		return &CancelStandingOrder{
+			Base: account_tx.Base{
+				Base: transaction.Base{
+					Type_: t,
+				},
+			},
+		}
+	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
+		tx := t.(*CancelStandingOrder)
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
+		}
+		if tx.Seq() <= loader.Seq(tx.From()) {
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
+		}
+
+		so, err := OrderByID(loader, tx.OrderID)
+		if err != nil {
+			return err
+		}
+		if so.From != tx.From() {
+			return errs.WrapValue(ErrNotOrderOwner, tx.Type(), "from", so.From, tx.From())
+		}
+
+		fromAcc, err := loader.Account(tx.From())
+		if err != nil {
+			return err
+		}
+
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), nil, nil); err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
+		tx := t.(*CancelStandingOrder)
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
+
+		sn := ctx.Snapshot()
+		defer ctx.Revert(sn)
+
+		if tx.Seq() != ctx.Seq(tx.From())+1 {
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
+		}
+		ctx.AddSeq(tx.From())
+
+		fromAcc, err := ctx.Account(tx.From())
+		if err != nil {
+			return nil, err
+		}
+		if err := fromAcc.SubBalance(Fee); err != nil {
+			return nil, err
+		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), nil, nil); err != nil {
+			return nil, err
+		}
+
+		so, err := OrderByID(ctx, tx.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if so.From != tx.From() {
+			return nil, errs.WrapValue(ErrNotOrderOwner, tx.Type(), "from", so.From, tx.From())
+		}
+		unscheduleOrder(ctx, so.NextHeight, so.ID)
+		deleteOrder(ctx, so)
+
+		ctx.Commit(sn)
+		return &CancelStandingOrderResult{
+			OrderID: so.ID,
+		}, nil
+	})
+}
+
+// CancelStandingOrder is a fleta.CancelStandingOrder
+// It is used to cancel the standing order that is registered by the account
+type CancelStandingOrder struct {
+	account_tx.Base
+	OrderID uint64 `codec:"order_id"`
+}
+
+// Hash returns the hash value of it
+func (tx *CancelStandingOrder) Hash() hash.Hash256 {
+	return hash.DoubleHashByWriterTo(tx)
+}
+
+// Size returns the serialized byte size of it
+func (tx *CancelStandingOrder) Size() (int64, error) {
+	return fee.Size(tx)
+}
+
+// OutputCount returns the number of the outputs that it makes
+func (tx *CancelStandingOrder) OutputCount() int {
+	return 0
+}
+
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *CancelStandingOrder) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	return nil
+}
@@ -0,0 +1,171 @@
+package standing_order
+
+import (
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/fee"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+)
+
+func init() {
+	data.RegisterTransaction("fleta.RegisterStandingOrder", func(t transaction.Type) transaction.Transaction {
+		return &RegisterStandingOrder{
+			Base: account_tx.Base{
+				Base: transaction.Base{
+					Type_: t,
+				},
+			},
+			Amount: amount.NewCoinAmount(0, 0),
+		}
+	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
+		tx := t.(*RegisterStandingOrder)
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
+		}
+		if tx.Seq() <= loader.Seq(tx.From()) {
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
+		}
+		if tx.EndHeight > 0 && tx.EndHeight < loader.TargetHeight()+tx.Interval {
+			return errs.WrapValue(ErrInvalidSchedule, tx.Type(), "end_height", loader.TargetHeight()+tx.Interval, tx.EndHeight)
+		}
+
+		fromAcc, err := loader.Account(tx.From())
+		if err != nil {
+			return err
+		}
+		if _, err := loader.Account(tx.To); err != nil {
+			return err
+		}
+
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), []common.Address{tx.To}, tx.Amount); err != nil {
+			return err
+		}
+		return nil
+	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
+		tx := t.(*RegisterStandingOrder)
+		policy := chain.PolicyOf(ctx.ChainCoord())
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
+		if tx.Interval == 0 {
+			return nil, errs.WrapValue(ErrInvalidInterval, tx.Type(), "interval", "at least 1", tx.Interval)
+		}
+		if tx.Count == 0 && tx.EndHeight == 0 {
+			return nil, errs.Wrap(ErrInvalidSchedule, tx.Type(), "count")
+		}
+		if tx.From() == tx.To {
+			return nil, errs.WrapValue(ErrSelfStandingOrder, tx.Type(), "to", nil, tx.To)
+		}
+
+		sn := ctx.Snapshot()
+		defer ctx.Revert(sn)
+
+		if tx.Seq() != ctx.Seq(tx.From())+1 {
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
+		}
+		ctx.AddSeq(tx.From())
+
+		if policy.IsDust(tx.Amount) {
+			return nil, errs.WrapValue(ErrDustAmount, tx.Type(), "amount", policy.DustAmount, tx.Amount)
+		}
+
+		fromAcc, err := ctx.Account(tx.From())
+		if err != nil {
+			return nil, err
+		}
+		if err := fromAcc.SubBalance(Fee); err != nil {
+			return nil, err
+		}
+		if _, err := ctx.Account(tx.To); err != nil {
+			return nil, err
+		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), []common.Address{tx.To}, nil); err != nil {
+			return nil, err
+		}
+		// the payments are recorded in the spent amount when they are made, so the registration only checks the first one
+		if err := account_def.CheckPolicy(ctx, tx.From(), tx.Type(), []common.Address{tx.To}, tx.Amount); err != nil {
+			return nil, err
+		}
+
+		so := &StandingOrder{
+			ID:         transaction.MarshalID(coord.Height, coord.Index, 0),
+			From:       tx.From(),
+			To:         tx.To,
+			Amount:     tx.Amount.Clone(),
+			Interval:   tx.Interval,
+			Remain:     tx.Count,
+			EndHeight:  tx.EndHeight,
+			NextHeight: coord.Height + tx.Interval,
+		}
+		if so.NextHeight < coord.Height {
+			return nil, errs.WrapValue(ErrInvalidSchedule, tx.Type(), "interval", ^uint32(0)-coord.Height, tx.Interval)
+		}
+		if so.isFinished(so.NextHeight) {
+			return nil, errs.WrapValue(ErrInvalidSchedule, tx.Type(), "end_height", so.NextHeight, tx.EndHeight)
+		}
+		if err := saveOrder(ctx, so); err != nil {
+			return nil, err
+		}
+		scheduleOrder(ctx, so.NextHeight, so.ID)
+
+		ctx.Commit(sn)
+		return &RegisterStandingOrderResult{
+			OrderID:    so.ID,
+			To:         so.To,
+			NextHeight: so.NextHeight,
+		}, nil
+	})
+}
+
+// RegisterStandingOrder is a fleta.RegisterStandingOrder
+// It is used to register a transfer that is executed at every interval of blocks
+// The order is finished after Count payments or at EndHeight, whichever comes first (0 means no limit)
+type RegisterStandingOrder struct {
+	account_tx.Base
+	To        common.Address `codec:"to"`
+	Amount    *amount.Amount `codec:"amount"`
+	Interval  uint32         `codec:"interval"`
+	Count     uint32         `codec:"count"`
+	EndHeight uint32         `codec:"end_height"`
+}
+
+// Hash returns the hash value of it
+func (tx *RegisterStandingOrder) Hash() hash.Hash256 {
+	return hash.DoubleHashByWriterTo(tx)
+}
+
+// Size returns the serialized byte size of it
+func (tx *RegisterStandingOrder) Size() (int64, error) {
+	return fee.Size(tx)
+}
+
+// OutputCount returns the number of the outputs that it makes
+func (tx *RegisterStandingOrder) OutputCount() int {
+	return 1
+}
+
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *RegisterStandingOrder) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	policy := chain.PolicyOf(coord)
+	if policy.IsDust(tx.Amount) {
+		return errs.WrapValue(ErrDustAmount, tx.Type(), "amount", policy.DustAmount, tx.Amount)
+	}
+	if tx.Interval == 0 {
+		return errs.WrapValue(ErrInvalidInterval, tx.Type(), "interval", "at least 1", tx.Interval)
+	}
+	if tx.Count == 0 && tx.EndHeight == 0 {
+		return errs.Wrap(ErrInvalidSchedule, tx.Type(), "count")
+	}
+	if tx.From() == tx.To {
+		return errs.WrapValue(ErrSelfStandingOrder, tx.Type(), "to", nil, tx.To)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package store
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// store error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeCorruptedStore errs.Code = 2601
+)
+
+// store errors
+var (
+	ErrCorruptedStore = errs.New(CodeCorruptedStore, "corrupted store")
+)
@@ -0,0 +1,161 @@
+package store
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// maxLevel is the number of the levels of the skip list that is enough for billions of keys
+const maxLevel = 24
+
+type node struct {
+	key   string
+	value []byte
+	next  []*node
+	prev  *node // nil when it is the first node
+}
+
+// orderedMap is a skip list of the keys that keeps them in order while they are set and deleted
+type orderedMap struct {
+	head  *node
+	tail  *node
+	level int
+	size  int
+	rnd   *rand.Rand
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{
+		head:  &node{next: make([]*node, maxLevel)},
+		level: 1,
+		rnd:   rand.New(rand.NewSource(1)),
+	}
+}
+
+// seek returns the first node that is not less than the key
+// It fills update with the last nodes that are less than the key at each level when update is not nil
+func (m *orderedMap) seek(key string, update []*node) *node {
+	x := m.head
+	for i := m.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+		if update != nil {
+			update[i] = x
+		}
+	}
+	return x.next[0]
+}
+
+func (m *orderedMap) randomLevel() int {
+	lvl := 1
+	for lvl < maxLevel && m.rnd.Intn(4) == 0 {
+		lvl++
+	}
+	return lvl
+}
+
+// Len returns the number of the keys
+func (m *orderedMap) Len() int {
+	return m.size
+}
+
+// Get returns the value of the key
+func (m *orderedMap) Get(key string) ([]byte, bool) {
+	if x := m.seek(key, nil); x != nil && x.key == key {
+		return x.value, true
+	}
+	return nil, false
+}
+
+// Set sets the value of the key
+func (m *orderedMap) Set(key string, value []byte) {
+	update := make([]*node, maxLevel)
+	if x := m.seek(key, update); x != nil && x.key == key {
+		x.value = value
+		return
+	}
+	lvl := m.randomLevel()
+	for i := m.level; i < lvl; i++ {
+		update[i] = m.head
+	}
+	if lvl > m.level {
+		m.level = lvl
+	}
+	n := &node{
+		key:   key,
+		value: value,
+		next:  make([]*node, lvl),
+	}
+	for i := 0; i < lvl; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+	if update[0] != m.head {
+		n.prev = update[0]
+	}
+	if n.next[0] != nil {
+		n.next[0].prev = n
+	} else {
+		m.tail = n
+	}
+	m.size++
+}
+
+// Delete deletes the key
+func (m *orderedMap) Delete(key string) {
+	update := make([]*node, maxLevel)
+	x := m.seek(key, update)
+	if x == nil || x.key != key {
+		return
+	}
+	for i := range x.next {
+		update[i].next[i] = x.next[i]
+	}
+	if x.next[0] != nil {
+		x.next[0].prev = x.prev
+	} else {
+		m.tail = x.prev
+	}
+	for m.level > 1 && m.head.next[m.level-1] == nil {
+		m.level--
+	}
+	m.size--
+}
+
+// Iterate calls fn in the order of the keys that have the prefix until fn returns false
+func (m *orderedMap) Iterate(prefix string, reverse bool, fn func(key string, value []byte) bool) {
+	if !reverse {
+		for x := m.seek(prefix, nil); x != nil && strings.HasPrefix(x.key, prefix); x = x.next[0] {
+			if !fn(x.key, x.value) {
+				return
+			}
+		}
+		return
+	}
+
+	x := m.tail
+	if end, has := prefixEnd(prefix); has {
+		if y := m.seek(end, nil); y != nil {
+			x = y.prev
+		}
+	}
+	for ; x != nil && strings.HasPrefix(x.key, prefix); x = x.prev {
+		if !fn(x.key, x.value) {
+			return
+		}
+	}
+}
+
+// prefixEnd returns the least key that is greater than all keys that have the prefix
+// It returns false when there is no such key like the empty prefix or the prefix of 0xff bytes
+func prefixEnd(prefix string) (string, bool) {
+	bs := []byte(prefix)
+	for i := len(bs) - 1; i >= 0; i-- {
+		if bs[i] < 0xff {
+			bs[i]++
+			return string(bs[:i+1]), true
+		}
+	}
+	return "", false
+}
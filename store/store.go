@@ -0,0 +1,299 @@
+// Package store provides the embedded key-value store that keeps the indexes of the extensions
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/extension/errs"
+)
+
+// Store is the key-value store that keeps the index
+type Store interface {
+	// Get returns nil when the key does not exist
+	Get(key []byte) ([]byte, error)
+	// Iterate calls fn in the order of the keys that have the prefix until fn returns false
+	Iterate(prefix []byte, reverse bool, fn func(key []byte, value []byte) bool) error
+	// Apply applies all changes of the batch or nothing
+	Apply(b *Batch) error
+	Close() error
+}
+
+type batchOp struct {
+	isDelete bool
+	key      []byte
+	value    []byte
+}
+
+// Batch is the changes that are applied to a store at once
+type Batch struct {
+	ops []batchOp
+}
+
+// NewBatch returns a Batch
+func NewBatch() *Batch {
+	return &Batch{
+		ops: []batchOp{},
+	}
+}
+
+// Set sets the value of the key
+func (b *Batch) Set(key []byte, value []byte) {
+	b.ops = append(b.ops, batchOp{key: key, value: value})
+}
+
+// Delete deletes the key
+func (b *Batch) Delete(key []byte) {
+	b.ops = append(b.ops, batchOp{isDelete: true, key: key})
+}
+
+// Len returns the number of the changes
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// WriteTo is a serialization function
+func (b *Batch) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint32(w, uint32(len(b.ops))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, op := range b.ops {
+		if op.isDelete {
+			if n, err := util.WriteUint8(w, 1); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		} else {
+			if n, err := util.WriteUint8(w, 0); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+		if n, err := util.WriteBytes(w, op.key); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if !op.isDelete {
+			if n, err := util.WriteBytes(w, op.value); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (b *Batch) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	Len, n, err := util.ReadUint32(r)
+	if err != nil {
+		return read, err
+	}
+	read += n
+	b.ops = make([]batchOp, 0, Len)
+	for i := uint32(0); i < Len; i++ {
+		var op batchOp
+		if v, n, err := util.ReadUint8(r); err != nil {
+			return read, err
+		} else if v > 1 {
+			return read, errs.WrapField(ErrCorruptedStore, "op", "0 or 1", v)
+		} else {
+			read += n
+			op.isDelete = (v == 1)
+		}
+		if bs, n, err := util.ReadBytes(r); err != nil {
+			return read, err
+		} else {
+			read += n
+			op.key = bs
+		}
+		if !op.isDelete {
+			if bs, n, err := util.ReadBytes(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				op.value = bs
+			}
+		}
+		b.ops = append(b.ops, op)
+	}
+	return read, nil
+}
+
+// FileStore is an embedded store that keeps the keys in order in the memory and appends the batches to a file
+// A batch that is not completely written by a crash is dropped when the file is opened
+type FileStore struct {
+	sync.Mutex
+	path  string
+	file  *os.File
+	kvMap *orderedMap
+	ops   int
+	size  int64
+}
+
+// OpenFileStore returns the store of the file that is created when it does not exist
+func OpenFileStore(path string) (*FileStore, error) {
+	st := &FileStore{
+		path:  path,
+		kvMap: newOrderedMap(),
+	}
+	if err := st.load(); err != nil {
+		return nil, err
+	}
+	if st.ops > 2*st.kvMap.Len()+1024 {
+		if err := st.compact(); err != nil {
+			st.file.Close()
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+func (st *FileStore) load() error {
+	file, err := os.OpenFile(st.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	r := bufio.NewReader(file)
+	var offset int64
+	for {
+		b := &Batch{}
+		n, err := b.ReadFrom(r)
+		if err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			file.Close()
+			return err
+		}
+		offset += n
+		st.apply(b)
+	}
+	if err := file.Truncate(offset); err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+	st.file = file
+	st.size = offset
+	return nil
+}
+
+func (st *FileStore) compact() error {
+	tmp := st.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	b := NewBatch()
+	st.kvMap.Iterate("", false, func(key string, value []byte) bool {
+		b.Set([]byte(key), value)
+		return true
+	})
+	w := bufio.NewWriter(file)
+	size, err := b.WriteTo(w)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := os.Rename(tmp, st.path); err != nil {
+		file.Close()
+		return err
+	}
+	st.file.Close()
+	st.file = file
+	st.ops = b.Len()
+	st.size = size
+	return nil
+}
+
+func (st *FileStore) apply(b *Batch) {
+	for _, op := range b.ops {
+		if op.isDelete {
+			st.kvMap.Delete(string(op.key))
+		} else {
+			st.kvMap.Set(string(op.key), op.value)
+		}
+	}
+	st.ops += len(b.ops)
+}
+
+// rewind drops the partially written batch so the next batch is appended after the last complete one
+func (st *FileStore) rewind() {
+	st.file.Truncate(st.size)
+	st.file.Seek(st.size, io.SeekStart)
+}
+
+// Get returns nil when the key does not exist
+func (st *FileStore) Get(key []byte) ([]byte, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	value, _ := st.kvMap.Get(string(key))
+	return value, nil
+}
+
+// Iterate calls fn in the order of the keys that have the prefix until fn returns false
+// fn should not use the store because it is called while the store is locked
+func (st *FileStore) Iterate(prefix []byte, reverse bool, fn func(key []byte, value []byte) bool) error {
+	st.Lock()
+	defer st.Unlock()
+
+	st.kvMap.Iterate(string(prefix), reverse, func(key string, value []byte) bool {
+		return fn([]byte(key), value)
+	})
+	return nil
+}
+
+// Apply writes the batch to the file and applies it to the memory
+func (st *FileStore) Apply(b *Batch) error {
+	st.Lock()
+	defer st.Unlock()
+
+	var buffer bytes.Buffer
+	if _, err := b.WriteTo(&buffer); err != nil {
+		return err
+	}
+	if _, err := st.file.Write(buffer.Bytes()); err != nil {
+		st.rewind()
+		return err
+	}
+	if err := st.file.Sync(); err != nil {
+		st.rewind()
+		return err
+	}
+	st.size += int64(buffer.Len())
+	st.apply(b)
+	return nil
+}
+
+// Close closes the file of the store
+func (st *FileStore) Close() error {
+	st.Lock()
+	defer st.Unlock()
+
+	return st.file.Close()
+}
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func tempStorePath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "store.db"), func() { os.RemoveAll(dir) }
+}
+
+func keysOf(t *testing.T, st Store, prefix string, reverse bool) []string {
+	keys := []string{}
+	if err := st.Iterate([]byte(prefix), reverse, func(key []byte, value []byte) bool {
+		keys = append(keys, string(key))
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestFileStore(t *testing.T) {
+	path, remove := tempStorePath(t)
+	defer remove()
+	st, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewBatch()
+	b.Set([]byte("a2"), []byte("2"))
+	b.Set([]byte("a1"), []byte("1"))
+	b.Set([]byte("b1"), []byte("3"))
+	if err := st.Apply(b); err != nil {
+		t.Fatal(err)
+	}
+	b = NewBatch()
+	b.Delete([]byte("b1"))
+	b.Set([]byte("a3"), []byte("4"))
+	if err := st.Apply(b); err != nil {
+		t.Fatal(err)
+	}
+	if keys := keysOf(t, st, "a", true); len(keys) != 3 || keys[0] != "a3" || keys[2] != "a1" {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	st.Close()
+
+	st, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	if v, _ := st.Get([]byte("a2")); string(v) != "2" {
+		t.Errorf("expected the value 2 but %q", v)
+	}
+	if v, _ := st.Get([]byte("b1")); v != nil {
+		t.Errorf("expected the deleted key but %q", v)
+	}
+	if keys := keysOf(t, st, "", false); len(keys) != 3 {
+		t.Errorf("unexpected keys %v", keys)
+	}
+}
+
+func TestFileStoreTornBatch(t *testing.T) {
+	path, remove := tempStorePath(t)
+	defer remove()
+	st, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewBatch()
+	b.Set([]byte("a1"), []byte("1"))
+	if err := st.Apply(b); err != nil {
+		t.Fatal(err)
+	}
+	st.Close()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the count of a batch of two changes and only the first change
+	f.Write([]byte{2, 0, 0, 0, 0, 2, 'a', '2', 1, '2'})
+	f.Close()
+
+	st, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := st.Get([]byte("a2")); v != nil {
+		t.Errorf("the torn batch is applied %q", v)
+	}
+	b = NewBatch()
+	b.Set([]byte("a3"), []byte("3"))
+	if err := st.Apply(b); err != nil {
+		t.Fatal(err)
+	}
+	st.Close()
+	if info2, _ := os.Stat(path); info2.Size() <= info.Size() {
+		t.Fatal("the batch is not appended")
+	}
+
+	st, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	if keys := keysOf(t, st, "a", false); len(keys) != 2 || keys[0] != "a1" || keys[1] != "a3" {
+		t.Errorf("unexpected keys %v", keys)
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	m := newOrderedMap()
+	keys := []string{}
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("%03d", (i*7)%300)
+		m.Set(key, []byte(key))
+		keys = append(keys, key)
+	}
+	for i := 0; i < 300; i += 2 {
+		m.Delete(fmt.Sprintf("%03d", i))
+	}
+	m.Delete("not exist")
+	if m.Len() != 150 {
+		t.Fatalf("expected 150 keys but %v", m.Len())
+	}
+	if _, has := m.Get("010"); has {
+		t.Error("the deleted key exists")
+	}
+	if v, has := m.Get("011"); !has || string(v) != "011" {
+		t.Errorf("expected the value 011 but %q", v)
+	}
+
+	sort.Strings(keys)
+	expected := []string{}
+	for _, key := range keys {
+		if key[2]%2 == 1 && strings.HasPrefix(key, "1") {
+			expected = append(expected, key)
+		}
+	}
+	for _, reverse := range []bool{false, true} {
+		got := []string{}
+		m.Iterate("1", reverse, func(key string, value []byte) bool {
+			got = append(got, key)
+			return true
+		})
+		if len(got) != len(expected) {
+			t.Fatalf("expected %v keys but %v", len(expected), got)
+		}
+		for i := range got {
+			j := i
+			if reverse {
+				j = len(expected) - 1 - i
+			}
+			if got[i] != expected[j] {
+				t.Fatalf("expected %v but %v", expected[j], got[i])
+			}
+		}
+	}
+
+	m.Set("\xff\xff", []byte{})
+	got := []string{}
+	m.Iterate("\xff", true, func(key string, value []byte) bool {
+		got = append(got, key)
+		return true
+	})
+	if len(got) != 1 || got[0] != "\xff\xff" {
+		t.Errorf("unexpected keys %q", got)
+	}
+}
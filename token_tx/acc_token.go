@@ -1,15 +1,12 @@
 package token_tx
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/core/amount"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/core/account"
 	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/errs"
 )
 
 func init() {
@@ -23,11 +20,11 @@ func init() {
 	}, func(loader data.Loader, a account.Account, signers []common.PublicHash) error {
 		acc := a.(*TokenAccount)
 		if len(signers) != 1 {
-			return ErrInvalidSignerCount
+			return errs.WrapField(ErrInvalidSignerCount, "signers", 1, len(signers))
 		}
 		signer := signers[0]
 		if !acc.KeyHash.Equal(signer) {
-			return ErrInvalidAccountSigner
+			return errs.WrapField(ErrInvalidAccountSigner, "signers", acc.KeyHash, signer)
 		}
 		return nil
 	})
@@ -37,8 +34,8 @@ func init() {
 // It is used as a basic account
 type TokenAccount struct {
 	account.Base
-	TokenCoord common.Coordinate
-	KeyHash    common.PublicHash
+	TokenCoord common.Coordinate `codec:"token_coord"`
+	KeyHash    common.PublicHash `codec:"key_hash"`
 }
 
 // Clone returns the clonend value of it
@@ -47,79 +44,10 @@ func (acc *TokenAccount) Clone() account.Account {
 		Base: account.Base{
 			Type_:    acc.Type_,
 			Address_: acc.Address_,
+			Name_:    acc.Name_,
 			Balance_: acc.Balance(),
 		},
 		TokenCoord: *acc.TokenCoord.Clone(),
 		KeyHash:    acc.KeyHash.Clone(),
 	}
 }
-
-// WriteTo is a serialization function
-func (acc *TokenAccount) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := acc.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := acc.TokenCoord.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := acc.KeyHash.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	return wrote, nil
-}
-
-// ReadFrom is a deserialization function
-func (acc *TokenAccount) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := acc.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if n, err := acc.TokenCoord.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if n, err := acc.KeyHash.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	return read, nil
-}
-
-// MarshalJSON is a marshaler function
-func (acc *TokenAccount) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"address":`)
-	if bs, err := acc.Address_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(acc.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"key_hash":`)
-	if bs, err := acc.KeyHash.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
-}
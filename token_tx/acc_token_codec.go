@@ -0,0 +1,141 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package token_tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/extension/json_util"
+)
+
+// WriteTo is a serialization function
+func (acc *TokenAccount) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := acc.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := acc.TokenCoord.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := acc.KeyHash.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (acc *TokenAccount) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := acc.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := acc.TokenCoord.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := acc.KeyHash.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (acc *TokenAccount) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"address":`)
+	if bs, err := acc.Address_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(acc.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(acc.Name_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"balance":`)
+	if bs, err := acc.Balance_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_coord":`)
+	if bs, err := json.Marshal(&json_util.Coordinate{
+		Height: acc.TokenCoord.Height,
+		Index:  acc.TokenCoord.Index,
+	}); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"key_hash":`)
+	if bs, err := acc.KeyHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (acc *TokenAccount) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Address    string               `json:"address"`
+		Type       account.Type         `json:"type"`
+		Name       string               `json:"name"`
+		Balance    json.RawMessage      `json:"balance"`
+		TokenCoord json_util.Coordinate `json:"token_coord"`
+		KeyHash    string               `json:"key_hash"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	if addr, err := common.ParseAddress(v.Address); err != nil {
+		return err
+	} else {
+		acc.Address_ = addr
+	}
+	acc.Type_ = v.Type
+	acc.Name_ = v.Name
+	if am, err := json_util.ParseAmount(v.Balance); err != nil {
+		return err
+	} else {
+		acc.Balance_ = am
+	}
+	acc.TokenCoord = *common.NewCoordinate(v.TokenCoord.Height, v.TokenCoord.Index)
+	if pubhash, err := common.ParsePublicHash(v.KeyHash); err != nil {
+		return err
+	} else {
+		acc.KeyHash = pubhash
+	}
+	return nil
+}
@@ -42,7 +42,7 @@ func NewKey(sk string) *Key {
 	return k
 }
 
-//Addresses TODO
+// Addresses TODO
 type Addresses struct {
 	MainObserver   []*Key
 	MainFormulator []*Key
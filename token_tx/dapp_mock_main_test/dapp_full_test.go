@@ -16,7 +16,9 @@ import (
 	"github.com/fletaio/core/key"
 	"github.com/fletaio/core/message_def"
 	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_name"
 	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/event_def"
 	"github.com/fletaio/extension/token_tx/dapp_mock_main_test/address"
 	"github.com/fletaio/extension/token_tx/dapp_mock_main_test/dappchain"
 	"github.com/fletaio/extension/token_tx/dapp_mock_main_test/mainchain"
@@ -81,17 +83,15 @@ type DappStarterEventHandler struct {
 }
 
 func (eh *DappStarterEventHandler) AfterProcessBlock(kn *kernel.Kernel, b *block.Block, s *block.ObserverSigned, ctx *data.Context) {
-	for i, t := range b.Body.Transactions {
-		switch tx := t.(type) {
-		case *token_tx.TokenCreation:
+	for _, e := range ctx.Top().Events {
+		switch ev := e.(type) {
+		case *event_def.TokenCreatedEvent:
 			// if tx's coordinate == dapp chain coord
-			coord := common.NewCoordinate(b.Header.Height(), uint16(i))
-			log.Println("token_tx.TokenCreation", coord.Height, coord.Index)
+			log.Println("event_def.TokenCreatedEvent", ev.TokenCoord.Height, ev.TokenCoord.Index)
 
-			addr := common.NewAddress(coord, 0)
-			address.ADDR.MainTokenAccount.Addr = addr
+			address.ADDR.MainTokenAccount.Addr = ev.Address
 			// check hash and genesis context hash
-			if tx.TokenPublicHash.String() != eh.TokenPublicHash {
+			if ev.TokenPublicHash.String() != eh.TokenPublicHash {
 				continue
 			}
 			go func(addr common.Address) {
@@ -105,7 +105,7 @@ func (eh *DappStarterEventHandler) AfterProcessBlock(kn *kernel.Kernel, b *block
 
 					t.Seq_ = eh.mainkn.Loader().Seq(eh.accountAddr) + 1
 					t.From_ = address.ADDR.MainAccount.Addr
-					t.To = address.ADDR.MainTokenAccount.Addr
+					t.To = account_name.NewAddressDestination(address.ADDR.MainTokenAccount.Addr)
 					t.Amount = amount.NewCoinAmount(500000, 0)
 
 					sig1, _ := address.ADDR.MainAccount.Signer.Sign(t.Hash())
@@ -114,16 +114,16 @@ func (eh *DappStarterEventHandler) AfterProcessBlock(kn *kernel.Kernel, b *block
 					eh.mainkn.AddTransaction(t, sigs1)
 					// end Transfer
 				}
-			}(addr)
+			}(ev.Address)
 
-		case *account_tx.Transfer:
-			if tx.From_ != address.ADDR.MainAccount.Addr || tx.To != address.ADDR.MainTokenAccount.Addr {
+		case *event_def.TransferEvent:
+			if ev.From != address.ADDR.MainAccount.Addr || ev.To != address.ADDR.MainTokenAccount.Addr {
 				continue
 			}
 
 			Height := binary.LittleEndian.Uint32(address.ADDR.MainTokenAccount.Addr[:4])
 			Index := binary.LittleEndian.Uint16(address.ADDR.MainTokenAccount.Addr[4:6])
-			log.Println("account_tx.Transfer", Height, Index)
+			log.Println("event_def.TransferEvent", Height, Index)
 			coord := common.NewCoordinate(Height, Index)
 
 			go func(coord *common.Coordinate) {
@@ -173,7 +173,11 @@ func (eh *DappStarterEventHandler) AfterProcessBlock(kn *kernel.Kernel, b *block
 					// end CreateContract
 				}
 			}(coord)
+		}
+	}
 
+	for _, t := range b.Body.Transactions {
+		switch tx := t.(type) {
 		case *token_tx.ChainInitialization:
 			//check chaininfo
 			go func(tx *token_tx.ChainInitialization) {
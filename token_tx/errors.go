@@ -1,20 +1,21 @@
 package token_tx
 
 import (
-	"errors"
+	"github.com/fletaio/extension/errs"
 )
 
-// account_tx errors
+// token_tx errors
 var (
-	ErrInvalidSequence             = errors.New("invalid sequence")
-	ErrInsuffcientBalance          = errors.New("insufficient balance")
-	ErrExistAddress                = errors.New("exist address")
-	ErrInvalidTransactionSignature = errors.New("invalid transaction signature")
-	ErrInvalidMultiSigKeyHashCount = errors.New("invalid multisig key hash count")
-	ErrNotMainChain                = errors.New("not main chain")
-	ErrDustAmount                  = errors.New("dust amount")
-	ErrInvalidSignerCount          = errors.New("invalid signer count")
-	ErrInvalidAccountSigner        = errors.New("invalid account signer")
-	ErrLockedAccount               = errors.New("locked account")
-	ErrFromTypeMustTokenAccount    = errors.New("only TokenAccount can initialize the chain")
+	ErrInvalidSequence             = errs.ErrInvalidSequence
+	ErrInsuffcientBalance          = errs.ErrInsufficientBalance
+	ErrExistAddress                = errs.ErrExistAddress
+	ErrInvalidTransactionSignature = errs.ErrInvalidTransactionSignature
+	ErrInvalidMultiSigKeyHashCount = errs.ErrInvalidMultiSigKeyHashCount
+	ErrNotMainChain                = errs.ErrNotMainChain
+	ErrDustAmount                  = errs.ErrDustAmount
+	ErrInvalidSignerCount          = errs.ErrInvalidSignerCount
+	ErrInvalidAccountSigner        = errs.ErrInvalidAccountSigner
+	ErrLockedAccount               = errs.ErrLockedAccount
+	ErrFromTypeMustTokenAccount    = errs.ErrFromTypeMustTokenAccount
+	ErrTooLongTag                  = errs.ErrTooLongTag
 )
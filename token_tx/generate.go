@@ -0,0 +1,3 @@
+package token_tx
+
+//go:generate go run ../codec/codecgen -type=TokenCreation,ChainInitialization,TokenCreationInformation:binary,ObserverInfo:binary,TokenIssue,EngraveDapp,TokenAccount
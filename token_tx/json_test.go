@@ -0,0 +1,47 @@
+package token_tx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fletaio/core/amount"
+
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func TestTransactionJSON(t *testing.T) {
+	tran := test_util.Transactor(t, testTransactionTypes)
+	for _, src := range testTransactions() {
+		dst, err := tran.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.JSONRoundTrip(t, src.(test_util.JSONValue), dst.(test_util.JSONValue))
+		if src.Hash() != dst.Hash() {
+			t.Errorf("%T: hash mismatch after json round trip", src)
+		}
+	}
+}
+
+func TestTokenAccountJSON(t *testing.T) {
+	test_util.JSONRoundTrip(t, testTokenAccount(), &TokenAccount{})
+}
+
+func TestTokenIssueTagJSON(t *testing.T) {
+	// the tag of a token issue keeps the base64 form of encoding/json
+	tx := &TokenIssue{Base: testBase(52), Amount: amount.NewCoinAmount(5, 0), Tag: []byte("memo")}
+	bs, err := tx.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bs), `"tag":"bWVtbw=="`) {
+		t.Fatalf("unexpected tag in %s", bs)
+	}
+	tx.Tag = nil
+	if bs, err = tx.MarshalJSON(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bs), `"tag":null`) {
+		t.Fatalf("unexpected tag in %s", bs)
+	}
+}
@@ -0,0 +1,32 @@
+package token_tx
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/amount"
+)
+
+// TokenCreationResult is the execution result of the TokenCreation
+type TokenCreationResult struct {
+	Address   common.Address `json:"address"`
+	TokenName string         `json:"token_name"`
+}
+
+// TokenIssueResult is the execution result of the TokenIssue
+type TokenIssueResult struct {
+	TokenAddress common.Address `json:"token_address"`
+	Height       uint32         `json:"height"`
+	Amount       *amount.Amount `json:"amount"`
+}
+
+// ChainInitializationResult is the execution result of the ChainInitialization
+type ChainInitializationResult struct {
+	GenesisContextHash hash.Hash256 `json:"genesis_context_hash"`
+	ObserverCount      int          `json:"observer_count"`
+}
+
+// EngraveDappResult is the execution result of the EngraveDapp
+type EngraveDappResult struct {
+	Height    uint32       `json:"height"`
+	BlockHash hash.Hash256 `json:"block_hash"`
+}
@@ -0,0 +1,118 @@
+package token_tx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+var testTransactionTypes = map[string]transaction.Type{
+	"fleta.TokenCreation":       50,
+	"fleta.ChainInitialization": 51,
+	"fleta.TokenIssue":          52,
+	"fleta.EngraveDapp":         53,
+}
+
+func testBase(t transaction.Type) account_tx.Base {
+	return account_tx.Base{
+		Base:  test_util.TxBase(t),
+		Seq_:  test_util.Seq,
+		From_: test_util.From(),
+	}
+}
+
+func testTokenAccount() *TokenAccount {
+	return &TokenAccount{
+		Base: account.Base{
+			Type_:    12,
+			Address_: common.NewAddress(common.NewCoordinate(5, 6), 0),
+			Name_:    "tokenaccount",
+			Balance_: amount.NewCoinAmount(1, 0),
+		},
+		TokenCoord: *common.NewCoordinate(3, 4),
+		KeyHash:    test_util.PublicHash(2),
+	}
+}
+
+func testTransactions() []transaction.Transaction {
+	var blockHash hash.Hash256
+	for i := range blockHash {
+		blockHash[i] = byte(i)
+	}
+
+	return []transaction.Transaction{
+		&TokenCreation{Base: testBase(50), TokenName: "testtoken", TokenPublicHash: test_util.PublicHash(1)},
+		&ChainInitialization{Base: testBase(51), TokenCreationInformation: TokenCreationInformation{
+			GenesisContextHash: blockHash,
+			ObserverInfos: []ObserverInfo{
+				{Hash: "obs1", URL: "localhost:3001"},
+				{Hash: "obs2", URL: "localhost:3002"},
+			},
+		}},
+		&TokenIssue{Base: testBase(52), TokenAddress: common.NewAddress(common.NewCoordinate(3, 4), 0), Height: 10, Amount: amount.NewCoinAmount(5, 0), Tag: []byte("memo")},
+		&TokenIssue{Base: testBase(52), TokenAddress: common.NewAddress(common.NewCoordinate(3, 4), 0), Height: 10, Amount: amount.NewCoinAmount(5, 0)},
+		&EngraveDapp{Base: testBase(53), Height: 100, BlockHash: blockHash},
+	}
+}
+
+func TestTransactionBinary(t *testing.T) {
+	tran := test_util.Transactor(t, testTransactionTypes)
+	for _, src := range testTransactions() {
+		dst, err := tran.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.BinaryRoundTrip(t, src, dst)
+		if src.Hash() != dst.Hash() {
+			t.Errorf("%T: hash mismatch after binary round trip", src)
+		}
+	}
+}
+
+func TestTokenAccountBinary(t *testing.T) {
+	acc := testTokenAccount()
+	test_util.BinaryRoundTrip(t, acc, &TokenAccount{Base: account.Base{Balance_: amount.NewCoinAmount(0, 0)}})
+
+	var buffer, cloned bytes.Buffer
+	if _, err := acc.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acc.Clone().WriteTo(&cloned); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buffer.Bytes(), cloned.Bytes()) {
+		t.Errorf("clone mismatch\n got %x\nwant %x", cloned.Bytes(), buffer.Bytes())
+	}
+}
+
+func FuzzTransactionReadFrom(f *testing.F) {
+	tran := test_util.Transactor(f, testTransactionTypes)
+	for _, src := range testTransactions() {
+		var buffer bytes.Buffer
+		if _, err := src.WriteTo(&buffer); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(uint8(src.Type()), buffer.Bytes())
+	}
+	f.Fuzz(func(t *testing.T, Type uint8, bs []byte) {
+		tx, err := tran.NewByType(transaction.Type(Type))
+		if err != nil {
+			return
+		}
+		if _, err := tx.ReadFrom(bytes.NewReader(bs)); err != nil {
+			return
+		}
+		dst, err := tran.NewByType(transaction.Type(Type))
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.BinaryRoundTrip(t, tx, dst)
+	})
+}
@@ -1,17 +1,16 @@
 package token_tx
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-
+	"github.com/fletaio/extension/account_def"
 	"github.com/fletaio/extension/account_tx"
 
 	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/transaction"
 )
@@ -27,11 +26,11 @@ func init() {
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*ChainInitialization)
-		if !transaction.IsMainChain(loader.ChainCoord()) {
-			return ErrNotMainChain
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
 		}
 		if tx.Seq() <= loader.Seq(tx.From()) {
-			return ErrInvalidSequence
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
 		}
 
 		fromAcc, err := loader.Account(tx.From())
@@ -46,20 +45,25 @@ func init() {
 		}
 
 		if Name != "fleta.TokenAccount" {
-			return ErrFromTypeMustTokenAccount
+			return errs.WrapValue(ErrFromTypeMustTokenAccount, tx.Type(), "from", "fleta.TokenAccount", Name)
 		}
 
-		if err := loader.Accounter().Validate(loader, fromAcc, signers); err != nil {
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), nil, nil); err != nil {
 			return err
 		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*ChainInitialization)
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		if tx.Seq() != ctx.Seq(tx.From())+1 {
-			return nil, ErrInvalidSequence
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
 		}
 		ctx.AddSeq(tx.From())
 
@@ -70,9 +74,18 @@ func init() {
 		if err := fromAcc.SubBalance(Fee); err != nil {
 			return nil, err
 		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), nil, nil); err != nil {
+			return nil, err
+		}
 
+		if err := em.ChainInitialized(tx.From(), tx.GenesisContextHash); err != nil {
+			return nil, err
+		}
 		ctx.Commit(sn)
-		return nil, nil
+		return &ChainInitializationResult{
+			GenesisContextHash: tx.GenesisContextHash,
+			ObserverCount:      len(tx.ObserverInfos),
+		}, nil
 	})
 }
 
@@ -88,168 +101,34 @@ func (tx *ChainInitialization) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *ChainInitialization) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.TokenCreationInformation.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *ChainInitialization) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *ChainInitialization) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if n, err := tx.TokenCreationInformation.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-
-	return read, nil
+// OutputCount returns the number of the outputs that it makes
+func (tx *ChainInitialization) OutputCount() int {
+	return 0
 }
 
-// MarshalJSON is a marshaler function
-func (tx *ChainInitialization) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *ChainInitialization) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	if !transaction.IsMainChain(coord) {
+		return errs.WrapValue(ErrNotMainChain, tx.Type(), "chain_coord", nil, coord)
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"seq":`)
-	if bs, err := json.Marshal(tx.Seq_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"from":`)
-	if bs, err := tx.From_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"genesis_context_hash":`)
-	if bs, err := tx.GenesisContextHash.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"observer_infos":`)
-	if bs, err := json.Marshal(tx.ObserverInfos); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	return nil
 }
 
 // TokenCreationInformation is a information of token creation
 type TokenCreationInformation struct {
-	GenesisContextHash hash.Hash256
-	ObserverInfos      []ObserverInfo
+	GenesisContextHash hash.Hash256   `codec:"genesis_context_hash"`
+	ObserverInfos      []ObserverInfo `codec:"observer_infos"`
 }
 
 // ObserverInfo is a information of observer
 type ObserverInfo struct {
-	Hash string
-	URL  string
-}
-
-// WriteTo is a serialization function
-func (ti *TokenCreationInformation) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := ti.GenesisContextHash.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-
-	if n, err := util.WriteUint8(w, byte(len(ti.ObserverInfos))); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	for _, v := range ti.ObserverInfos {
-		if n, err := util.WriteString(w, v.Hash); err != nil {
-			return wrote, err
-		} else {
-			wrote += n
-		}
-		if n, err := util.WriteString(w, v.URL); err != nil {
-			return wrote, err
-		} else {
-			wrote += n
-		}
-	}
-	return wrote, nil
-}
-
-// ReadFrom is a deserialization function
-func (ti *TokenCreationInformation) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := ti.GenesisContextHash.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-
-	var hlen int
-	if v, n, err := util.ReadUint8(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		hlen = int(v)
-	}
-
-	ti.ObserverInfos = make([]ObserverInfo, hlen)
-	for i := 0; i < hlen; i++ {
-		hash, n, err := util.ReadString(r)
-		if err != nil {
-			return read, err
-		} else {
-			read += n
-		}
-		url, n, err := util.ReadString(r)
-		if err != nil {
-			return read, err
-		} else {
-			read += n
-		}
-		ti.ObserverInfos[i] = ObserverInfo{
-			Hash: hash,
-			URL:  url,
-		}
-	}
-
-	return read, nil
+	Hash string `codec:"Hash,max=255"`
+	URL  string `codec:"URL,max=255"`
 }
 
 // Equal returns a == b
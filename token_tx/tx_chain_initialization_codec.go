@@ -0,0 +1,221 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package token_tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+)
+
+// WriteTo is a serialization function
+func (tx *ChainInitialization) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tx.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tx.TokenCreationInformation.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tx *ChainInitialization) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tx.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := tx.TokenCreationInformation.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *ChainInitialization) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tx.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"genesis_context_hash":`)
+	if bs, err := tx.GenesisContextHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"observer_infos":`)
+	if bs, err := json.Marshal(tx.ObserverInfos); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tx *ChainInitialization) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Type               transaction.Type `json:"type"`
+		Timestamp          uint64           `json:"timestamp"`
+		Seq                uint64           `json:"seq"`
+		From               string           `json:"from"`
+		GenesisContextHash string           `json:"genesis_context_hash"`
+		ObserverInfos      []ObserverInfo   `json:"observer_infos"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tx.Type_ = v.Type
+	tx.Timestamp_ = v.Timestamp
+	tx.Seq_ = v.Seq
+	if addr, err := common.ParseAddress(v.From); err != nil {
+		return err
+	} else {
+		tx.From_ = addr
+	}
+	if h, err := hash.ParseHash(v.GenesisContextHash); err != nil {
+		return err
+	} else {
+		tx.GenesisContextHash = h
+	}
+	if err := codec.CheckItemCount("TokenCreationInformation.ObserverInfos", uint64(len(v.ObserverInfos)), 255); err != nil {
+		return err
+	}
+	tx.ObserverInfos = v.ObserverInfos
+	return nil
+}
+
+// WriteTo is a serialization function
+func (ti *TokenCreationInformation) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := ti.GenesisContextHash.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckItemCount("TokenCreationInformation.ObserverInfos", uint64(len(ti.ObserverInfos)), 255); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteUint8(w, uint8(len(ti.ObserverInfos))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+		for _, v := range ti.ObserverInfos {
+			if n, err := v.WriteTo(w); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (ti *TokenCreationInformation) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := ti.GenesisContextHash.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if Len, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		ti.ObserverInfos = make([]ObserverInfo, 0, Len)
+		for i := 0; i < int(Len); i++ {
+			var v ObserverInfo
+			if n, err := v.ReadFrom(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				ti.ObserverInfos = append(ti.ObserverInfos, v)
+			}
+		}
+	}
+	return read, nil
+}
+
+// WriteTo is a serialization function
+func (oi *ObserverInfo) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if err := codec.CheckByteLength("ObserverInfo.Hash", uint64(len(oi.Hash)), 255); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteString(w, oi.Hash); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckByteLength("ObserverInfo.URL", uint64(len(oi.URL)), 255); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteString(w, oi.URL); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (oi *ObserverInfo) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if v, n, err := codec.ReadString(r, "ObserverInfo.Hash", 255); err != nil {
+		return read, err
+	} else {
+		read += n
+		oi.Hash = v
+	}
+	if v, n, err := codec.ReadString(r, "ObserverInfo.URL", 255); err != nil {
+		return read, err
+	} else {
+		read += n
+		oi.URL = v
+	}
+	return read, nil
+}
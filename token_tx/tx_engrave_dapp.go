@@ -1,16 +1,15 @@
 package token_tx
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
 	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/transaction"
 )
@@ -26,11 +25,11 @@ func init() {
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*EngraveDapp)
-		if !transaction.IsMainChain(loader.ChainCoord()) {
-			return ErrNotMainChain
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
 		}
 		if tx.Seq() <= loader.Seq(tx.From()) {
-			return ErrInvalidSequence
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
 		}
 
 		fromAcc, err := loader.Account(tx.From())
@@ -45,20 +44,25 @@ func init() {
 		}
 
 		if Name != "fleta.TokenAccount" {
-			return ErrFromTypeMustTokenAccount
+			return errs.WrapValue(ErrFromTypeMustTokenAccount, tx.Type(), "from", "fleta.TokenAccount", Name)
 		}
 
-		if err := loader.Accounter().Validate(loader, fromAcc, signers); err != nil {
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), nil, nil); err != nil {
 			return err
 		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*EngraveDapp)
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		if tx.Seq() != ctx.Seq(tx.From())+1 {
-			return nil, ErrInvalidSequence
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
 		}
 		ctx.AddSeq(tx.From())
 
@@ -69,9 +73,18 @@ func init() {
 		if err := fromAcc.SubBalance(Fee); err != nil {
 			return nil, err
 		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), nil, nil); err != nil {
+			return nil, err
+		}
 
+		if err := em.DappEngraved(tx.From(), tx.Height, tx.BlockHash); err != nil {
+			return nil, err
+		}
 		ctx.Commit(sn)
-		return nil, nil
+		return &EngraveDappResult{
+			Height:    tx.Height,
+			BlockHash: tx.BlockHash,
+		}, nil
 	})
 }
 
@@ -79,8 +92,8 @@ func init() {
 // It is engraved dapp on main chain
 type EngraveDapp struct {
 	account_tx.Base
-	Height    uint32
-	BlockHash hash.Hash256
+	Height    uint32       `codec:"height"`
+	BlockHash hash.Hash256 `codec:"block_hash"`
 }
 
 // Hash returns the hash value of it
@@ -88,94 +101,20 @@ func (tx *EngraveDapp) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *EngraveDapp) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteUint32(w, tx.Height); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.BlockHash.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *EngraveDapp) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *EngraveDapp) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if v, n, err := util.ReadUint32(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Height = v
-	}
-	if n, err := tx.BlockHash.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	return read, nil
+// OutputCount returns the number of the outputs that it makes
+func (tx *EngraveDapp) OutputCount() int {
+	return 0
 }
 
-// MarshalJSON is a marshaler function
-func (tx *EngraveDapp) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"seq":`)
-	if bs, err := json.Marshal(tx.Seq_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"from":`)
-	if bs, err := tx.From_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"height":`)
-	if bs, err := json.Marshal(tx.Height); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"block_hash":`)
-	if bs, err := tx.BlockHash.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *EngraveDapp) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	if !transaction.IsMainChain(coord) {
+		return errs.WrapValue(ErrNotMainChain, tx.Type(), "chain_coord", nil, coord)
 	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	return nil
 }
@@ -1,16 +1,15 @@
 package token_tx
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
 	"log"
 
-	"github.com/fletaio/common/util"
-
+	"github.com/fletaio/extension/account_def"
 	"github.com/fletaio/extension/account_tx"
 
 	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
@@ -29,11 +28,11 @@ func init() {
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*TokenCreation)
-		if !transaction.IsMainChain(loader.ChainCoord()) {
-			return ErrNotMainChain
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
 		}
 		if tx.Seq() <= loader.Seq(tx.From()) {
-			return ErrInvalidSequence
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
 		}
 
 		fromAcc, err := loader.Account(tx.From())
@@ -42,17 +41,22 @@ func init() {
 			return err
 		}
 
-		if err := loader.Accounter().Validate(loader, fromAcc, signers); err != nil {
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), nil, nil); err != nil {
 			return err
 		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*TokenCreation)
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		if tx.Seq() != ctx.Seq(tx.From())+1 {
-			return nil, ErrInvalidSequence
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
 		}
 		ctx.AddSeq(tx.From())
 
@@ -63,12 +67,15 @@ func init() {
 		if err := fromAcc.SubBalance(Fee); err != nil {
 			return nil, err
 		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), nil, nil); err != nil {
+			return nil, err
+		}
 
 		addr := common.NewAddress(coord, 0)
 		if is, err := ctx.IsExistAccount(addr); err != nil {
 			return nil, err
 		} else if is {
-			return nil, ErrExistAddress
+			return nil, errs.WrapValue(ErrExistAddress, tx.Type(), "address", nil, addr)
 		}
 
 		a, err := ctx.Accounter().NewByTypeName("fleta.TokenAccount")
@@ -85,9 +92,15 @@ func init() {
 		if err != nil {
 			return nil, err
 		}
+		if err := em.TokenCreated(addr, tx.TokenName, coord, tx.TokenPublicHash); err != nil {
+			return nil, err
+		}
 
 		ctx.Commit(sn)
-		return nil, nil
+		return &TokenCreationResult{
+			Address:   addr,
+			TokenName: tx.TokenName,
+		}, nil
 	})
 }
 
@@ -95,8 +108,8 @@ func init() {
 // It is used to make a single account
 type TokenCreation struct {
 	account_tx.Base
-	TokenName       string
-	TokenPublicHash common.PublicHash
+	TokenName       string            `codec:"token_name,max=64"`
+	TokenPublicHash common.PublicHash `codec:"token_public_hash"`
 }
 
 // Hash returns the hash value of it
@@ -104,87 +117,20 @@ func (tx *TokenCreation) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *TokenCreation) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteString(w, tx.TokenName); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.TokenPublicHash.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *TokenCreation) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *TokenCreation) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if value, n, err := util.ReadString(r); err != nil {
-		return read, err
-	} else {
-		tx.TokenName = value
-		read += n
-	}
-	if n, err := tx.TokenPublicHash.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	return read, nil
+// OutputCount returns the number of the outputs that it makes
+func (tx *TokenCreation) OutputCount() int {
+	return 1
 }
 
-// MarshalJSON is a marshaler function
-func (tx *TokenCreation) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"seq":`)
-	if bs, err := json.Marshal(tx.Seq_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"from":`)
-	if bs, err := tx.From_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"token_public_hash":`)
-	if bs, err := tx.TokenPublicHash.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *TokenCreation) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	if !transaction.IsMainChain(coord) {
+		return errs.WrapValue(ErrNotMainChain, tx.Type(), "chain_coord", nil, coord)
 	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	return nil
 }
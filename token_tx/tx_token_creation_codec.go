@@ -0,0 +1,142 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package token_tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+)
+
+// WriteTo is a serialization function
+func (tx *TokenCreation) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tx.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckByteLength("TokenCreation.TokenName", uint64(len(tx.TokenName)), 64); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteString(w, tx.TokenName); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tx.TokenPublicHash.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tx *TokenCreation) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tx.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := codec.ReadString(r, "TokenCreation.TokenName", 64); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.TokenName = v
+	}
+	if n, err := tx.TokenPublicHash.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *TokenCreation) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tx.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_name":`)
+	if bs, err := json.Marshal(tx.TokenName); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_public_hash":`)
+	if bs, err := tx.TokenPublicHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tx *TokenCreation) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Type            transaction.Type `json:"type"`
+		Timestamp       uint64           `json:"timestamp"`
+		Seq             uint64           `json:"seq"`
+		From            string           `json:"from"`
+		TokenName       string           `json:"token_name"`
+		TokenPublicHash string           `json:"token_public_hash"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tx.Type_ = v.Type
+	tx.Timestamp_ = v.Timestamp
+	tx.Seq_ = v.Seq
+	if addr, err := common.ParseAddress(v.From); err != nil {
+		return err
+	} else {
+		tx.From_ = addr
+	}
+	if err := codec.CheckByteLength("TokenCreation.TokenName", uint64(len(v.TokenName)), 64); err != nil {
+		return err
+	}
+	tx.TokenName = v.TokenName
+	if pubhash, err := common.ParsePublicHash(v.TokenPublicHash); err != nil {
+		return err
+	} else {
+		tx.TokenPublicHash = pubhash
+	}
+	return nil
+}
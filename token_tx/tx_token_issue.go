@@ -1,16 +1,16 @@
 package token_tx
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_def"
 	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/transaction"
 )
@@ -23,14 +23,15 @@ func init() {
 					Type_: t,
 				},
 			},
+			Amount: amount.NewCoinAmount(0, 0),
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*TokenIssue)
-		if !transaction.IsMainChain(loader.ChainCoord()) {
-			return ErrNotMainChain
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
 		}
 		if tx.Seq() <= loader.Seq(tx.From()) {
-			return ErrInvalidSequence
+			return errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", loader.Seq(tx.From())+1, tx.Seq())
 		}
 
 		fromAcc, err := loader.Account(tx.From())
@@ -38,17 +39,26 @@ func init() {
 			return err
 		}
 
-		if err := loader.Accounter().Validate(loader, fromAcc, signers); err != nil {
+		if err := account_def.ValidateSender(loader, fromAcc, signers, tx.Type(), []common.Address{tx.TokenAddress}, tx.Amount); err != nil {
 			return err
 		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*TokenIssue)
+		policy := chain.PolicyOf(ctx.ChainCoord())
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
+		if len(tx.Tag) > policy.MaxTagLength {
+			return nil, errs.WrapValue(ErrTooLongTag, tx.Type(), "tag", policy.MaxTagLength, len(tx.Tag))
+		}
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		if tx.Seq() != ctx.Seq(tx.From())+1 {
-			return nil, ErrInvalidSequence
+			return nil, errs.WrapValue(ErrInvalidSequence, tx.Type(), "seq", ctx.Seq(tx.From())+1, tx.Seq())
 		}
 		ctx.AddSeq(tx.From())
 
@@ -62,9 +72,19 @@ func init() {
 		if err := fromAcc.SubBalance(tx.Amount); err != nil {
 			return nil, err
 		}
+		if err := account_def.ApplyPolicy(ctx, tx.From(), tx.Type(), []common.Address{tx.TokenAddress}, tx.Amount); err != nil {
+			return nil, err
+		}
 
+		if err := em.TokenIssued(tx.TokenAddress, tx.Height, tx.Amount); err != nil {
+			return nil, err
+		}
 		ctx.Commit(sn)
-		return nil, nil
+		return &TokenIssueResult{
+			TokenAddress: tx.TokenAddress,
+			Height:       tx.Height,
+			Amount:       tx.Amount.Clone(),
+		}, nil
 	})
 }
 
@@ -72,10 +92,10 @@ func init() {
 // It is used to make a single account
 type TokenIssue struct {
 	account_tx.Base
-	TokenAddress common.Address
-	Height       uint32
-	Amount       *amount.Amount
-	Tag          []byte
+	TokenAddress common.Address `codec:"token_address"`
+	Height       uint32         `codec:"height"`
+	Amount       *amount.Amount `codec:"amount"`
+	Tag          []byte         `codec:"tag,max=256,base64,memo"`
 }
 
 // Hash returns the hash value of it
@@ -83,129 +103,24 @@ func (tx *TokenIssue) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *TokenIssue) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.TokenAddress.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteUint32(w, tx.Height); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.Amount.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteBytes(w, tx.Tag); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *TokenIssue) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *TokenIssue) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if n, err := tx.TokenAddress.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if v, n, err := util.ReadUint32(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Height = v
-	}
-	if n, err := tx.Amount.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if bs, n, err := util.ReadBytes(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Tag = bs
-	}
-	return read, nil
+// OutputCount returns the number of the outputs that it makes
+func (tx *TokenIssue) OutputCount() int {
+	return 0
 }
 
-// MarshalJSON is a marshaler function
-func (tx *TokenIssue) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"seq":`)
-	if bs, err := json.Marshal(tx.Seq_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"from":`)
-	if bs, err := tx.From_.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"token_address":`)
-	if bs, err := tx.TokenAddress.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"height":`)
-	if bs, err := json.Marshal(tx.Height); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
-	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"amount":`)
-	if bs, err := tx.Amount.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *TokenIssue) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	policy := chain.PolicyOf(coord)
+	if !transaction.IsMainChain(coord) {
+		return errs.WrapValue(ErrNotMainChain, tx.Type(), "chain_coord", nil, coord)
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"tag":`)
-	if bs, err := json.Marshal(tx.Tag); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+	if len(tx.Tag) > policy.MaxTagLength {
+		return errs.WrapValue(ErrTooLongTag, tx.Type(), "tag", policy.MaxTagLength, len(tx.Tag))
 	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	return nil
 }
@@ -0,0 +1,196 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package token_tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/json_util"
+	"github.com/fletaio/extension/memo"
+)
+
+// WriteTo is a serialization function
+func (tx *TokenIssue) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tx.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tx.TokenAddress.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := util.WriteUint32(w, tx.Height); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tx.Amount.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckByteLength("TokenIssue.Tag", uint64(len(tx.Tag)), 256); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteBytes(w, tx.Tag); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tx *TokenIssue) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tx.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if n, err := tx.TokenAddress.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.Height = v
+	}
+	tx.Amount = amount.NewCoinAmount(0, 0)
+	if n, err := tx.Amount.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if v, n, err := codec.ReadBytes(r, "TokenIssue.Tag", 256); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.Tag = v
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *TokenIssue) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tx.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"seq":`)
+	if bs, err := json.Marshal(tx.Seq_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"from":`)
+	if bs, err := tx.From_.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"token_address":`)
+	if bs, err := tx.TokenAddress.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"height":`)
+	if bs, err := json.Marshal(tx.Height); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"amount":`)
+	if bs, err := tx.Amount.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"tag":`)
+	if bs, err := json.Marshal(tx.Tag); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"memo":`)
+	if bs, err := memo.MarshalTagJSON(tx.Tag); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tx *TokenIssue) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Type         transaction.Type `json:"type"`
+		Timestamp    uint64           `json:"timestamp"`
+		Seq          uint64           `json:"seq"`
+		From         string           `json:"from"`
+		TokenAddress string           `json:"token_address"`
+		Height       uint32           `json:"height"`
+		Amount       json.RawMessage  `json:"amount"`
+		Tag          []byte           `json:"tag"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tx.Type_ = v.Type
+	tx.Timestamp_ = v.Timestamp
+	tx.Seq_ = v.Seq
+	if addr, err := common.ParseAddress(v.From); err != nil {
+		return err
+	} else {
+		tx.From_ = addr
+	}
+	if addr, err := common.ParseAddress(v.TokenAddress); err != nil {
+		return err
+	} else {
+		tx.TokenAddress = addr
+	}
+	tx.Height = v.Height
+	if am, err := json_util.ParseAmount(v.Amount); err != nil {
+		return err
+	} else {
+		tx.Amount = am
+	}
+	if err := codec.CheckByteLength("TokenIssue.Tag", uint64(len(v.Tag)), 256); err != nil {
+		return err
+	}
+	tx.Tag = v.Tag
+	return nil
+}
@@ -0,0 +1,73 @@
+package tx_builder
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/errs"
+)
+
+type jsonTransaction interface {
+	transaction.Transaction
+	json.Marshaler
+	json.Unmarshaler
+}
+
+// Build returns the transaction of the name that has the fields of the JSON form of it
+// The fields that are not given keep the zero values and the timestamp is set to the current time when it is not given
+func Build(tran *data.Transactor, name string, fields map[string]json.RawMessage) (transaction.Transaction, error) {
+	t, err := tran.NewByTypeName(name)
+	if err != nil {
+		return nil, err
+	}
+	tx := t.(jsonTransaction)
+	bs, err := tx.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(bs, &obj); err != nil {
+		return nil, err
+	}
+	for k, v := range fields {
+		if k == "type" {
+			continue
+		}
+		if _, has := obj[k]; !has {
+			return nil, errs.WrapValue(ErrUnknownTransactionField, tx.Type(), k, nil, string(v))
+		}
+		obj[k] = v
+	}
+	if _, has := fields["timestamp"]; !has {
+		obj["timestamp"], _ = json.Marshal(uint64(time.Now().UnixNano()))
+	}
+	bs, err = json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.UnmarshalJSON(bs); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ParseField returns the key and the JSON value of the key=value form
+// The value is used as it is when it is a JSON value, otherwise it is used as a JSON string
+func ParseField(str string) (string, json.RawMessage, error) {
+	idx := strings.Index(str, "=")
+	if idx <= 0 {
+		return "", nil, errs.WrapField(ErrUnknownTransactionField, "field", "key=value", str)
+	}
+	key, value := str[:idx], str[idx+1:]
+	if json.Valid([]byte(value)) {
+		return key, json.RawMessage(value), nil
+	}
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return key, json.RawMessage(bs), nil
+}
@@ -0,0 +1,111 @@
+package tx_builder
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/account_tx"
+)
+
+func testTransactor(tb testing.TB) *data.Transactor {
+	tran, err := NewTransactor(common.NewCoordinate(0, 0), DefaultTypes())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return tran
+}
+
+func testFields(tb testing.TB, strs ...string) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	for _, str := range strs {
+		k, v, err := ParseField(str)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		fields[k] = v
+	}
+	return fields
+}
+
+func TestBuild(t *testing.T) {
+	tran := testTransactor(t)
+	from := common.NewAddress(common.NewCoordinate(1, 2), 0)
+	to := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	tx, err := Build(tran, "fleta.Transfer", testFields(t,
+		"timestamp=1234",
+		"seq=2",
+		"from="+from.String(),
+		"to="+to.String(),
+		"amount=1.5",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &account_tx.Transfer{
+		Base: account_tx.Base{
+			Seq_:  2,
+			From_: from,
+		},
+		Amount: amount.NewCoinAmount(1, 500000000000000000),
+		To:     account_name.NewAddressDestination(to),
+	}
+	expected.Type_ = 10
+	expected.Timestamp_ = 1234
+	if tx.Hash() != expected.Hash() {
+		t.Errorf("hash mismatch\n got %+v\nwant %+v", tx, expected)
+	}
+
+	named, err := Build(tran, "fleta.Transfer", testFields(t, `to={"name":"alice"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest := named.(*account_tx.Transfer).To; !dest.IsName() || dest.Name != "alice" {
+		t.Errorf("unexpected destination %v", dest)
+	}
+	if named.Timestamp() == 0 {
+		t.Error("timestamp is not set")
+	}
+}
+
+func TestBuildUnknownField(t *testing.T) {
+	tran := testTransactor(t)
+	if _, err := Build(tran, "fleta.Transfer", testFields(t, "amout=1")); !errors.Is(err, ErrUnknownTransactionField) {
+		t.Fatalf("expected %v but %v", ErrUnknownTransactionField, err)
+	}
+	if _, err := Build(tran, "fleta.NotExist", nil); err == nil {
+		t.Fatal("expected an error for the unknown transaction name")
+	}
+}
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		str   string
+		key   string
+		value string
+	}{
+		{"seq=1", "seq", `1`},
+		{"name=alice", "name", `"alice"`},
+		{`name="alice"`, "name", `"alice"`},
+		{"key_hashes=[]", "key_hashes", `[]`},
+		{"tag=", "tag", `""`},
+		{"url=a=b", "url", `"a=b"`},
+	}
+	for _, tt := range tests {
+		k, v, err := ParseField(tt.str)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.str, err)
+		}
+		if k != tt.key || string(v) != tt.value {
+			t.Errorf("%s: got %s=%s, want %s=%s", tt.str, k, v, tt.key, tt.value)
+		}
+	}
+	if _, _, err := ParseField("seq"); !errors.Is(err, ErrUnknownTransactionField) {
+		t.Errorf("expected %v but %v", ErrUnknownTransactionField, err)
+	}
+}
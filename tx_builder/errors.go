@@ -0,0 +1,19 @@
+package tx_builder
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// tx builder error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeUnknownTransactionField  errs.Code = 1801
+	CodeInvalidSignedTransaction errs.Code = 1802
+)
+
+// tx builder errors
+var (
+	ErrUnknownTransactionField     = errs.New(CodeUnknownTransactionField, "unknown transaction field")
+	ErrInvalidSignedTransaction    = errs.New(CodeInvalidSignedTransaction, "invalid signed transaction")
+	ErrInvalidTransactionSignature = errs.ErrInvalidTransactionSignature
+)
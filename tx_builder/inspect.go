@@ -0,0 +1,158 @@
+package tx_builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/errs"
+)
+
+// StatelessChecker is a transaction that validates the rules of it without the state of the chain
+type StatelessChecker interface {
+	CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error
+}
+
+// Violation is the rule that is failed by the transaction
+type Violation struct {
+	Code     errs.Code   `json:"code"`
+	Field    string      `json:"field,omitempty"`
+	Expected interface{} `json:"expected,omitempty"`
+	Actual   interface{} `json:"actual,omitempty"`
+	Message  string      `json:"message"`
+}
+
+// NewViolation returns the violation of the error
+// The field and the values are filled when the error is wrapped with the context
+func NewViolation(err error) *Violation {
+	v := &Violation{
+		Code:    errs.CodeOf(err),
+		Message: err.Error(),
+	}
+	var ce *errs.ContextError
+	if errors.As(err, &ce) {
+		v.Field = ce.Field
+		v.Expected = ce.Expected
+		v.Actual = ce.Actual
+	}
+	return v
+}
+
+// Inspection is the decoded transaction bytes with the result of the stateless validation
+type Inspection struct {
+	Name      string
+	Size      int
+	Signed    *Signed
+	Signers   []common.PublicHash
+	Checked   bool
+	Violation *Violation
+}
+
+// Inspect decodes the bytes of a transaction that may be followed by its signatures and validates the stateless rules of it on the chain of the coordinate
+// The error is returned only when the bytes cannot be decoded and a failed rule is reported by the violation of the inspection
+func Inspect(tran *data.Transactor, coord *common.Coordinate, bs []byte) (*Inspection, error) {
+	r := bytes.NewReader(bs)
+	lr := codec.NewLimitedReader(r, MaxSignedTransactionSize)
+	tx, read, err := readTransaction(tran, lr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Signed{
+		Tx:   tx,
+		Sigs: []common.Signature{},
+	}
+	if read < int64(len(bs)) {
+		if n, err := s.readSignatures(lr); err != nil {
+			return nil, err
+		} else {
+			read += n
+		}
+	}
+	if read != int64(len(bs)) {
+		return nil, errs.WrapValue(ErrInvalidSignedTransaction, tx.Type(), "length", read, len(bs))
+	}
+	name, err := tran.NameByType(tx.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	ins := &Inspection{
+		Name:   name,
+		Size:   len(bs),
+		Signed: s,
+	}
+	signers, err := s.Signers()
+	if err != nil {
+		ins.Violation = NewViolation(err)
+		return ins, nil
+	}
+	ins.Signers = signers
+	if sc, is := tx.(StatelessChecker); is {
+		ins.Checked = true
+		if err := sc.CheckStateless(coord, signers); err != nil {
+			ins.Violation = NewViolation(err)
+		}
+	}
+	return ins, nil
+}
+
+// MarshalJSON is a marshaler function
+func (ins *Inspection) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(ins.Name); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"size":`)
+	if bs, err := json.Marshal(ins.Size); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"signed":`)
+	if bs, err := ins.Signed.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"signers":`)
+	if bs, err := json.Marshal(ins.Signers); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"checked":`)
+	if bs, err := json.Marshal(ins.Checked); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"valid":`)
+	if bs, err := json.Marshal(ins.Checked && ins.Violation == nil); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	if ins.Violation != nil {
+		buffer.WriteString(`,`)
+		buffer.WriteString(`"violation":`)
+		if bs, err := json.Marshal(ins.Violation); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,111 @@
+package tx_builder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/key"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/errs"
+)
+
+func testSignedBytes(t *testing.T, tx transaction.Transaction, sign bool) []byte {
+	s := &Signed{Tx: tx}
+	if sign {
+		k, err := key.NewMemoryKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s, err = Sign(tx, k); err != nil {
+			t.Fatal(err)
+		}
+		bs, err := s.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bs
+	}
+	bs, err := s.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the unsigned form has no signature count
+	return bs[:len(bs)-1]
+}
+
+func TestInspect(t *testing.T) {
+	tran := testTransactor(t)
+	main := common.NewCoordinate(0, 0)
+	tests := []struct {
+		name   string
+		fields []string
+		coord  *common.Coordinate
+		sign   bool
+		code   errs.Code
+		field  string
+	}{
+		{"fleta.Transfer", []string{"seq=1", "amount=1"}, main, true, errs.CodeUnknown, ""},
+		{"fleta.Transfer", []string{"seq=1", "amount=1"}, main, false, errs.CodeUnknown, ""},
+		{"fleta.Transfer", []string{"seq=1", "amount=0.01"}, main, true, errs.CodeDustAmount, "amount"},
+		{"fleta.CreateAccount", []string{"seq=1", "name=testaccount"}, common.NewCoordinate(3, 1), true, errs.CodeNotMainChain, "chain_coord"},
+		{"fleta.CreateAccount", []string{"seq=1", "name=Test"}, main, true, errs.CodeInvalidAccountName, "name"},
+		{"fleta.CreateMultiSigAccount", []string{"seq=1", "name=testaccount"}, main, true, errs.CodeInvalidMultiSigKeyHashCount, "key_hashes"},
+		{"fleta.Assign", []string{}, main, true, errs.CodeInvalidTxInCount, "vin"},
+		{"fleta.Assign", []string{"vin=[1]"}, main, false, errs.CodeInvalidSignerCount, "signers"},
+		{"fleta.RegisterStandingOrder", []string{"seq=1", "amount=1", "count=1"}, main, true, errs.CodeInvalidInterval, "interval"},
+	}
+	for _, tt := range tests {
+		tx, err := Build(tran, tt.name, testFields(t, tt.fields...))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		ins, err := Inspect(tran, tt.coord, testSignedBytes(t, tx, tt.sign))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if ins.Name != tt.name || ins.Signed.Tx.Hash() != tx.Hash() || !ins.Checked {
+			t.Errorf("%s: unexpected inspection %+v", tt.name, ins)
+		}
+		if tt.sign && len(ins.Signers) != 1 {
+			t.Errorf("%s: expected a signer but %v", tt.name, ins.Signers)
+		}
+		if tt.code == errs.CodeUnknown {
+			if ins.Violation != nil {
+				t.Errorf("%s: unexpected violation %+v", tt.name, ins.Violation)
+			}
+			continue
+		}
+		if ins.Violation == nil {
+			t.Errorf("%s: expected the violation of %d", tt.name, tt.code)
+		} else if ins.Violation.Code != tt.code || ins.Violation.Field != tt.field {
+			t.Errorf("%s: got %d %s, want %d %s", tt.name, ins.Violation.Code, ins.Violation.Field, tt.code, tt.field)
+		}
+	}
+}
+
+func TestInspectInvalidBytes(t *testing.T) {
+	tran := testTransactor(t)
+	tx, err := Build(tran, "fleta.Burn", testFields(t, "seq=1", "amount=1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs := testSignedBytes(t, tx, true)
+	if _, err := Inspect(tran, common.NewCoordinate(0, 0), append(bs, 1, 2)); err == nil {
+		t.Error("expected an error for the trailing bytes")
+	}
+	if _, err := Inspect(tran, common.NewCoordinate(0, 0), []byte{200}); err == nil {
+		t.Error("expected an error for the unknown type")
+	}
+	if _, err := Inspect(tran, common.NewCoordinate(0, 0), bs[:10]); err == nil {
+		t.Error("expected an error for the truncated bytes")
+	}
+
+	v := NewViolation(errs.WrapValue(ErrInvalidSignedTransaction, 19, "length", 1, 2))
+	if v.Code != CodeInvalidSignedTransaction || v.Field != "length" || v.Expected != 1 || v.Actual != 2 {
+		t.Errorf("unexpected violation %+v", v)
+	}
+	if v := NewViolation(errors.New("plain")); v.Code != errs.CodeUnknown || len(v.Field) != 0 {
+		t.Errorf("unexpected violation %+v", v)
+	}
+}
@@ -0,0 +1,22 @@
+package tx_builder
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+
+	"github.com/fletaio/core/key"
+)
+
+// LoadKey returns the key of the file that has the hex string of the private key
+func LoadKey(path string) (key.Key, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := hex.DecodeString(strings.TrimSpace(string(bs)))
+	if err != nil {
+		return nil, err
+	}
+	return key.NewMemoryKeyFromBytes(pk)
+}
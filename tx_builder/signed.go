@@ -0,0 +1,225 @@
+package tx_builder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/key"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/errs"
+)
+
+// MaxSignedTransactionSize is the maximum byte size of a signed transaction that is decoded
+const MaxSignedTransactionSize = 1 << 20
+
+// MaxSignatureCount is the maximum number of the signatures of a signed transaction
+const MaxSignatureCount = 255
+
+// Signed is a transaction with its signatures
+// It is written as the transaction including its type followed by the signatures
+type Signed struct {
+	Tx   transaction.Transaction
+	Sigs []common.Signature
+}
+
+// Sign returns the signed transaction that is signed by the keys in order
+func Sign(tx transaction.Transaction, keys ...key.Key) (*Signed, error) {
+	s := &Signed{
+		Tx:   tx,
+		Sigs: []common.Signature{},
+	}
+	for _, k := range keys {
+		if err := s.AddSignature(k); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// AddSignature appends the signature of the key
+func (s *Signed) AddSignature(k key.Key) error {
+	sig, err := k.Sign(s.Tx.Hash())
+	if err != nil {
+		return err
+	}
+	s.Sigs = append(s.Sigs, sig)
+	return nil
+}
+
+// Signers returns the public hashes that are recovered from the signatures
+func (s *Signed) Signers() ([]common.PublicHash, error) {
+	h := s.Tx.Hash()
+	signers := make([]common.PublicHash, 0, len(s.Sigs))
+	for i, sig := range s.Sigs {
+		pubkey, err := common.RecoverPubkey(h, sig)
+		if err != nil {
+			return nil, errs.WrapValue(ErrInvalidTransactionSignature, s.Tx.Type(), "sigs", nil, i)
+		}
+		signers = append(signers, common.NewPublicHash(pubkey))
+	}
+	return signers, nil
+}
+
+// Verify checks that the signatures are signed by the expected signers in order
+func (s *Signed) Verify(expected []common.PublicHash) error {
+	signers, err := s.Signers()
+	if err != nil {
+		return err
+	}
+	if len(signers) != len(expected) {
+		return errs.WrapValue(ErrInvalidTransactionSignature, s.Tx.Type(), "sigs", len(expected), len(signers))
+	}
+	for i, signer := range signers {
+		if !signer.Equal(expected[i]) {
+			return errs.WrapValue(ErrInvalidTransactionSignature, s.Tx.Type(), "sigs", expected[i], signer)
+		}
+	}
+	return nil
+}
+
+// WriteTo is a serialization function
+func (s *Signed) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := s.Tx.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckItemCount("Signed.Sigs", uint64(len(s.Sigs)), MaxSignatureCount); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteUint8(w, uint8(len(s.Sigs))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+		for _, sig := range s.Sigs {
+			if n, err := sig.WriteTo(w); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	return wrote, nil
+}
+
+// Bytes returns the serialized bytes of it
+func (s *Signed) Bytes() ([]byte, error) {
+	var buffer bytes.Buffer
+	if _, err := s.WriteTo(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// ReadSigned reads a signed transaction whose type is registered to the transactor
+func ReadSigned(tran *data.Transactor, r io.Reader) (*Signed, int64, error) {
+	var read int64
+	lr := codec.NewLimitedReader(r, MaxSignedTransactionSize)
+	tx, n, err := readTransaction(tran, lr)
+	read += n
+	if err != nil {
+		return nil, read, err
+	}
+	s := &Signed{
+		Tx: tx,
+	}
+	if n, err := s.readSignatures(lr); err != nil {
+		return nil, read, err
+	} else {
+		read += n
+	}
+	return s, read, nil
+}
+
+func readTransaction(tran *data.Transactor, r io.Reader) (transaction.Transaction, int64, error) {
+	var read int64
+	t, n, err := util.ReadUint8(r)
+	read += n
+	if err != nil {
+		return nil, read, err
+	}
+	tx, err := tran.NewByType(transaction.Type(t))
+	if err != nil {
+		return nil, read, err
+	}
+	if n, err := tx.ReadFrom(io.MultiReader(bytes.NewReader([]byte{t}), r)); err != nil {
+		return nil, read, err
+	} else {
+		read += n - 1
+	}
+	return tx, read, nil
+}
+
+func (s *Signed) readSignatures(r io.Reader) (int64, error) {
+	var read int64
+	if Len, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		s.Sigs = make([]common.Signature, 0, Len)
+		for i := 0; i < int(Len); i++ {
+			var sig common.Signature
+			if n, err := sig.ReadFrom(r); err != nil {
+				return read, err
+			} else {
+				read += n
+			}
+			s.Sigs = append(s.Sigs, sig)
+		}
+	}
+	return read, nil
+}
+
+// DecodeSigned returns the signed transaction of the bytes
+// The bytes should be consumed entirely by the signed transaction
+func DecodeSigned(tran *data.Transactor, bs []byte) (*Signed, error) {
+	s, read, err := ReadSigned(tran, bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+	if read != int64(len(bs)) {
+		return nil, errs.WrapValue(ErrInvalidSignedTransaction, s.Tx.Type(), "length", read, len(bs))
+	}
+	return s, nil
+}
+
+// MarshalJSON is a marshaler function
+func (s *Signed) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"hash":`)
+	if bs, err := s.Tx.Hash().MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"tx":`)
+	if bs, err := json.Marshal(s.Tx); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"sigs":`)
+	buffer.WriteString(`[`)
+	for i, sig := range s.Sigs {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := json.Marshal(sig.String()); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
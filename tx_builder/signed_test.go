@@ -0,0 +1,79 @@
+package tx_builder
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/key"
+)
+
+func TestSignedBinary(t *testing.T) {
+	tran := testTransactor(t)
+	tx, err := Build(tran, "fleta.Withdraw", testFields(t, "seq=1", "timestamp=10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := key.NewMemoryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Sign(tx, k, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs, err := s.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := DecodeSigned(tran, bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.Tx.Hash() != tx.Hash() || len(ds.Sigs) != 2 {
+		t.Fatalf("signed mismatch %+v", ds)
+	}
+	rbs, err := ds.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bs, rbs) {
+		t.Errorf("binary mismatch\n got %x\nwant %x", rbs, bs)
+	}
+
+	if _, err := DecodeSigned(tran, append(bs, 0)); !errors.Is(err, ErrInvalidSignedTransaction) {
+		t.Errorf("expected %v but %v", ErrInvalidSignedTransaction, err)
+	}
+	if _, err := DecodeSigned(tran, bs[:len(bs)-1]); err == nil {
+		t.Error("expected an error for the truncated bytes")
+	}
+}
+
+func TestSignedVerify(t *testing.T) {
+	tran := testTransactor(t)
+	tx, err := Build(tran, "fleta.Burn", testFields(t, "seq=1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := key.NewMemoryKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Sign(tx, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := common.NewPublicHash(k.PublicKey())
+	if err := s.Verify([]common.PublicHash{signer}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(nil); !errors.Is(err, ErrInvalidTransactionSignature) {
+		t.Errorf("expected %v but %v", ErrInvalidTransactionSignature, err)
+	}
+	var other common.PublicHash
+	other[0] = signer[0] + 1
+	if err := s.Verify([]common.PublicHash{other}); !errors.Is(err, ErrInvalidTransactionSignature) {
+		t.Errorf("expected %v but %v", ErrInvalidTransactionSignature, err)
+	}
+}
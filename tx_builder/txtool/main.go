@@ -0,0 +1,321 @@
+// Command txtool builds, signs and verifies the extension transactions offline.
+//
+// A transaction is built from the JSON form of it and the key=value fields that override it:
+//
+//	txtool build -type fleta.Transfer -f from=3CUsUpv9v -f seq=1 -f to=4Jkt2E7N6 -f amount=1.5 -key from.key
+//
+// A value that is not a JSON value is used as a JSON string, so an object like the name destination is given as -f 'to={"name":"alice"}'.
+// The signed transaction is printed with its hash, the hex of its binary form and its JSON form.
+// The hex can be signed again by other keys and verified against the expected signers:
+//
+//	txtool sign -in <hex> -key second.key
+//	txtool verify -in <hex> -signer <public hash> -signer <public hash>
+//
+// The raw bytes of a transaction that is received from a chain can be inspected with or without the signatures.
+// It prints the decoded transaction, its hash and the recovered signers and reports the stateless rule that the transaction fails on the chain given by -chain:
+//
+//	txtool inspect -in <hex> -chain 0,0
+//
+// The transaction types are the default types of the FLETA chains and can be overridden by a JSON file of the name and type pairs given by -types.
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/key"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/tx_builder"
+)
+
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("txtool: ")
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "build":
+		err = build(os.Args[2:])
+	case "sign":
+		err = sign(os.Args[2:])
+	case "verify":
+		err = verify(os.Args[2:])
+	case "inspect":
+		err = inspect(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: txtool build|sign|verify|inspect [flags]")
+	os.Exit(2)
+}
+
+func build(args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	types := fs.String("types", "", "JSON file of the transaction names and types")
+	name := fs.String("type", "", "name of the transaction type such as fleta.Transfer")
+	jsonPath := fs.String("json", "", "JSON file of the transaction or - for the standard input")
+	var fields, keys listFlag
+	fs.Var(&fields, "f", "key=value field of the transaction (repeatable)")
+	fs.Var(&keys, "key", "key file to sign the transaction (repeatable)")
+	fs.Parse(args)
+
+	if len(*name) == 0 {
+		return errors.New("-type is required")
+	}
+	tran, err := newTransactor(*types)
+	if err != nil {
+		return err
+	}
+	m := map[string]json.RawMessage{}
+	if len(*jsonPath) > 0 {
+		bs, err := readInput(*jsonPath)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(bs, &m); err != nil {
+			return err
+		}
+	}
+	for _, f := range fields {
+		k, v, err := tx_builder.ParseField(f)
+		if err != nil {
+			return err
+		}
+		m[k] = v
+	}
+	tx, err := tx_builder.Build(tran, *name, m)
+	if err != nil {
+		return err
+	}
+	ks, err := loadKeys(keys)
+	if err != nil {
+		return err
+	}
+	s, err := tx_builder.Sign(tx, ks...)
+	if err != nil {
+		return err
+	}
+	return printSigned(s)
+}
+
+func sign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	types := fs.String("types", "", "JSON file of the transaction names and types")
+	in := fs.String("in", "-", "hex of the signed transaction or - for the standard input")
+	var keys listFlag
+	fs.Var(&keys, "key", "key file to sign the transaction (repeatable)")
+	fs.Parse(args)
+
+	s, err := readSigned(*types, *in)
+	if err != nil {
+		return err
+	}
+	ks, err := loadKeys(keys)
+	if err != nil {
+		return err
+	}
+	for _, k := range ks {
+		if err := s.AddSignature(k); err != nil {
+			return err
+		}
+	}
+	return printSigned(s)
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	types := fs.String("types", "", "JSON file of the transaction names and types")
+	in := fs.String("in", "-", "hex of the signed transaction or - for the standard input")
+	var signers listFlag
+	fs.Var(&signers, "signer", "expected public hash of the signer in order (repeatable)")
+	fs.Parse(args)
+
+	s, err := readSigned(*types, *in)
+	if err != nil {
+		return err
+	}
+	if err := printSigned(s); err != nil {
+		return err
+	}
+	if len(signers) > 0 {
+		expected := make([]common.PublicHash, 0, len(signers))
+		for _, str := range signers {
+			pubhash, err := common.ParsePublicHash(str)
+			if err != nil {
+				return err
+			}
+			expected = append(expected, pubhash)
+		}
+		if err := s.Verify(expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func inspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	types := fs.String("types", "", "JSON file of the transaction names and types")
+	in := fs.String("in", "-", "hex of the transaction or - for the standard input")
+	chain := fs.String("chain", "0,0", "coordinate of the chain as height,index")
+	fs.Parse(args)
+
+	coord, err := parseCoordinate(*chain)
+	if err != nil {
+		return err
+	}
+	tran, err := newTransactor(*types)
+	if err != nil {
+		return err
+	}
+	bs, err := readHex(*in)
+	if err != nil {
+		return err
+	}
+	ins, err := tx_builder.Inspect(tran, coord, bs)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(ins, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	if ins.Violation != nil {
+		return errors.New(ins.Violation.Message)
+	}
+	return nil
+}
+
+func parseCoordinate(str string) (*common.Coordinate, error) {
+	ls := strings.Split(str, ",")
+	if len(ls) != 2 {
+		return nil, fmt.Errorf("invalid coordinate %q", str)
+	}
+	Height, err := strconv.ParseUint(strings.TrimSpace(ls[0]), 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	Index, err := strconv.ParseUint(strings.TrimSpace(ls[1]), 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	return common.NewCoordinate(uint32(Height), uint16(Index)), nil
+}
+
+func newTransactor(path string) (*data.Transactor, error) {
+	types := tx_builder.DefaultTypes()
+	if len(path) > 0 {
+		bs, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		m := map[string]transaction.Type{}
+		if err := json.Unmarshal(bs, &m); err != nil {
+			return nil, err
+		}
+		for name, t := range m {
+			types[name] = t
+		}
+	}
+	return tx_builder.NewTransactor(common.NewCoordinate(0, 0), types)
+}
+
+func loadKeys(paths []string) ([]key.Key, error) {
+	ks := make([]key.Key, 0, len(paths))
+	for _, path := range paths {
+		k, err := tx_builder.LoadKey(path)
+		if err != nil {
+			return nil, err
+		}
+		ks = append(ks, k)
+	}
+	return ks, nil
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(bufio.NewReader(os.Stdin))
+	}
+	return ioutil.ReadFile(path)
+}
+
+func readHex(in string) ([]byte, error) {
+	str := in
+	if in == "-" {
+		bs, err := readInput(in)
+		if err != nil {
+			return nil, err
+		}
+		str = string(bs)
+	}
+	return hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(str), "0x"))
+}
+
+func readSigned(types string, in string) (*tx_builder.Signed, error) {
+	tran, err := newTransactor(types)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := readHex(in)
+	if err != nil {
+		return nil, err
+	}
+	return tx_builder.DecodeSigned(tran, bs)
+}
+
+func printSigned(s *tx_builder.Signed) error {
+	bs, err := s.Bytes()
+	if err != nil {
+		return err
+	}
+	signers, err := s.Signers()
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(&struct {
+		Hex     string              `json:"hex"`
+		Signed  *tx_builder.Signed  `json:"signed"`
+		Signers []common.PublicHash `json:"signers"`
+	}{
+		Hex:     hex.EncodeToString(bs),
+		Signed:  s,
+		Signers: signers,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
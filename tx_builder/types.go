@@ -0,0 +1,26 @@
+package tx_builder
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/type_registry"
+)
+
+// DefaultTypes are the transaction types of the extension transactions that are used by the FLETA chains
+func DefaultTypes() map[string]transaction.Type {
+	return type_registry.Default().Transactions
+}
+
+// NewTransactor returns a transactor that has the types
+// The fees are not registered because they are decided by the chain when the transaction is executed
+func NewTransactor(coord *common.Coordinate, types map[string]transaction.Type) (*data.Transactor, error) {
+	tran := data.NewTransactor(coord)
+	for name, t := range types {
+		if err := tran.RegisterType(name, t, amount.NewCoinAmount(0, 0)); err != nil {
+			return nil, err
+		}
+	}
+	return tran, nil
+}
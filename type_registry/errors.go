@@ -0,0 +1,18 @@
+package type_registry
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// type registry error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeIncompatibleTypeTable errs.Code = 2101
+	CodeMissingTransactionFee errs.Code = 2102
+)
+
+// type registry errors
+var (
+	ErrIncompatibleTypeTable = errs.New(CodeIncompatibleTypeTable, "incompatible type table")
+	ErrMissingTransactionFee = errs.New(CodeMissingTransactionFee, "missing transaction fee")
+)
@@ -0,0 +1,259 @@
+// Package type_registry holds the canonical type ids of the extension transactions, accounts and events.
+//
+// A transaction, an account or an event is encoded with the type id that is registered by the chain,
+// so chains that exchange them (like a main chain and its dapp chains) should register the same ids for the same names.
+// The ids of the default table are stable and new types get new ids without changing the existing ones.
+package type_registry
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/fletaio/core/account"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/event"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/errs"
+
+	_ "github.com/fletaio/extension/account_def"
+	_ "github.com/fletaio/extension/account_tx"
+	_ "github.com/fletaio/extension/event_def"
+	_ "github.com/fletaio/extension/standing_order"
+	_ "github.com/fletaio/extension/token_tx"
+	_ "github.com/fletaio/extension/utxo_tx"
+)
+
+// Table is the type ids of the transactions, accounts and events of a chain
+type Table struct {
+	Transactions map[string]transaction.Type
+	Accounts     map[string]account.Type
+	Events       map[string]event.Type
+}
+
+// Default returns the table of the stable type ids of the extension types
+func Default() *Table {
+	return &Table{
+		Transactions: map[string]transaction.Type{
+			// FLETA Transactions
+			"fleta.Transfer":              10,
+			"fleta.Withdraw":              18,
+			"fleta.Burn":                  19,
+			"fleta.CreateAccount":         20,
+			"fleta.CreateMultiSigAccount": 21,
+			"fleta.RegisterStandingOrder": 22,
+			"fleta.CancelStandingOrder":   23,
+			"fleta.SetAccountPolicy":      24,
+			"fleta.TransferName":          25,
+			"fleta.CloseAccount":          26,
+			// UTXO Transactions
+			"fleta.Assign":      30,
+			"fleta.Deposit":     38,
+			"fleta.OpenAccount": 41,
+			// Token Transactions
+			"fleta.TokenCreation":       50,
+			"fleta.ChainInitialization": 51,
+			"fleta.TokenIssue":          52,
+			"fleta.EngraveDapp":         53,
+		},
+		Accounts: map[string]account.Type{
+			"fleta.SingleAccount":   10,
+			"fleta.MultiSigAccount": 11,
+			"fleta.TokenAccount":    12,
+			"fleta.LockedAccount":   19,
+		},
+		Events: map[string]event.Type{
+			// FLETA Events
+			"fleta.TransferEvent":       10,
+			"fleta.AccountCreatedEvent": 11,
+			// UTXO Events
+			"fleta.UTXOCreatedEvent": 20,
+			"fleta.UTXOSpentEvent":   21,
+			// Token Events
+			"fleta.TokenCreatedEvent":     30,
+			"fleta.ChainInitializedEvent": 31,
+			"fleta.TokenIssuedEvent":      32,
+			"fleta.DappEngravedEvent":     33,
+		},
+	}
+}
+
+// DefaultFees returns the fees of the extension transactions that are used by the FLETA chains
+func DefaultFees() map[string]*amount.Amount {
+	return map[string]*amount.Amount{
+		"fleta.Transfer":              amount.COIN.DivC(10),
+		"fleta.Withdraw":              amount.COIN.DivC(10),
+		"fleta.Burn":                  amount.COIN.DivC(10),
+		"fleta.CreateAccount":         amount.COIN.MulC(10),
+		"fleta.CreateMultiSigAccount": amount.COIN.MulC(10),
+		"fleta.RegisterStandingOrder": amount.COIN.DivC(10),
+		"fleta.CancelStandingOrder":   amount.COIN.DivC(10),
+		"fleta.SetAccountPolicy":      amount.COIN.DivC(10),
+		"fleta.TransferName":          amount.COIN.DivC(10),
+		"fleta.CloseAccount":          amount.COIN.DivC(10),
+		"fleta.Assign":                amount.COIN.DivC(2),
+		"fleta.Deposit":               amount.COIN.DivC(2),
+		"fleta.OpenAccount":           amount.COIN.MulC(10),
+		"fleta.TokenCreation":         amount.COIN.MulC(10),
+		"fleta.ChainInitialization":   amount.COIN.MulC(10),
+		"fleta.TokenIssue":            amount.COIN.MulC(10),
+		"fleta.EngraveDapp":           amount.COIN.MulC(10),
+	}
+}
+
+// Clone returns the copy of the table
+func (t *Table) Clone() *Table {
+	c := &Table{
+		Transactions: map[string]transaction.Type{},
+		Accounts:     map[string]account.Type{},
+		Events:       map[string]event.Type{},
+	}
+	for name, v := range t.Transactions {
+		c.Transactions[name] = v
+	}
+	for name, v := range t.Accounts {
+		c.Accounts[name] = v
+	}
+	for name, v := range t.Events {
+		c.Events[name] = v
+	}
+	return c
+}
+
+// Without returns the copy of the table that does not have the names
+func (t *Table) Without(names ...string) *Table {
+	c := t.Clone()
+	for _, name := range names {
+		delete(c.Transactions, name)
+		delete(c.Accounts, name)
+		delete(c.Events, name)
+	}
+	return c
+}
+
+// Register registers all types of the table to the accounter, the transactor and the eventer
+// Every transaction of the table should have its fee
+func (t *Table) Register(act *data.Accounter, tran *data.Transactor, evt *data.Eventer, fees map[string]*amount.Amount) error {
+	for _, name := range sortedNames(transactionIDs(t.Transactions)) {
+		fee, has := fees[name]
+		if !has {
+			return errs.WrapField(ErrMissingTransactionFee, "fees", nil, name)
+		}
+		if err := tran.RegisterType(name, t.Transactions[name], fee); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedNames(accountIDs(t.Accounts)) {
+		if err := act.RegisterType(name, t.Accounts[name]); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedNames(eventIDs(t.Events)) {
+		if err := evt.RegisterType(name, t.Events[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Register registers all extension types of the default table with the default fees
+func Register(act *data.Accounter, tran *data.Transactor, evt *data.Eventer) error {
+	return Default().Register(act, tran, evt, DefaultFees())
+}
+
+// Conflict is a name or a type id that is mapped differently by two tables
+type Conflict struct {
+	Kind string // transaction, account or event
+	Key  string // the name or the type id
+	A    string // the type id or the name of the first table
+	B    string // the type id or the name of the second table
+}
+
+// Compare returns the conflicts between the tables
+// A name that is registered by only one table is not a conflict because it cannot be decoded by the other silently
+func Compare(a *Table, b *Table) []Conflict {
+	conflicts := []Conflict{}
+	conflicts = append(conflicts, compare("transaction", transactionIDs(a.Transactions), transactionIDs(b.Transactions))...)
+	conflicts = append(conflicts, compare("account", accountIDs(a.Accounts), accountIDs(b.Accounts))...)
+	conflicts = append(conflicts, compare("event", eventIDs(a.Events), eventIDs(b.Events))...)
+	return conflicts
+}
+
+// CheckCompatible returns an error of the first conflict when the tables are not compatible
+func CheckCompatible(a *Table, b *Table) error {
+	conflicts := Compare(a, b)
+	if len(conflicts) > 0 {
+		c := conflicts[0]
+		return errs.WrapField(ErrIncompatibleTypeTable, c.Kind+" "+c.Key, c.A, c.B)
+	}
+	return nil
+}
+
+func compare(kind string, a map[string]uint8, b map[string]uint8) []Conflict {
+	conflicts := []Conflict{}
+	for _, name := range sortedNames(a) {
+		if t, has := b[name]; has && t != a[name] {
+			conflicts = append(conflicts, Conflict{
+				Kind: kind,
+				Key:  name,
+				A:    strconv.Itoa(int(a[name])),
+				B:    strconv.Itoa(int(t)),
+			})
+		}
+	}
+	aNames := map[uint8]string{}
+	for name, t := range a {
+		aNames[t] = name
+	}
+	for _, name := range sortedNames(b) {
+		t := b[name]
+		if aName, has := aNames[t]; has && aName != name {
+			if _, has := b[aName]; has {
+				continue
+			}
+			if _, has := a[name]; has {
+				continue
+			}
+			conflicts = append(conflicts, Conflict{
+				Kind: kind,
+				Key:  strconv.Itoa(int(t)),
+				A:    aName,
+				B:    name,
+			})
+		}
+	}
+	return conflicts
+}
+
+func transactionIDs(m map[string]transaction.Type) map[string]uint8 {
+	ids := map[string]uint8{}
+	for name, t := range m {
+		ids[name] = uint8(t)
+	}
+	return ids
+}
+
+func accountIDs(m map[string]account.Type) map[string]uint8 {
+	ids := map[string]uint8{}
+	for name, t := range m {
+		ids[name] = uint8(t)
+	}
+	return ids
+}
+
+func eventIDs(m map[string]event.Type) map[string]uint8 {
+	ids := map[string]uint8{}
+	for name, t := range m {
+		ids[name] = uint8(t)
+	}
+	return ids
+}
+
+func sortedNames(m map[string]uint8) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,121 @@
+package type_registry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/extension/errs"
+)
+
+func TestDefault(t *testing.T) {
+	table := Default()
+	fees := DefaultFees()
+	if len(fees) != len(table.Transactions) {
+		t.Fatalf("expected %d fees but %d", len(table.Transactions), len(fees))
+	}
+	for name := range table.Transactions {
+		if _, has := fees[name]; !has {
+			t.Errorf("%s has no default fee", name)
+		}
+	}
+	txIDs := map[uint8]string{}
+	for name, v := range table.Transactions {
+		if prev, has := txIDs[uint8(v)]; has {
+			t.Errorf("%s and %s have the same type %d", prev, name, v)
+		}
+		txIDs[uint8(v)] = name
+	}
+	accIDs := map[uint8]string{}
+	for name, v := range table.Accounts {
+		if prev, has := accIDs[uint8(v)]; has {
+			t.Errorf("%s and %s have the same type %d", prev, name, v)
+		}
+		accIDs[uint8(v)] = name
+	}
+	evIDs := map[uint8]string{}
+	for name, v := range table.Events {
+		if prev, has := evIDs[uint8(v)]; has {
+			t.Errorf("%s and %s have the same type %d", prev, name, v)
+		}
+		evIDs[uint8(v)] = name
+	}
+	if table.Transactions["fleta.Transfer"] != 10 || table.Accounts["fleta.SingleAccount"] != 10 || table.Events["fleta.TransferEvent"] != 10 {
+		t.Error("the default type ids are changed")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	coord := common.NewCoordinate(0, 0)
+	act := data.NewAccounter(coord)
+	tran := data.NewTransactor(coord)
+	evt := data.NewEventer(coord)
+	if err := Register(act, tran, evt); err != nil {
+		t.Fatal(err)
+	}
+	for name, v := range Default().Transactions {
+		tx, err := tran.NewByTypeName(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if tx.Type() != v {
+			t.Errorf("%s: expected the type %d but %d", name, v, tx.Type())
+		}
+	}
+	if fee, err := tran.Fee(10); err != nil || !fee.Equal(amount.COIN.DivC(10)) {
+		t.Errorf("unexpected fee %v %v", fee, err)
+	}
+	for name, v := range Default().Accounts {
+		acc, err := act.NewByTypeName(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if acc.Type() != v {
+			t.Errorf("%s: expected the type %d but %d", name, v, acc.Type())
+		}
+	}
+
+	fees := DefaultFees()
+	delete(fees, "fleta.Burn")
+	err := Default().Register(data.NewAccounter(coord), data.NewTransactor(coord), data.NewEventer(coord), fees)
+	if errs.CodeOf(err) != CodeMissingTransactionFee {
+		t.Errorf("expected the missing transaction fee but %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	main := Default()
+	dapp := Default().Without("fleta.RegisterStandingOrder", "fleta.CancelStandingOrder")
+	if _, has := dapp.Transactions["fleta.RegisterStandingOrder"]; has {
+		t.Fatal("the name is not removed")
+	}
+	if _, has := main.Transactions["fleta.RegisterStandingOrder"]; !has {
+		t.Fatal("the original table is changed")
+	}
+	if err := CheckCompatible(main, dapp); err != nil {
+		t.Fatal(err)
+	}
+
+	other := Default()
+	other.Transactions["fleta.Transfer"] = 11
+	other.Accounts["custom.Account"] = 10
+	delete(other.Accounts, "fleta.SingleAccount")
+	conflicts := Compare(main, other)
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts but %v", conflicts)
+	}
+	if c := conflicts[0]; c.Kind != "transaction" || c.Key != "fleta.Transfer" || c.A != "10" || c.B != "11" {
+		t.Errorf("unexpected conflict %+v", c)
+	}
+	if c := conflicts[1]; c.Kind != "account" || c.Key != "10" || c.A != "fleta.SingleAccount" || c.B != "custom.Account" {
+		t.Errorf("unexpected conflict %+v", c)
+	}
+
+	err := CheckCompatible(main, other)
+	var cerr *errs.ContextError
+	if errs.CodeOf(err) != CodeIncompatibleTypeTable || !errors.As(err, &cerr) || cerr.Field != "transaction fleta.Transfer" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
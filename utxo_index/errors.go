@@ -0,0 +1,18 @@
+package utxo_index
+
+import (
+	"github.com/fletaio/extension/errs"
+)
+
+// utxo index error codes
+// The codes are stable and must not be changed or reused
+const (
+	CodeInvalidUTXOIndexHeight errs.Code = 2401
+	CodeNotRollbackableHeight  errs.Code = 2402
+)
+
+// utxo index errors
+var (
+	ErrInvalidUTXOIndexHeight = errs.New(CodeInvalidUTXOIndexHeight, "invalid utxo index height")
+	ErrNotRollbackableHeight  = errs.New(CodeNotRollbackableHeight, "not rollbackable height")
+)
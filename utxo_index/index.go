@@ -0,0 +1,591 @@
+// Package utxo_index indexes the unspent outputs of the chain by their public hashes.
+//
+// The loader only finds a UTXO by its id, so a wallet cannot list the outputs that it can spend.
+// The index follows the processed blocks: the outputs of Withdraw, Assign, Deposit and OpenAccount are added and the inputs of Assign, Deposit and OpenAccount are removed.
+// Each indexed block keeps its undo data for RollbackDepth blocks so the index can be rolled back when the blocks are replaced.
+// The hash of each indexed block is kept, and the index is rolled back automatically when a different block comes at an indexed height.
+package utxo_index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/hash"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/kernel"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/store"
+	"github.com/fletaio/extension/utxo_tx"
+)
+
+// RollbackDepth is the number of the latest blocks that can be rolled back
+const RollbackDepth = 1024
+
+// key tags of the store
+var (
+	tagHeight     = []byte{0x01}
+	tagGenesis    = []byte{0x02}
+	tagHash       = []byte{0x03}
+	tagUTXO       = []byte{0x10}
+	tagPublicHash = []byte{0x11}
+	tagBalance    = []byte{0x12}
+	tagUndo       = []byte{0x20}
+)
+
+// Index is the unspent outputs by the public hashes
+type Index struct {
+	sync.Mutex
+	st store.Store
+}
+
+// NewIndex returns the index that is kept in the store
+func NewIndex(st store.Store) *Index {
+	return &Index{
+		st: st,
+	}
+}
+
+// Height returns the height of the last indexed block
+func (idx *Index) Height() (uint32, error) {
+	bs, err := idx.st.Get(tagHeight)
+	if err != nil {
+		return 0, err
+	}
+	if bs == nil {
+		return 0, nil
+	}
+	if len(bs) != 4 {
+		return 0, errs.WrapField(store.ErrCorruptedStore, "height", 4, len(bs))
+	}
+	return binary.BigEndian.Uint32(bs), nil
+}
+
+// Hash returns the hash of the indexed block of the height
+// It returns false when the block is not indexed or is indexed without its hash
+func (idx *Index) Hash(height uint32) (hash.Hash256, bool, error) {
+	bs, err := idx.st.Get(hashKey(height))
+	if err != nil {
+		return hash.Hash256{}, false, err
+	}
+	if bs == nil {
+		return hash.Hash256{}, false, nil
+	}
+	var h hash.Hash256
+	if len(bs) != len(h) {
+		return hash.Hash256{}, false, errs.WrapField(store.ErrCorruptedStore, "hash", len(h), len(bs))
+	}
+	copy(h[:], bs)
+	return h, true, nil
+}
+
+// IndexGenesis adds the UTXOs of the genesis context data that are not created by a block
+// It is ignored when the genesis is already indexed
+func (idx *Index) IndexGenesis(ctd *data.ContextData) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	if bs, err := idx.st.Get(tagGenesis); err != nil {
+		return err
+	} else if bs != nil {
+		return nil
+	}
+	height, err := idx.Height()
+	if err != nil {
+		return err
+	}
+	if height != 0 {
+		return errs.WrapField(ErrInvalidUTXOIndexHeight, "height", 0, height)
+	}
+
+	ids := make([]uint64, 0, len(ctd.CreatedUTXOMap))
+	for id := range ctd.CreatedUTXOMap {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	u := newUpdate(idx.st)
+	for _, id := range ids {
+		if err := u.create(id, ctd.CreatedUTXOMap[id]); err != nil {
+			return err
+		}
+	}
+	batch := u.batch()
+	batch.Set(tagGenesis, []byte{1})
+	return idx.st.Apply(batch)
+}
+
+// IndexBlock applies the transactions of the block that should be the next of the last indexed block
+// A block that is already indexed is ignored, and a block that replaces an indexed block rolls back the index from its height before it is applied
+func (idx *Index) IndexBlock(b *block.Block) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	height, err := idx.Height()
+	if err != nil {
+		return err
+	}
+	if b.Header.Height() <= height {
+		h, has, err := idx.Hash(b.Header.Height())
+		if err != nil {
+			return err
+		}
+		if !has || h.Equal(b.Header.Hash()) {
+			return nil
+		}
+		if err := idx.rollback(b.Header.Height() - 1); err != nil {
+			return err
+		}
+		height = b.Header.Height() - 1
+	}
+	if b.Header.Height() != height+1 {
+		return errs.WrapField(ErrInvalidUTXOIndexHeight, "height", height+1, b.Header.Height())
+	}
+
+	u := newUpdate(idx.st)
+	for i, t := range b.Body.Transactions {
+		coord := common.NewCoordinate(b.Header.Height(), uint16(i))
+		var vin []*transaction.TxIn
+		var vout []*transaction.TxOut
+		switch tx := t.(type) {
+		case *account_tx.Withdraw:
+			vout = tx.Vout
+		case *utxo_tx.Assign:
+			vin, vout = tx.Vin, tx.Vout
+		case *utxo_tx.Deposit:
+			vin, vout = tx.Vin, tx.Vout
+		case *utxo_tx.OpenAccount:
+			vin, vout = tx.Vin, tx.Vout
+		}
+		for _, in := range vin {
+			if err := u.spend(in.ID()); err != nil {
+				return err
+			}
+		}
+		for n, out := range vout {
+			if err := u.create(transaction.MarshalID(coord.Height, coord.Index, uint16(n)), out); err != nil {
+				return err
+			}
+		}
+	}
+
+	batch := u.batch()
+	var buffer bytes.Buffer
+	if _, err := u.undo.WriteTo(&buffer); err != nil {
+		return err
+	}
+	batch.Set(undoKey(b.Header.Height()), buffer.Bytes())
+	if b.Header.Height() > RollbackDepth {
+		batch.Delete(undoKey(b.Header.Height() - RollbackDepth))
+	}
+	h := b.Header.Hash()
+	batch.Set(hashKey(b.Header.Height()), h[:])
+	batch.Set(tagHeight, heightBytes(b.Header.Height()))
+	return idx.st.Apply(batch)
+}
+
+// Rollback reverts the indexed blocks that are higher than the height
+func (idx *Index) Rollback(height uint32) error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	return idx.rollback(height)
+}
+
+func (idx *Index) rollback(height uint32) error {
+	top, err := idx.Height()
+	if err != nil {
+		return err
+	}
+	if height >= top {
+		return nil
+	}
+	if top-height > RollbackDepth {
+		return errs.WrapField(ErrNotRollbackableHeight, "height", top-RollbackDepth, height)
+	}
+	for h := top; h > height; h-- {
+		bs, err := idx.st.Get(undoKey(h))
+		if err != nil {
+			return err
+		}
+		if bs == nil {
+			return errs.WrapField(ErrNotRollbackableHeight, "height", nil, h)
+		}
+		undo := &undoData{}
+		if _, err := undo.ReadFrom(bytes.NewReader(bs)); err != nil {
+			return err
+		}
+
+		// the spent outputs are restored first because an output can be created and spent in the same block
+		u := newUpdate(idx.st)
+		for i := len(undo.Spent) - 1; i >= 0; i-- {
+			if err := u.create(undo.Spent[i].ID(), undo.Spent[i].TxOut); err != nil {
+				return err
+			}
+		}
+		for i := len(undo.Created) - 1; i >= 0; i-- {
+			if err := u.spend(undo.Created[i]); err != nil {
+				return err
+			}
+		}
+		batch := u.batch()
+		batch.Delete(undoKey(h))
+		batch.Delete(hashKey(h))
+		batch.Set(tagHeight, heightBytes(h-1))
+		if err := idx.st.Apply(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync indexes the blocks of the provider from the next of the last indexed block
+// The indexed blocks whose hashes are different from the blocks of the provider are rolled back first
+func (idx *Index) Sync(provider kernel.Provider) error {
+	height, err := idx.Height()
+	if err != nil {
+		return err
+	}
+	fork := height
+	if fork > provider.Height() {
+		fork = provider.Height()
+	}
+	for ; fork > 0; fork-- {
+		h, has, err := idx.Hash(fork)
+		if err != nil {
+			return err
+		}
+		if !has {
+			break
+		}
+		if ph, err := provider.Hash(fork); err != nil {
+			return err
+		} else if h.Equal(ph) {
+			break
+		}
+	}
+	if fork < height && fork < provider.Height() {
+		if err := idx.Rollback(fork); err != nil {
+			return err
+		}
+		height = fork
+	}
+	for h := height + 1; h <= provider.Height(); h++ {
+		b, err := provider.Block(h)
+		if err != nil {
+			return err
+		}
+		if err := idx.IndexBlock(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rebuild removes the index and indexes the genesis and all blocks of the provider
+// The genesis context data can be nil when the chain has no genesis UTXO
+func (idx *Index) Rebuild(genesis *data.ContextData, provider kernel.Provider) error {
+	if err := idx.clear(); err != nil {
+		return err
+	}
+	if genesis != nil {
+		if err := idx.IndexGenesis(genesis); err != nil {
+			return err
+		}
+	}
+	return idx.Sync(provider)
+}
+
+func (idx *Index) clear() error {
+	idx.Lock()
+	defer idx.Unlock()
+
+	batch := store.NewBatch()
+	if err := idx.st.Iterate(nil, false, func(key []byte, value []byte) bool {
+		batch.Delete(key)
+		return true
+	}); err != nil {
+		return err
+	}
+	return idx.st.Apply(batch)
+}
+
+// UTXOs returns the unspent outputs of the public hash in the order of their ids
+func (idx *Index) UTXOs(pubhash common.PublicHash) ([]*transaction.UTXO, error) {
+	prefix := append(append([]byte{}, tagPublicHash...), pubhash[:]...)
+	ids := []uint64{}
+	if err := idx.st.Iterate(prefix, false, func(key []byte, value []byte) bool {
+		if len(key) == len(prefix)+8 {
+			ids = append(ids, binary.BigEndian.Uint64(key[len(prefix):]))
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	list := make([]*transaction.UTXO, 0, len(ids))
+	for _, id := range ids {
+		out, err := getTxOut(idx.st, id)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			continue
+		}
+		list = append(list, &transaction.UTXO{
+			TxIn:  transaction.NewTxIn(id),
+			TxOut: out,
+		})
+	}
+	return list, nil
+}
+
+// Balance returns the sum of the unspent outputs of the public hash
+func (idx *Index) Balance(pubhash common.PublicHash) (*amount.Amount, error) {
+	return getBalance(idx.st, pubhash)
+}
+
+func getTxOut(st store.Store, id uint64) (*transaction.TxOut, error) {
+	bs, err := st.Get(utxoKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		return nil, nil
+	}
+	out := transaction.NewTxOut()
+	if _, err := out.ReadFrom(bytes.NewReader(bs)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func getBalance(st store.Store, pubhash common.PublicHash) (*amount.Amount, error) {
+	bs, err := st.Get(balanceKey(pubhash))
+	if err != nil {
+		return nil, err
+	}
+	am := amount.NewCoinAmount(0, 0)
+	if bs == nil {
+		return am, nil
+	}
+	if _, err := am.ReadFrom(bytes.NewReader(bs)); err != nil {
+		return nil, err
+	}
+	return am, nil
+}
+
+// update collects the changes of a block on the store
+// The outputs that are created in the block can be spent in the same block
+type update struct {
+	st         store.Store
+	outMap     map[uint64]*transaction.TxOut
+	balanceMap map[common.PublicHash]*amount.Amount
+	undo       *undoData
+}
+
+func newUpdate(st store.Store) *update {
+	return &update{
+		st:         st,
+		outMap:     map[uint64]*transaction.TxOut{},
+		balanceMap: map[common.PublicHash]*amount.Amount{},
+		undo:       &undoData{},
+	}
+}
+
+func (u *update) txOut(id uint64) (*transaction.TxOut, error) {
+	if out, has := u.outMap[id]; has {
+		return out, nil
+	}
+	return getTxOut(u.st, id)
+}
+
+func (u *update) balance(pubhash common.PublicHash) (*amount.Amount, error) {
+	if am, has := u.balanceMap[pubhash]; has {
+		return am, nil
+	}
+	return getBalance(u.st, pubhash)
+}
+
+func (u *update) create(id uint64, out *transaction.TxOut) error {
+	am, err := u.balance(out.PublicHash)
+	if err != nil {
+		return err
+	}
+	u.outMap[id] = out.Clone()
+	u.balanceMap[out.PublicHash] = am.Add(out.Amount)
+	u.undo.Created = append(u.undo.Created, id)
+	return nil
+}
+
+// spend ignores the output that is not indexed like the one that is created before the index is started
+func (u *update) spend(id uint64) error {
+	out, err := u.txOut(id)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	am, err := u.balance(out.PublicHash)
+	if err != nil {
+		return err
+	}
+	u.outMap[id] = nil
+	u.balanceMap[out.PublicHash] = am.Sub(out.Amount)
+	u.undo.Spent = append(u.undo.Spent, &transaction.UTXO{
+		TxIn:  transaction.NewTxIn(id),
+		TxOut: out,
+	})
+	return nil
+}
+
+func (u *update) batch() *store.Batch {
+	batch := store.NewBatch()
+	for id, out := range u.outMap {
+		if out == nil {
+			if prev, err := getTxOut(u.st, id); err == nil && prev != nil {
+				batch.Delete(publicHashKey(prev.PublicHash, id))
+			}
+			batch.Delete(utxoKey(id))
+		} else {
+			var buffer bytes.Buffer
+			out.WriteTo(&buffer)
+			batch.Set(utxoKey(id), buffer.Bytes())
+			batch.Set(publicHashKey(out.PublicHash, id), []byte{})
+		}
+	}
+	for pubhash, am := range u.balanceMap {
+		if am.IsZero() {
+			batch.Delete(balanceKey(pubhash))
+		} else {
+			var buffer bytes.Buffer
+			am.WriteTo(&buffer)
+			batch.Set(balanceKey(pubhash), buffer.Bytes())
+		}
+	}
+	return batch
+}
+
+// undoData is the outputs that are created and spent by a block
+type undoData struct {
+	Created []uint64
+	Spent   []*transaction.UTXO
+}
+
+// WriteTo is a serialization function
+func (ud *undoData) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := util.WriteUint32(w, uint32(len(ud.Created))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, id := range ud.Created {
+		if n, err := util.WriteUint64(w, id); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	if n, err := util.WriteUint32(w, uint32(len(ud.Spent))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	for _, utxo := range ud.Spent {
+		if n, err := util.WriteUint64(w, utxo.ID()); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+		if n, err := utxo.TxOut.WriteTo(w); err != nil {
+			return wrote, err
+		} else {
+			wrote += n
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (ud *undoData) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if Len, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		ud.Created = make([]uint64, 0, Len)
+		for i := uint32(0); i < Len; i++ {
+			if v, n, err := util.ReadUint64(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				ud.Created = append(ud.Created, v)
+			}
+		}
+	}
+	if Len, n, err := util.ReadUint32(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		ud.Spent = make([]*transaction.UTXO, 0, Len)
+		for i := uint32(0); i < Len; i++ {
+			utxo := &transaction.UTXO{
+				TxOut: transaction.NewTxOut(),
+			}
+			if v, n, err := util.ReadUint64(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				utxo.TxIn = transaction.NewTxIn(v)
+			}
+			if n, err := utxo.TxOut.ReadFrom(r); err != nil {
+				return read, err
+			} else {
+				read += n
+			}
+			ud.Spent = append(ud.Spent, utxo)
+		}
+	}
+	return read, nil
+}
+
+func heightBytes(height uint32) []byte {
+	bs := make([]byte, 4)
+	binary.BigEndian.PutUint32(bs, height)
+	return bs
+}
+
+func hashKey(height uint32) []byte {
+	return append(append([]byte{}, tagHash...), heightBytes(height)...)
+}
+
+func undoKey(height uint32) []byte {
+	return append(append([]byte{}, tagUndo...), heightBytes(height)...)
+}
+
+func utxoKey(id uint64) []byte {
+	bs := make([]byte, 8)
+	binary.BigEndian.PutUint64(bs, id)
+	return append(append([]byte{}, tagUTXO...), bs...)
+}
+
+func publicHashKey(pubhash common.PublicHash, id uint64) []byte {
+	bs := make([]byte, 8)
+	binary.BigEndian.PutUint64(bs, id)
+	key := append(append([]byte{}, tagPublicHash...), pubhash[:]...)
+	return append(key, bs...)
+}
+
+func balanceKey(pubhash common.PublicHash) []byte {
+	return append(append([]byte{}, tagBalance...), pubhash[:]...)
+}
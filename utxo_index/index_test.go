@@ -0,0 +1,207 @@
+package utxo_index
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/block"
+	"github.com/fletaio/core/data"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_tx"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/internal/test_util"
+	"github.com/fletaio/extension/store"
+	"github.com/fletaio/extension/utxo_tx"
+)
+
+func openTestIndex(t *testing.T) (*Index, func()) {
+	dir, err := ioutil.TempDir("", "utxo_index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.OpenFileStore(filepath.Join(dir, "utxo.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return NewIndex(st), func() {
+		st.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func testTxOut(n byte, am uint64) *transaction.TxOut {
+	return &transaction.TxOut{
+		Amount:     amount.NewCoinAmount(am, 0),
+		PublicHash: test_util.PublicHash(n),
+	}
+}
+
+// testBlocks returns the blocks that
+// 1: withdraws 10 and 5 to A
+// 2: assigns the 10 of A to 4 of A and 6 of B, and spends the 4 of A in the same block by a deposit
+func testBlocks() []*block.Block {
+	withdraw := &account_tx.Withdraw{
+		Vout: []*transaction.TxOut{testTxOut(1, 10), testTxOut(1, 5)},
+	}
+	assign := &utxo_tx.Assign{
+		Base: utxo_tx.Base{Vin: []*transaction.TxIn{transaction.NewTxIn(transaction.MarshalID(1, 0, 0))}},
+		Vout: []*transaction.TxOut{testTxOut(1, 4), testTxOut(2, 6)},
+	}
+	deposit := &utxo_tx.Deposit{
+		Base: utxo_tx.Base{Vin: []*transaction.TxIn{transaction.NewTxIn(transaction.MarshalID(2, 0, 0))}},
+	}
+	return []*block.Block{
+		{Header: block.Header{Height_: 1}, Body: block.Body{Transactions: []transaction.Transaction{withdraw}}},
+		{Header: block.Header{Height_: 2}, Body: block.Body{Transactions: []transaction.Transaction{assign, deposit}}},
+	}
+}
+
+func checkBalance(t *testing.T, idx *Index, n byte, am uint64, count int) {
+	if balance, err := idx.Balance(test_util.PublicHash(n)); err != nil {
+		t.Fatal(err)
+	} else if !balance.Equal(amount.NewCoinAmount(am, 0)) {
+		t.Errorf("expected the balance %d of %d but %v", am, n, balance)
+	}
+	if list, err := idx.UTXOs(test_util.PublicHash(n)); err != nil {
+		t.Fatal(err)
+	} else if len(list) != count {
+		t.Errorf("expected %d utxos of %d but %d", count, n, len(list))
+	}
+}
+
+func TestIndexBlock(t *testing.T) {
+	idx, closer := openTestIndex(t)
+	defer closer()
+	blocks := testBlocks()
+
+	if err := idx.IndexBlock(blocks[1]); errs.CodeOf(err) != CodeInvalidUTXOIndexHeight {
+		t.Errorf("expected the invalid height but %v", err)
+	}
+	if err := idx.IndexBlock(blocks[0]); err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, idx, 1, 15, 2)
+	if err := idx.IndexBlock(blocks[1]); err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, idx, 1, 5, 1)
+	checkBalance(t, idx, 2, 6, 1)
+
+	list, err := idx.UTXOs(test_util.PublicHash(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list[0].ID() != transaction.MarshalID(2, 0, 1) || !list[0].Amount.Equal(amount.NewCoinAmount(6, 0)) {
+		t.Errorf("unexpected utxo %d %v", list[0].ID(), list[0].Amount)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	idx, closer := openTestIndex(t)
+	defer closer()
+	provider := &test_util.Provider{Blocks: testBlocks()}
+
+	if err := idx.Sync(provider); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Rollback(1); err != nil {
+		t.Fatal(err)
+	}
+	if height, _ := idx.Height(); height != 1 {
+		t.Errorf("expected the height 1 but %d", height)
+	}
+	checkBalance(t, idx, 1, 15, 2)
+	checkBalance(t, idx, 2, 0, 0)
+
+	if err := idx.Rollback(0); err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, idx, 1, 0, 0)
+
+	if err := idx.Sync(provider); err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, idx, 1, 5, 1)
+	checkBalance(t, idx, 2, 6, 1)
+}
+
+func TestRebuild(t *testing.T) {
+	idx, closer := openTestIndex(t)
+	defer closer()
+	provider := &test_util.Provider{Blocks: testBlocks()}
+
+	genesis := data.NewContextData(nil, nil)
+	genesis.CreatedUTXOMap[transaction.MarshalID(0, 0, 0)] = testTxOut(3, 7)
+	if err := idx.IndexBlock(provider.Blocks[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.IndexGenesis(genesis); errs.CodeOf(err) != CodeInvalidUTXOIndexHeight {
+		t.Errorf("expected the invalid height but %v", err)
+	}
+	if err := idx.Rebuild(genesis, provider); err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, idx, 3, 7, 1)
+	checkBalance(t, idx, 1, 5, 1)
+	if height, _ := idx.Height(); height != 2 {
+		t.Errorf("expected the height 2 but %d", height)
+	}
+}
+
+func TestReorg(t *testing.T) {
+	idx, closer := openTestIndex(t)
+	defer closer()
+	provider := &test_util.Provider{Blocks: testBlocks()}
+	if err := idx.Sync(provider); err != nil {
+		t.Fatal(err)
+	}
+
+	// the block of the height 2 is replaced by a block that withdraws 3 to C
+	replaced := &block.Block{
+		Header: block.Header{Height_: 2, Timestamp: 1},
+		Body: block.Body{Transactions: []transaction.Transaction{&account_tx.Withdraw{
+			Vout: []*transaction.TxOut{testTxOut(3, 3)},
+		}}},
+	}
+	if err := idx.IndexBlock(replaced); err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, idx, 1, 15, 2)
+	checkBalance(t, idx, 2, 0, 0)
+	checkBalance(t, idx, 3, 3, 1)
+	if h, has, err := idx.Hash(2); err != nil || !has || !h.Equal(replaced.Header.Hash()) {
+		t.Errorf("expected the hash of the replaced block but %v %v %v", h, has, err)
+	}
+	if err := idx.IndexBlock(replaced); err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, idx, 3, 3, 1)
+
+	// the blocks of the provider replace the indexed blocks again
+	if err := idx.Sync(provider); err != nil {
+		t.Fatal(err)
+	}
+	checkBalance(t, idx, 1, 5, 1)
+	checkBalance(t, idx, 2, 6, 1)
+	checkBalance(t, idx, 3, 0, 0)
+}
+
+func TestCorruptedHeight(t *testing.T) {
+	idx, closer := openTestIndex(t)
+	defer closer()
+	batch := store.NewBatch()
+	batch.Set(tagHeight, []byte{1, 2})
+	if err := idx.st.Apply(batch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.Height(); errs.CodeOf(err) != store.CodeCorruptedStore {
+		t.Errorf("expected the corrupted store but %v", err)
+	}
+	if err := idx.IndexBlock(testBlocks()[0]); errs.CodeOf(err) != store.CodeCorruptedStore {
+		t.Errorf("expected the corrupted store but %v", err)
+	}
+}
@@ -1,19 +1,20 @@
 package utxo_tx
 
 import (
-	"errors"
+	"github.com/fletaio/extension/errs"
 )
 
 // utxo_tx errors
 var (
-	ErrInvalidTxInCount            = errors.New("invalid txin count")
-	ErrInvalidTxOutCount           = errors.New("invalid txout count")
-	ErrInvalidTransactionSignature = errors.New("invalid transaction signature")
-	ErrInvalidOutputAmount         = errors.New("invalid output amount")
-	ErrInvalidSignerCount          = errors.New("invalid signer count")
-	ErrNotMainChain                = errors.New("not main chain")
-	ErrDustAmount                  = errors.New("dust amount")
-	ErrExistAddress                = errors.New("exist address")
-	ErrExistAccountName            = errors.New("exist account name")
-	ErrInvalidAccountName          = errors.New("invalid account name")
+	ErrInvalidTxInCount            = errs.ErrInvalidTxInCount
+	ErrInvalidTxOutCount           = errs.ErrInvalidTxOutCount
+	ErrInvalidTransactionSignature = errs.ErrInvalidTransactionSignature
+	ErrInvalidOutputAmount         = errs.ErrInvalidOutputAmount
+	ErrInvalidSignerCount          = errs.ErrInvalidSignerCount
+	ErrNotMainChain                = errs.ErrNotMainChain
+	ErrDustAmount                  = errs.ErrDustAmount
+	ErrExistAddress                = errs.ErrExistAddress
+	ErrExistAccountName            = errs.ErrExistAccountName
+	ErrInvalidAccountName          = errs.ErrInvalidAccountName
+	ErrTooLongTag                  = errs.ErrTooLongTag
 )
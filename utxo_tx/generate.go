@@ -0,0 +1,3 @@
+package utxo_tx
+
+//go:generate go run ../codec/codecgen -type=Base:binary,Assign,Deposit,OpenAccount
@@ -0,0 +1,58 @@
+package utxo_tx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+func TestTransactionJSON(t *testing.T) {
+	tran := test_util.Transactor(t, testTransactionTypes)
+	for _, src := range testTransactions() {
+		dst, err := tran.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.JSONRoundTrip(t, src.(test_util.JSONValue), dst.(test_util.JSONValue))
+		if src.Hash() != dst.Hash() {
+			t.Errorf("%T: hash mismatch after json round trip", src)
+		}
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	addr := common.NewAddress(common.NewCoordinate(3, 4), 0)
+	tests := []struct {
+		result interface{}
+		want   string
+	}{
+		{&AssignResult{UTXOs: []uint64{1, 2}}, `{"utxos":[1,2]}`},
+		{&OpenAccountResult{Address: addr, Name: "alice", UTXOs: []uint64{3}}, `{"address":"` + addr.String() + `","name":"alice","utxos":[3]}`},
+	}
+	for _, tt := range tests {
+		bs, err := json.Marshal(tt.result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(bs) != tt.want {
+			t.Errorf("%T: got %s, want %s", tt.result, bs, tt.want)
+		}
+	}
+
+	for _, name := range []string{"", "alice"} {
+		bs, err := json.Marshal(&DepositResult{To: addr, ToName: name, Amount: amount.NewCoinAmount(1, 0), UTXOs: []uint64{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := map[string]json.RawMessage{}
+		if err := json.Unmarshal(bs, &m); err != nil {
+			t.Fatal(err)
+		}
+		if _, has := m["to_name"]; has != (len(name) > 0) {
+			t.Errorf("to_name presence mismatch for %q: %s", name, bs)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package utxo_tx
+
+import (
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+)
+
+// AssignResult is the execution result of the Assign
+type AssignResult struct {
+	UTXOs []uint64 `json:"utxos"`
+}
+
+// DepositResult is the execution result of the Deposit
+type DepositResult struct {
+	To     common.Address `json:"to"`
+	ToName string         `json:"to_name,omitempty"`
+	Amount *amount.Amount `json:"amount"`
+	UTXOs  []uint64       `json:"utxos"`
+}
+
+// OpenAccountResult is the execution result of the OpenAccount
+type OpenAccountResult struct {
+	Address common.Address `json:"address"`
+	Name    string         `json:"name"`
+	UTXOs   []uint64       `json:"utxos"`
+}
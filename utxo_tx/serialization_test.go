@@ -0,0 +1,89 @@
+package utxo_tx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/core/amount"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/internal/test_util"
+)
+
+var testTransactionTypes = map[string]transaction.Type{
+	"fleta.Assign":      30,
+	"fleta.Deposit":     38,
+	"fleta.OpenAccount": 41,
+}
+
+func testBase(t transaction.Type) Base {
+	return Base{
+		Base: test_util.TxBase(t),
+		Vin: []*transaction.TxIn{
+			transaction.NewTxIn(transaction.MarshalID(10, 1, 0)),
+			transaction.NewTxIn(transaction.MarshalID(12, 0, 3)),
+		},
+	}
+}
+
+func testVout() []*transaction.TxOut {
+	vout := transaction.NewTxOut()
+	vout.Amount = amount.NewCoinAmount(4, 0)
+	for i := range vout.PublicHash {
+		vout.PublicHash[i] = byte(i)
+	}
+	return []*transaction.TxOut{vout}
+}
+
+func testTransactions() []transaction.Transaction {
+	var keyHash common.PublicHash
+	keyHash[0] = 1
+
+	return []transaction.Transaction{
+		&Assign{Base: testBase(30), Vout: testVout()},
+		&Deposit{Base: testBase(38), Vout: testVout(), Amount: amount.NewCoinAmount(1, 0), To: account_name.NewAddressDestination(common.NewAddress(common.NewCoordinate(3, 4), 0)), Tag: []byte("memo")},
+		&Deposit{Base: testBase(38), Vout: testVout(), Amount: amount.NewCoinAmount(1, 0), To: account_name.NewNameDestination("testaccount")},
+		&Deposit{Base: testBase(38), Vout: []*transaction.TxOut{}, Amount: amount.NewCoinAmount(1, 0)},
+		&OpenAccount{Base: testBase(41), Vout: testVout(), Name: "openaccount", KeyHash: keyHash},
+	}
+}
+
+func TestTransactionBinary(t *testing.T) {
+	tran := test_util.Transactor(t, testTransactionTypes)
+	for _, src := range testTransactions() {
+		dst, err := tran.NewByType(src.Type())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.BinaryRoundTrip(t, src, dst)
+		if src.Hash() != dst.Hash() {
+			t.Errorf("%T: hash mismatch after binary round trip", src)
+		}
+	}
+}
+
+func FuzzTransactionReadFrom(f *testing.F) {
+	tran := test_util.Transactor(f, testTransactionTypes)
+	for _, src := range testTransactions() {
+		var buffer bytes.Buffer
+		if _, err := src.WriteTo(&buffer); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(uint8(src.Type()), buffer.Bytes())
+	}
+	f.Fuzz(func(t *testing.T, Type uint8, bs []byte) {
+		tx, err := tran.NewByType(transaction.Type(Type))
+		if err != nil {
+			return
+		}
+		if _, err := tx.ReadFrom(bytes.NewReader(bs)); err != nil {
+			return
+		}
+		dst, err := tran.NewByType(transaction.Type(Type))
+		if err != nil {
+			t.Fatal(err)
+		}
+		test_util.BinaryRoundTrip(t, tx, dst)
+	})
+}
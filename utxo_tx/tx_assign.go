@@ -1,15 +1,14 @@
 package utxo_tx
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/transaction"
 )
@@ -27,11 +26,8 @@ func init() {
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*Assign)
-		if len(tx.Vin) == 0 {
-			return ErrInvalidTxInCount
-		}
-		if len(signers) > 1 {
-			return ErrInvalidSignerCount
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
 		}
 
 		for _, vin := range tx.Vin {
@@ -39,22 +35,22 @@ func init() {
 				return err
 			} else {
 				if !utxo.PublicHash.Equal(signers[0]) {
-					return ErrInvalidTransactionSignature
+					return errs.WrapValue(ErrInvalidTransactionSignature, tx.Type(), "vin", utxo.PublicHash, signers[0])
 				}
 			}
 		}
-
-		for _, vout := range tx.Vout {
-			if vout.Amount.Less(amount.COIN.DivC(10)) {
-				return ErrDustAmount
-			}
-		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*Assign)
+		policy := chain.PolicyOf(ctx.ChainCoord())
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
 
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		insum := amount.NewCoinAmount(0, 0)
 		for _, vin := range tx.Vin {
@@ -65,26 +61,37 @@ func init() {
 				if err := ctx.DeleteUTXO(vin.ID()); err != nil {
 					return nil, err
 				}
+				if err := em.UTXOSpent(vin.ID(), utxo.Amount); err != nil {
+					return nil, err
+				}
 			}
 		}
 
 		outsum := Fee.Clone()
+		ids := make([]uint64, 0, len(tx.Vout))
 		for n, vout := range tx.Vout {
-			if vout.Amount.Less(amount.COIN.DivC(10)) {
-				return nil, ErrDustAmount
+			if policy.IsDust(vout.Amount) {
+				return nil, errs.WrapValue(ErrDustAmount, tx.Type(), "vout", policy.DustAmount, vout.Amount)
 			}
 			outsum = outsum.Add(vout.Amount)
-			if err := ctx.CreateUTXO(transaction.MarshalID(coord.Height, coord.Index, uint16(n)), vout); err != nil {
+			id := transaction.MarshalID(coord.Height, coord.Index, uint16(n))
+			if err := ctx.CreateUTXO(id, vout); err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+			if err := em.UTXOCreated(id, vout); err != nil {
 				return nil, err
 			}
 		}
 
 		if !insum.Equal(outsum) {
-			return nil, ErrInvalidOutputAmount
+			return nil, errs.WrapValue(ErrInvalidOutputAmount, tx.Type(), "vout", insum, outsum)
 		}
 
 		ctx.Commit(sn)
-		return nil, nil
+		return &AssignResult{
+			UTXOs: ids,
+		}, nil
 	})
 }
 
@@ -92,7 +99,7 @@ func init() {
 // It is used to transfer coins between keys
 type Assign struct {
 	Base
-	Vout []*transaction.TxOut
+	Vout []*transaction.TxOut `codec:"vout"`
 }
 
 // Hash returns the hash value of it
@@ -100,100 +107,29 @@ func (tx *Assign) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *Assign) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteUint8(w, uint8(len(tx.Vout))); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-		for _, v := range tx.Vout {
-			if n, err := v.WriteTo(w); err != nil {
-				return wrote, err
-			} else {
-				wrote += n
-			}
-		}
-	}
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *Assign) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *Assign) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if Len, n, err := util.ReadUint8(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Vout = make([]*transaction.TxOut, 0, Len)
-		for i := 0; i < int(Len); i++ {
-			vout := transaction.NewTxOut()
-			if n, err := vout.ReadFrom(r); err != nil {
-				return read, err
-			} else {
-				read += n
-				tx.Vout = append(tx.Vout, vout)
-			}
-		}
-	}
-	return read, nil
+// OutputCount returns the number of the outputs that it makes
+func (tx *Assign) OutputCount() int {
+	return len(tx.Vout)
 }
 
-// MarshalJSON is a marshaler function
-func (tx *Assign) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *Assign) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	policy := chain.PolicyOf(coord)
+	if len(tx.Vin) == 0 {
+		return errs.WrapValue(ErrInvalidTxInCount, tx.Type(), "vin", "at least 1", 0)
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+	if len(signers) != 1 {
+		return errs.WrapValue(ErrInvalidSignerCount, tx.Type(), "signers", 1, len(signers))
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"vin":`)
-	buffer.WriteString(`[`)
-	for i, vin := range tx.Vin {
-		if i > 0 {
-			buffer.WriteString(`,`)
-		}
-		if bs, err := json.Marshal(vin.ID()); err != nil {
-			return nil, err
-		} else {
-			buffer.Write(bs)
-		}
-	}
-	buffer.WriteString(`]`)
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"vout":`)
-	buffer.WriteString(`[`)
-	for i, vout := range tx.Vout {
-		if i > 0 {
-			buffer.WriteString(`,`)
-		}
-		if bs, err := vout.MarshalJSON(); err != nil {
-			return nil, err
-		} else {
-			buffer.Write(bs)
+	for _, vout := range tx.Vout {
+		if policy.IsDust(vout.Amount) {
+			return errs.WrapValue(ErrDustAmount, tx.Type(), "vout", policy.DustAmount, vout.Amount)
 		}
 	}
-	buffer.WriteString(`]`)
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	return nil
 }
@@ -1,16 +1,16 @@
 package utxo_tx
 
 import (
-	"bytes"
-	"encoding/hex"
-	"encoding/json"
-	"io"
-
 	"github.com/fletaio/core/amount"
+	"github.com/fletaio/extension/account_name"
+	"github.com/fletaio/extension/chain"
+	"github.com/fletaio/extension/errs"
+	"github.com/fletaio/extension/event_def"
+	"github.com/fletaio/extension/fee"
+	"github.com/fletaio/extension/memo"
 
 	"github.com/fletaio/common"
 	"github.com/fletaio/common/hash"
-	"github.com/fletaio/common/util"
 	"github.com/fletaio/core/data"
 	"github.com/fletaio/core/transaction"
 )
@@ -24,18 +24,16 @@ func init() {
 				},
 				Vin: []*transaction.TxIn{},
 			},
-			Vout: []*transaction.TxOut{},
+			Vout:   []*transaction.TxOut{},
+			Amount: amount.NewCoinAmount(0, 0),
 		}
 	}, func(loader data.Loader, t transaction.Transaction, signers []common.PublicHash) error {
 		tx := t.(*Deposit)
-		if len(tx.Vin) == 0 {
-			return ErrInvalidTxInCount
-		}
-		if len(signers) > 1 {
-			return ErrInvalidSignerCount
+		if err := tx.CheckStateless(loader.ChainCoord(), signers); err != nil {
+			return err
 		}
-		if tx.Amount.Less(amount.COIN.DivC(10)) {
-			return ErrDustAmount
+		if _, err := tx.To.Resolve(loader); err != nil {
+			return errs.Wrap(err, tx.Type(), "to")
 		}
 
 		for _, vin := range tx.Vin {
@@ -43,22 +41,25 @@ func init() {
 				return err
 			} else {
 				if !utxo.PublicHash.Equal(signers[0]) {
-					return ErrInvalidTransactionSignature
+					return errs.WrapValue(ErrInvalidTransactionSignature, tx.Type(), "vin", utxo.PublicHash, signers[0])
 				}
 			}
 		}
-
-		for _, vout := range tx.Vout {
-			if vout.Amount.Less(amount.COIN.DivC(10)) {
-				return ErrDustAmount
-			}
-		}
 		return nil
 	}, func(ctx *data.Context, Fee *amount.Amount, t transaction.Transaction, coord *common.Coordinate) (interface{}, error) {
 		tx := t.(*Deposit)
+		policy := chain.PolicyOf(ctx.ChainCoord())
+		Fee, err := fee.Required(ctx.ChainCoord(), Fee, t)
+		if err != nil {
+			return nil, err
+		}
+		if len(tx.Tag) > policy.MaxTagLength {
+			return nil, errs.WrapValue(ErrTooLongTag, tx.Type(), "tag", policy.MaxTagLength, len(tx.Tag))
+		}
 
 		sn := ctx.Snapshot()
 		defer ctx.Revert(sn)
+		em := event_def.NewEmitter(ctx, coord)
 
 		insum := amount.NewCoinAmount(0, 0)
 		for _, vin := range tx.Vin {
@@ -69,33 +70,54 @@ func init() {
 				if err := ctx.DeleteUTXO(vin.ID()); err != nil {
 					return nil, err
 				}
+				if err := em.UTXOSpent(vin.ID(), utxo.Amount); err != nil {
+					return nil, err
+				}
 			}
 		}
 
 		outsum := Fee.Clone()
 		outsum = outsum.Add(tx.Amount)
+		ids := make([]uint64, 0, len(tx.Vout))
 		for n, vout := range tx.Vout {
-			if vout.Amount.Less(amount.COIN.DivC(10)) {
-				return nil, ErrDustAmount
+			if policy.IsDust(vout.Amount) {
+				return nil, errs.WrapValue(ErrDustAmount, tx.Type(), "vout", policy.DustAmount, vout.Amount)
 			}
 			outsum = outsum.Add(vout.Amount)
-			if err := ctx.CreateUTXO(transaction.MarshalID(coord.Height, coord.Index, uint16(n)), vout); err != nil {
+			id := transaction.MarshalID(coord.Height, coord.Index, uint16(n))
+			if err := ctx.CreateUTXO(id, vout); err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+			if err := em.UTXOCreated(id, vout); err != nil {
 				return nil, err
 			}
 		}
 
 		if !insum.Equal(outsum) {
-			return nil, ErrInvalidOutputAmount
+			return nil, errs.WrapValue(ErrInvalidOutputAmount, tx.Type(), "vout", insum, outsum)
 		}
 
-		toAcc, err := ctx.Account(tx.To)
+		to, err := tx.To.Resolve(ctx)
+		if err != nil {
+			return nil, errs.Wrap(err, tx.Type(), "to")
+		}
+		toAcc, err := ctx.Account(to)
 		if err != nil {
 			return nil, err
 		}
 		toAcc.AddBalance(tx.Amount)
+		if err := em.Transfer(common.Address{}, to, tx.Amount); err != nil {
+			return nil, err
+		}
 
 		ctx.Commit(sn)
-		return nil, nil
+		return &DepositResult{
+			To:     to,
+			ToName: tx.To.Name,
+			Amount: tx.Amount.Clone(),
+			UTXOs:  ids,
+		}, nil
 	})
 }
 
@@ -103,10 +125,10 @@ func init() {
 // It is used to add balance to the account from UTXOs
 type Deposit struct {
 	Base
-	Vout   []*transaction.TxOut
-	Amount *amount.Amount
-	To     common.Address
-	Tag    []byte
+	Vout   []*transaction.TxOut     `codec:"vout"`
+	Amount *amount.Amount           `codec:"amount"`
+	To     account_name.Destination `codec:"to"` // an address or an account name that is resolved at the execution
+	Tag    []byte                   `codec:"tag,max=256,memo"`
 }
 
 // Hash returns the hash value of it
@@ -114,154 +136,45 @@ func (tx *Deposit) Hash() hash.Hash256 {
 	return hash.DoubleHashByWriterTo(tx)
 }
 
-// WriteTo is a serialization function
-func (tx *Deposit) WriteTo(w io.Writer) (int64, error) {
-	var wrote int64
-	if n, err := tx.Base.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteUint8(w, uint8(len(tx.Vout))); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-		for _, v := range tx.Vout {
-			if n, err := v.WriteTo(w); err != nil {
-				return wrote, err
-			} else {
-				wrote += n
-			}
-		}
-	}
-	if n, err := tx.Amount.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := tx.To.WriteTo(w); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	if n, err := util.WriteBytes(w, tx.Tag); err != nil {
-		return wrote, err
-	} else {
-		wrote += n
-	}
-	return wrote, nil
+// Size returns the serialized byte size of it
+func (tx *Deposit) Size() (int64, error) {
+	return fee.Size(tx)
 }
 
-// ReadFrom is a deserialization function
-func (tx *Deposit) ReadFrom(r io.Reader) (int64, error) {
-	var read int64
-	if n, err := tx.Base.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if Len, n, err := util.ReadUint8(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Vout = make([]*transaction.TxOut, 0, Len)
-		for i := 0; i < int(Len); i++ {
-			vout := transaction.NewTxOut()
-			if n, err := vout.ReadFrom(r); err != nil {
-				return read, err
-			} else {
-				read += n
-				tx.Vout = append(tx.Vout, vout)
-			}
-		}
-	}
-	if n, err := tx.Amount.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if n, err := tx.To.ReadFrom(r); err != nil {
-		return read, err
-	} else {
-		read += n
-	}
-	if bs, n, err := util.ReadBytes(r); err != nil {
-		return read, err
-	} else {
-		read += n
-		tx.Tag = bs
-	}
-	return read, nil
+// OutputCount returns the number of the outputs that it makes
+func (tx *Deposit) OutputCount() int {
+	return len(tx.Vout) + 1
 }
 
-// MarshalJSON is a marshaler function
-func (tx *Deposit) MarshalJSON() ([]byte, error) {
-	var buffer bytes.Buffer
-	buffer.WriteString(`{`)
-	buffer.WriteString(`"type":`)
-	if bs, err := json.Marshal(tx.Type_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+// CheckStateless validates the rules of it that do not depend on the state of the chain of the coordinate
+func (tx *Deposit) CheckStateless(coord *common.Coordinate, signers []common.PublicHash) error {
+	policy := chain.PolicyOf(coord)
+	if len(tx.Vin) == 0 {
+		return errs.WrapValue(ErrInvalidTxInCount, tx.Type(), "vin", "at least 1", 0)
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"timestamp":`)
-	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+	if len(signers) != 1 {
+		return errs.WrapValue(ErrInvalidSignerCount, tx.Type(), "signers", 1, len(signers))
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"vin":`)
-	buffer.WriteString(`[`)
-	for i, vin := range tx.Vin {
-		if i > 0 {
-			buffer.WriteString(`,`)
-		}
-		if bs, err := json.Marshal(vin.ID()); err != nil {
-			return nil, err
-		} else {
-			buffer.Write(bs)
-		}
-	}
-	buffer.WriteString(`]`)
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"vout":`)
-	buffer.WriteString(`[`)
-	for i, vout := range tx.Vout {
-		if i > 0 {
-			buffer.WriteString(`,`)
-		}
-		if bs, err := vout.MarshalJSON(); err != nil {
-			return nil, err
-		} else {
-			buffer.Write(bs)
-		}
+	if policy.IsDust(tx.Amount) {
+		return errs.WrapValue(ErrDustAmount, tx.Type(), "amount", policy.DustAmount, tx.Amount)
 	}
-	buffer.WriteString(`]`)
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"amount":`)
-	if bs, err := tx.Amount.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+	if len(tx.Tag) > policy.MaxTagLength {
+		return errs.WrapValue(ErrTooLongTag, tx.Type(), "tag", policy.MaxTagLength, len(tx.Tag))
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"to":`)
-	if bs, err := tx.To.MarshalJSON(); err != nil {
-		return nil, err
-	} else {
-		buffer.Write(bs)
+	for _, vout := range tx.Vout {
+		if policy.IsDust(vout.Amount) {
+			return errs.WrapValue(ErrDustAmount, tx.Type(), "vout", policy.DustAmount, vout.Amount)
+		}
 	}
-	buffer.WriteString(`,`)
-	buffer.WriteString(`"tag":`)
-	if len(tx.Tag) == 0 {
-		buffer.WriteString(`null`)
-	} else {
-		buffer.WriteString(`"`)
-		buffer.WriteString(hex.EncodeToString(tx.Tag))
-		buffer.WriteString(`"`)
+	return nil
+}
+
+// SetEncryptedTag sets the tag to the memo that has the text encrypted to the public key of the recipient
+func (tx *Deposit) SetEncryptedTag(pubkey common.PublicKey, text []byte) error {
+	tag, err := memo.EncryptTo(pubkey, text)
+	if err != nil {
+		return err
 	}
-	buffer.WriteString(`}`)
-	return buffer.Bytes(), nil
+	tx.Tag = tag
+	return nil
 }
@@ -0,0 +1,193 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package utxo_tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/fletaio/common"
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+	"github.com/fletaio/extension/json_util"
+)
+
+// WriteTo is a serialization function
+func (tx *OpenAccount) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tx.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckItemCount("OpenAccount.Vout", uint64(len(tx.Vout)), 255); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteUint8(w, uint8(len(tx.Vout))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+		for _, v := range tx.Vout {
+			if n, err := v.WriteTo(w); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	if err := codec.CheckByteLength("OpenAccount.Name", uint64(len(tx.Name)), 64); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteString(w, tx.Name); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if n, err := tx.KeyHash.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tx *OpenAccount) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tx.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if Len, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.Vout = make([]*transaction.TxOut, 0, Len)
+		for i := 0; i < int(Len); i++ {
+			v := transaction.NewTxOut()
+			if n, err := v.ReadFrom(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				tx.Vout = append(tx.Vout, v)
+			}
+		}
+	}
+	if v, n, err := codec.ReadString(r, "OpenAccount.Name", 64); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.Name = v
+	}
+	if n, err := tx.KeyHash.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	return read, nil
+}
+
+// MarshalJSON is a marshaler function
+func (tx *OpenAccount) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(`{`)
+	buffer.WriteString(`"type":`)
+	if bs, err := json.Marshal(tx.Type_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"timestamp":`)
+	if bs, err := json.Marshal(tx.Timestamp_); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"vin":`)
+	buffer.WriteString(`[`)
+	for i, v := range tx.Vin {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := json.Marshal(v.ID()); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"vout":`)
+	buffer.WriteString(`[`)
+	for i, v := range tx.Vout {
+		if i > 0 {
+			buffer.WriteString(`,`)
+		}
+		if bs, err := v.MarshalJSON(); err != nil {
+			return nil, err
+		} else {
+			buffer.Write(bs)
+		}
+	}
+	buffer.WriteString(`]`)
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"name":`)
+	if bs, err := json.Marshal(tx.Name); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`,`)
+	buffer.WriteString(`"key_hash":`)
+	if bs, err := tx.KeyHash.MarshalJSON(); err != nil {
+		return nil, err
+	} else {
+		buffer.Write(bs)
+	}
+	buffer.WriteString(`}`)
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON is a unmarshaler function
+func (tx *OpenAccount) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		Type      transaction.Type  `json:"type"`
+		Timestamp uint64            `json:"timestamp"`
+		Vin       []uint64          `json:"vin"`
+		Vout      []json.RawMessage `json:"vout"`
+		Name      string            `json:"name"`
+		KeyHash   string            `json:"key_hash"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	tx.Type_ = v.Type
+	tx.Timestamp_ = v.Timestamp
+	if err := codec.CheckItemCount("Base.Vin", uint64(len(v.Vin)), 255); err != nil {
+		return err
+	}
+	tx.Vin = json_util.ParseVin(v.Vin)
+	if err := codec.CheckItemCount("OpenAccount.Vout", uint64(len(v.Vout)), 255); err != nil {
+		return err
+	}
+	if list, err := json_util.ParseVout(v.Vout); err != nil {
+		return err
+	} else {
+		tx.Vout = list
+	}
+	if err := codec.CheckByteLength("OpenAccount.Name", uint64(len(v.Name)), 64); err != nil {
+		return err
+	}
+	tx.Name = v.Name
+	if pubhash, err := common.ParsePublicHash(v.KeyHash); err != nil {
+		return err
+	} else {
+		tx.KeyHash = pubhash
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Code generated by codecgen. DO NOT EDIT.
+
+package utxo_tx
+
+import (
+	"io"
+
+	"github.com/fletaio/common/util"
+	"github.com/fletaio/core/transaction"
+	"github.com/fletaio/extension/codec"
+)
+
+// WriteTo is a serialization function
+func (tx *Base) WriteTo(w io.Writer) (int64, error) {
+	var wrote int64
+	if n, err := tx.Base.WriteTo(w); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+	}
+	if err := codec.CheckItemCount("Base.Vin", uint64(len(tx.Vin)), 255); err != nil {
+		return wrote, err
+	}
+	if n, err := util.WriteUint8(w, uint8(len(tx.Vin))); err != nil {
+		return wrote, err
+	} else {
+		wrote += n
+		for _, v := range tx.Vin {
+			if n, err := v.WriteTo(w); err != nil {
+				return wrote, err
+			} else {
+				wrote += n
+			}
+		}
+	}
+	return wrote, nil
+}
+
+// ReadFrom is a deserialization function
+func (tx *Base) ReadFrom(r io.Reader) (int64, error) {
+	var read int64
+	if n, err := tx.Base.ReadFrom(r); err != nil {
+		return read, err
+	} else {
+		read += n
+	}
+	if Len, n, err := util.ReadUint8(r); err != nil {
+		return read, err
+	} else {
+		read += n
+		tx.Vin = make([]*transaction.TxIn, 0, Len)
+		for i := 0; i < int(Len); i++ {
+			v := &transaction.TxIn{}
+			if n, err := v.ReadFrom(r); err != nil {
+				return read, err
+			} else {
+				read += n
+				tx.Vin = append(tx.Vin, v)
+			}
+		}
+	}
+	return read, nil
+}